@@ -0,0 +1,145 @@
+package docx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// aggregates compute a value of a collection, like [SUM items.amount] or [COUNT items]
+var aggregates = map[string]func(values []interface{}) (interface{}, error){
+	"COUNT": func(values []interface{}) (interface{}, error) { return len(values), nil },
+	"SUM": func(values []interface{}) (interface{}, error) {
+		if len(values) == 0 {
+			return 0, nil
+		}
+		return reduceNumbers(values, add)
+	},
+	"AVG": func(values []interface{}) (interface{}, error) {
+		if len(values) == 0 {
+			return "", nil
+		}
+		sum, err := reduceNumbers(values, add)
+		if m, ok := sum.(Money); ok {
+			m.Amount /= float64(len(values))
+			return m, err
+		}
+		if n, ok := sum.(float64); ok {
+			return n / float64(len(values)), err
+		}
+		return sum, err
+	},
+	"MIN": func(values []interface{}) (interface{}, error) {
+		return reduceNumbers(values, func(acc, n float64) float64 {
+			if n < acc {
+				return n
+			}
+			return acc
+		})
+	},
+	"MAX": func(values []interface{}) (interface{}, error) {
+		return reduceNumbers(values, func(acc, n float64) float64 {
+			if n > acc {
+				return n
+			}
+			return acc
+		})
+	},
+}
+
+// parseAggregate splits a placeholder like "SUM items.amount" to a function and a path
+func parseAggregate(name string) (string, string, bool) {
+	fields := strings.Fields(name)
+	if len(fields) != 2 || aggregates[fields[0]] == nil {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// aggregate computes a function of values found at a path through collections. Values come from
+// the same collections which repeated content is made of, so totals always match detail lines
+func (s *scope) aggregate(fn, path string) (interface{}, bool, error) {
+	values, ok := s.collect(path)
+	if !ok {
+		return nil, false, nil
+	}
+	v, err := aggregates[fn](values)
+	if err != nil {
+		return nil, false, fmt.Errorf("Invalid %s %s: %v", fn, path, err)
+	}
+	return v, true, nil
+}
+
+// collect returns values at a dotted path, collections on the way are flattened,
+// e.g. "orders.lines.amount" returns amounts of lines of all orders
+func (s *scope) collect(path string) ([]interface{}, bool) {
+	keys := strings.Split(path, ".")
+	v, ok := s.find(keys[0])
+	if !ok {
+		return nil, false
+	}
+	values := elements(v)
+	for _, key := range keys[1:] {
+		var next []interface{}
+		for _, v := range values {
+			if f, ok := field(v, key); ok {
+				next = append(next, elements(f)...)
+			}
+		}
+		values = next
+	}
+	var found []interface{}
+	for _, v := range values {
+		if v != nil {
+			found = append(found, v)
+		}
+	}
+	return found, true
+}
+
+// elements returns items of a slice or an array, other values are returned as they are
+func elements(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return []interface{}{v}
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+func add(acc, n float64) float64 { return acc + n }
+
+// reduceNumbers combines numbers starting with the first one, Money of a single currency stays Money.
+// The result of no numbers is empty
+func reduceNumbers(values []interface{}, fn func(acc, n float64) float64) (interface{}, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	var acc float64
+	var currency *Money
+	for i, v := range values {
+		n, err := number(v)
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := v.(Money); ok && (i == 0 || currency != nil && currency.Currency == m.Currency) {
+			currency = &m
+		} else {
+			currency = nil
+		}
+		if i == 0 {
+			acc = n
+		} else {
+			acc = fn(acc, n)
+		}
+	}
+	if currency != nil {
+		m := *currency
+		m.Amount = acc
+		return m, nil
+	}
+	return acc, nil
+}
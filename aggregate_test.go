@@ -0,0 +1,36 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	body := p("[COUNT items] items, [SUM items.qty] pieces for [SUM items.price|money:EUR]") +
+		p("Cheapest [MIN items.price], dearest [MAX items.price], average [AVG items.price]") +
+		p("[SUM orders.lines.amount] in [COUNT orders.lines] lines, [SUM missing.amount], [COUNT empty], [AVG empty.x].")
+	dict := Dict{
+		"items": []Dict{
+			{"name": "Apple", "qty": 2, "price": 1.5},
+			{"name": "Pear", "qty": 1, "price": "2.25"},
+			{"name": "Plum", "qty": 3, "price": 0.75},
+		},
+		"orders": []map[string]interface{}{
+			{"lines": []Dict{{"amount": Money{Amount: 10, Currency: "USD"}}, {"amount": Money{Amount: 5.5, Currency: "USD"}}}},
+			{"lines": []Dict{{"amount": Money{Amount: 1, Currency: "USD"}}}},
+		},
+		"empty": []Dict{},
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	want := p("3 items, 6 pieces for €4.50") +
+		p("Cheapest 0.75, dearest 2.25, average 1.5") +
+		p("$16.50 in 3 lines, [SUM missing.amount], 0, .")
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+
+	doc := newTestDocx(t, p("[SUM items.name]"), nil).ReplaceDict(dict)
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for a sum of text")
+	}
+}
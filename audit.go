@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// now returns the current time, tests replace it
+var now = time.Now
+
+// audit is who renders documents
+type audit struct {
+	user string
+}
+
+// Audit records into custom properties of generated documents who rendered them (RenderedBy),
+// when (RenderedAt, a date in UTC) and with what data (DataHash, SHA-256 of the dictionary
+// encoded as JSON, so the data can be verified later without storing it in the document)
+func (doc *Docx) Audit(user string) *Docx {
+	doc.audit = &audit{user: user}
+	return doc
+}
+
+// auditProperties returns custom properties of the audit trail
+func (doc *Docx) auditProperties() ([]customProperty, error) {
+	if doc.audit == nil {
+		return nil, nil
+	}
+	hash, err := dataHash(doc.dict)
+	if err != nil {
+		return nil, err
+	}
+	return []customProperty{
+		{name: "RenderedBy", value: doc.audit.user},
+		{name: "RenderedAt", value: doc.now().UTC().Format(time.RFC3339), typ: "vt:filetime"},
+		{name: "DataHash", value: hash},
+	}, nil
+}
+
+// dataHash returns a hash of a dictionary, keys of maps are sorted by JSON encoding.
+// Values which can't be encoded as JSON (like functions) are an error, formatting them
+// would hash addresses which differ between runs
+func dataHash(dict Dict) (string, error) {
+	data, err := json.Marshal(dict)
+	if err != nil {
+		return "", fmt.Errorf("Data of the audit trail can't be hashed: %v", err)
+	}
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:]), nil
+}
@@ -0,0 +1,44 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600)) }
+	hash := func(dict Dict) string {
+		t.Helper()
+		h, err := dataHash(dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	dict := Dict{"name": "ACME", "total": 12.5}
+	doc := newTestDocx(t, p("[name]"), nil).ReplaceDict(dict).Audit("alice@example.com")
+	custom := renderParts(t, doc)["docProps/custom.xml"]
+	for _, want := range []string{
+		`name="RenderedBy"><vt:lpwstr>alice@example.com</vt:lpwstr>`,
+		`name="RenderedAt"><vt:filetime>2024-03-01T09:30:00Z</vt:filetime>`,
+		`name="DataHash"><vt:lpwstr>` + hash(dict) + `</vt:lpwstr>`,
+	} {
+		if !strings.Contains(custom, want) {
+			t.Errorf("%s not found in %s", want, custom)
+		}
+	}
+	if hash(dict) == hash(Dict{"name": "ACME", "total": 12.6}) {
+		t.Error("Different data have the same hash")
+	}
+	if hash(Dict{"a": 1, "b": 2}) != hash(Dict{"b": 2, "a": 1}) {
+		t.Error("Hash depends on order of keys")
+	}
+	var buf bytes.Buffer
+	doc = newTestDocx(t, p("[name]"), nil).ReplaceDict(Dict{"name": "ACME", "f": func() {}}).Audit("alice")
+	if _, err := doc.WriteTo(&buf); err == nil || !strings.Contains(err.Error(), "can't be hashed") {
+		t.Errorf("Expected an error of a function in the data, got %v", err)
+	}
+}
@@ -0,0 +1,369 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// blockKinds are names of supported blocks, like [#if condition]...[/if] or [#each items]...[/each]
+var blockKinds = map[string]bool{
+	"if":   true,
+	"each": true,
+	// sections of Mustache templates
+	"section":  true,
+	"inverted": true,
+}
+
+// blockContainers are elements which children are paragraphs, tables or table rows,
+// a block spanning several paragraphs always consists of children of one of them
+var blockContainers = map[string]bool{
+	"w:body": true, "w:tc": true, "w:tbl": true, "w:sdtContent": true, "w:txbxContent": true,
+	"w:hdr": true, "w:ftr": true, "w:footnote": true, "w:endnote": true, "w:comment": true,
+	"w:docPartBody": true,
+}
+
+// marker is a start or an end of a block found in paragraph text
+type marker struct {
+	para *node
+	ph   placeholder
+	kind string
+	// arg is the rest of a starting marker, like a condition of [#if condition]
+	arg string
+	end bool
+	// alternative is set for [#else] which separates content kept when the condition is false
+	alternative bool
+	// trimBefore and trimAfter are set by a dash like [-#if x] or [/if-]
+	// and remove an empty paragraph preceding or following a marker
+	trimBefore, trimAfter bool
+}
+
+// parseMarker checks if a placeholder starts or ends a block
+func parseMarker(ph placeholder) (marker, bool) {
+	name := strings.TrimSpace(ph.name)
+	m := marker{ph: ph}
+	if strings.HasPrefix(name, "-") {
+		m.trimBefore = true
+		name = strings.TrimSpace(name[1:])
+	}
+	if strings.HasSuffix(name, "-") {
+		m.trimAfter = true
+		name = strings.TrimSpace(name[:len(name)-1])
+	}
+	switch {
+	case strings.HasPrefix(name, "#"):
+		fields := strings.SplitN(name[1:], " ", 2)
+		m.kind = fields[0]
+		if len(fields) > 1 {
+			m.arg = strings.TrimSpace(fields[1])
+		}
+	case strings.HasPrefix(name, "/"):
+		m.kind = strings.TrimSpace(name[1:])
+		m.end = true
+	default:
+		return m, false
+	}
+	if m.kind == "else" && !m.end && m.arg == "" {
+		m.alternative = true
+		return m, true
+	}
+	return m, blockKinds[m.kind]
+}
+
+// markers returns all block markers of a part in document order
+func (doc *Docx) markers(root *node) []marker {
+	var found []marker
+	for _, p := range root.find("w:p") {
+		para := newParagraph(p)
+		for _, ph := range doc.placeholders(para.text) {
+			if m, ok := parseMarker(ph); ok && !para.crossesField(ph.start, ph.end) {
+				m.para = p
+				found = append(found, m)
+			}
+		}
+	}
+	return found
+}
+
+// expandBlocks evaluates all blocks of a part, starting from the outermost
+func (ctx *renderContext) expandBlocks() error {
+	return ctx.expandBlocksOf(ctx.root)
+}
+
+// expandBlocksOf evaluates all blocks of an element
+func (ctx *renderContext) expandBlocksOf(root *node) error {
+	for {
+		markers := ctx.doc.markers(root)
+		if len(markers) == 0 {
+			return nil
+		}
+		start := markers[0]
+		if start.end {
+			return fmt.Errorf("Unexpected end of block %s", start.ph.name)
+		}
+		if start.alternative {
+			return fmt.Errorf("Unexpected %s outside of a block", start.ph.name)
+		}
+		end, ok := matchingEnd(start, markers[1:])
+		if !ok {
+			return fmt.Errorf("Unclosed block %s", start.ph.name)
+		}
+		if err := ctx.expandBlock(start, alternativeOf(markers[1:]), end); err != nil {
+			return err
+		}
+	}
+}
+
+// matchingEnd finds the marker which closes a block, skipping nested blocks
+func matchingEnd(start marker, markers []marker) (marker, bool) {
+	depth := 0
+	for _, m := range markers {
+		if m.alternative {
+			continue
+		}
+		if !m.end {
+			depth++
+			continue
+		}
+		if depth > 0 {
+			depth--
+			continue
+		}
+		return m, m.kind == start.kind
+	}
+	return marker{}, false
+}
+
+// alternativeOf returns [#else] of a block given markers following its start
+func alternativeOf(markers []marker) *marker {
+	depth := 0
+	for i, m := range markers {
+		switch {
+		case m.alternative && depth == 0:
+			return &markers[i]
+		case m.alternative:
+		case !m.end:
+			depth++
+		case depth == 0:
+			return nil
+		default:
+			depth--
+		}
+	}
+	return nil
+}
+
+// expandBlock keeps content of a block or its alternative, later content is changed first
+// so positions of earlier markers stay valid
+func (ctx *renderContext) expandBlock(start marker, alternative *marker, end marker) error {
+	switch start.kind {
+	case "if":
+		keep, err := ctx.scopeOf(start.para).condition(start.arg)
+		if err != nil {
+			return err
+		}
+		switch {
+		case alternative == nil:
+			ctx.keepBlock(start, end, keep)
+		case keep:
+			ctx.keepBlock(*alternative, end, false)
+			removeMarker(start)
+		default:
+			removeMarker(end)
+			ctx.keepBlock(start, *alternative, false)
+		}
+	case "each", "section", "inverted":
+		if alternative != nil {
+			return fmt.Errorf("Unexpected %s in block %s", alternative.ph.name, start.ph.name)
+		}
+		switch start.kind {
+		case "each":
+			scopes, err := ctx.loopScopes(start)
+			if err != nil {
+				return err
+			}
+			return ctx.repeatBlock(start, end, scopes)
+		case "section":
+			return ctx.repeatBlock(start, end, ctx.sectionScopes(start))
+		}
+		v, _ := ctx.scopeOf(start.para).lookup(start.arg)
+		ctx.keepBlock(start, end, !truthy(v))
+	}
+	return nil
+}
+
+// removeMarker removes a marker of a block together with its paragraph if there's nothing else
+func removeMarker(m marker) {
+	prev, next, parent := sibling(m.para, -1), sibling(m.para, 1), m.para.parent
+	if isMarkerOnly(m) {
+		m.para.remove()
+	} else {
+		newParagraph(m.para).erase(m.ph.start, m.ph.end)
+	}
+	ensureParagraph(parent)
+	trimEmpty(prev, m.trimBefore)
+	trimEmpty(next, m.trimAfter)
+}
+
+// keepBlock removes markers of a block and its content too unless it should be kept.
+// Paragraphs which contain nothing but a marker are removed with it, so no empty lines are left
+func (ctx *renderContext) keepBlock(start, end marker, keep bool) {
+	if start.para == end.para {
+		para := newParagraph(start.para)
+		prev, next := sibling(start.para, -1), sibling(start.para, 1)
+		defer ensureParagraph(start.para.parent)
+		switch {
+		case keep:
+			para.erase(end.ph.start, end.ph.end)
+			para.erase(start.ph.start, start.ph.end)
+		case isBlank(para.text[:start.ph.start]+para.text[end.ph.end:]) && !hasContent(start.para):
+			start.para.remove()
+		default:
+			para.erase(start.ph.start, end.ph.end)
+		}
+		trimEmpty(prev, start.trimBefore)
+		trimEmpty(next, end.trimAfter)
+		return
+	}
+	first, last := blockRange(start.para, end.para)
+	prev, next := sibling(first, -1), sibling(last, 1)
+	defer ensureParagraph(first.parent)
+	startOnly := first == start.para && isMarkerOnly(start)
+	endOnly := last == end.para && isMarkerOnly(end)
+	defer trimEmpty(prev, start.trimBefore)
+	defer trimEmpty(next, end.trimAfter)
+	if keep {
+		if endOnly {
+			end.para.remove()
+		} else {
+			newParagraph(end.para).erase(end.ph.start, end.ph.end)
+		}
+		if startOnly {
+			start.para.remove()
+		} else {
+			newParagraph(start.para).erase(start.ph.start, start.ph.end)
+		}
+		return
+	}
+	// paragraphs starting with the starting marker or ending with the ending one are removed,
+	// text before the starting marker and after the ending one stays in its paragraph
+	startOnly = startOnly || (first == start.para && leadsParagraph(start))
+	endOnly = endOnly || (last == end.para && endsParagraph(end))
+	parent := first.parent
+	for i := last.index() - 1; i > first.index(); i-- {
+		parent.children[i].remove()
+	}
+	if last == end.para && !endOnly {
+		newParagraph(last).erase(0, end.ph.end)
+	} else if last != first {
+		last.remove()
+	}
+	if first == start.para && !startOnly {
+		newParagraph(first).truncate(start.ph.start)
+	} else {
+		first.remove()
+	}
+}
+
+// ensureParagraph adds an empty paragraph to a table cell
+// which lost all its paragraphs, Word refuses to open documents with empty cells
+func ensureParagraph(n *node) {
+	if n != nil && n.is("w:tc") && n.child("w:p") == nil {
+		n.append(elem("w:p"))
+	}
+}
+
+// isMarkerOnly checks if a paragraph contains nothing else than a marker
+func isMarkerOnly(m marker) bool {
+	text := newParagraph(m.para).text
+	return isBlank(text[:m.ph.start]+text[m.ph.end:]) && !hasContent(m.para)
+}
+
+// leadsParagraph checks if there's nothing in a paragraph before a marker
+func leadsParagraph(m marker) bool {
+	return isBlank(newParagraph(m.para).text[:m.ph.start]) && !hasSectPr(m.para)
+}
+
+// endsParagraph checks if there's nothing in a paragraph after a marker
+func endsParagraph(m marker) bool {
+	return isBlank(newParagraph(m.para).text[m.ph.end:]) && !hasSectPr(m.para)
+}
+
+func hasSectPr(p *node) bool {
+	pPr := p.child("w:pPr")
+	return pPr != nil && pPr.child("w:sectPr") != nil
+}
+
+// trimEmpty removes an empty paragraph
+func trimEmpty(p *node, trim bool) {
+	if trim && p != nil && p.parent != nil && p.is("w:p") && isBlank(newParagraph(p).text) && !hasContent(p) {
+		p.remove()
+	}
+}
+
+// hasContent checks if a paragraph contains something else than text,
+// like pictures, page breaks or section properties, which shouldn't be removed
+func hasContent(p *node) bool {
+	found := false
+	p.walk(func(n *node) bool {
+		switch n.tag {
+		case "w:drawing", "w:pict", "w:object", "w:sectPr", "w:fldChar", "w:fldSimple":
+			found = true
+		case "w:br":
+			found = found || n.attrValue("w:type") == "page"
+		}
+		return !found
+	})
+	return found
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// sibling returns a node next to n (offset 1) or preceding it (offset -1)
+func sibling(n *node, offset int) *node {
+	i := n.index()
+	if i == -1 || i+offset < 0 || i+offset >= len(n.parent.children) {
+		return nil
+	}
+	return n.parent.children[i+offset]
+}
+
+// blockRange returns the first and the last sibling of elements
+// which contain paragraphs of starting and ending markers
+func blockRange(a, b *node) (*node, *node) {
+	lca := commonAncestor(a, b)
+	first, last := childOf(lca, a), childOf(lca, b)
+	if first == nil || last == nil {
+		first, last, lca = lca, lca, lca.parent
+	}
+	// i.e. markers in cells of the same row make the whole row a block
+	for lca != nil && !blockContainers[lca.tag] {
+		first, last, lca = lca, lca, lca.parent
+	}
+	return first, last
+}
+
+// commonAncestor returns the closest node containing both a and b (or being one of them)
+func commonAncestor(a, b *node) *node {
+	ancestors := make(map[*node]bool)
+	for n := a; n != nil; n = n.parent {
+		ancestors[n] = true
+	}
+	for n := b; n != nil; n = n.parent {
+		if ancestors[n] {
+			return n
+		}
+	}
+	return nil
+}
+
+// childOf returns a child of the ancestor which contains n
+func childOf(ancestor, n *node) *node {
+	for ; n != nil; n = n.parent {
+		if n.parent == ancestor {
+			return n
+		}
+	}
+	return nil
+}
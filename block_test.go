@@ -0,0 +1,126 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func p(text string) string {
+	return `<w:p><w:r><w:t>` + escape(text) + `</w:t></w:r></w:p>`
+}
+
+func TestConditions(t *testing.T) {
+	dict := Dict{"total": 1500, "country": "DE", "has_discount": false, "items": []Dict{{"name": "x"}}}
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{`total > 1000 && country == "DE"`, true},
+		{`total > 1000 && country == “PL”`, false},
+		{`total >= 1500 || missing`, true},
+		{`!has_discount`, true},
+		{`has_discount`, false},
+		{`missing`, false},
+		{`missing == ""`, false},
+		{`(total - 500) * 2 == 2000`, true},
+		{`country != 'DE' || total < 100`, false},
+		{`items`, true},
+	}
+	for _, test := range tests {
+		body := p("a[#if "+test.condition+"]b[/if]c") + p("before") + p("[#if "+test.condition+"]") + p("yes") + p("[/if]")
+		got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+		want := p("ac") + p("before")
+		if test.want {
+			want = p("abc") + p("before") + p("yes")
+		}
+		if got != want {
+			t.Errorf("%s:\n got: %s\nwant: %s", test.condition, got, want)
+		}
+	}
+}
+
+func TestNestedConditions(t *testing.T) {
+	body := p("[#if a]A") + p("[#if b]B[/if]") + p("[/if]end")
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": true, "b": false}))
+	want := p("A") + p("end")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": false, "b": true}))
+	want = p("end")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestElseBlocks(t *testing.T) {
+	body := p("Dear [#if vip]valued [#else]new [/if]customer,") +
+		p("[#if has_discount]") + p("You save [discount].") + p("[#else]") + p("[#if coupon]Use [coupon].[/if]") + p("No discount.") + p("[/if]") +
+		p("end")
+	tests := []struct {
+		dict Dict
+		want string
+	}{
+		{Dict{"vip": true, "has_discount": true, "discount": "10%"}, p("Dear valued customer,") + p("You save 10%.") + p("end")},
+		{Dict{"coupon": "X1"}, p("Dear new customer,") + p("Use X1.") + p("No discount.") + p("end")},
+		{Dict{}, p("Dear new customer,") + p("No discount.") + p("end")},
+	}
+	for _, test := range tests {
+		got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(test.dict))
+		if got != test.want {
+			t.Errorf("%v:\n got: %s\nwant: %s", test.dict, got, test.want)
+		}
+	}
+	if _, err := newTestDocx(t, p("[#else]"), nil).WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of [#else] outside of a block")
+	}
+}
+
+func TestConditionalTableRow(t *testing.T) {
+	row := func(cells ...string) string {
+		s := `<w:tr>`
+		for _, cell := range cells {
+			s += `<w:tc>` + p(cell) + `</w:tc>`
+		}
+		return s + `</w:tr>`
+	}
+	body := `<w:tbl>` + row("Item", "Price") + row("[#if discount]Discount", "[discount][/if]") + row("Total", "[total]") + `</w:tbl>`
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"total": 10}))
+	want := `<w:tbl>` + row("Item", "Price") + row("Total", "10") + `</w:tbl>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestBlockWhitespace(t *testing.T) {
+	empty := `<w:p><w:pPr><w:pStyle w:val="Normal"/></w:pPr></w:p>`
+	body := p("Intro") + empty + p("[-#if a]") + p("A") + p("[/if-]") + empty + p("[#if a]Inline[/if]") + p("End")
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": false}))
+	want := p("Intro") + p("End")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": true}))
+	want = p("Intro") + p("A") + p("Inline") + p("End")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	// cells keep at least one paragraph and paragraphs with content are never removed
+	body = `<w:tbl><w:tr><w:tc>` + p("[#if a]") + p("A") + p("[/if]") + `</w:tc></w:tr></w:tbl>` +
+		p("[#if a]") + `<w:p><w:pPr><w:sectPr/></w:pPr><w:r><w:t>[/if]</w:t></w:r></w:p>`
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": false}))
+	want = `<w:tbl><w:tr><w:tc><w:p></w:p></w:tc></w:tr></w:tbl><w:p><w:pPr><w:sectPr></w:sectPr></w:pPr></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestInvalidBlocks(t *testing.T) {
+	for _, body := range []string{p("[#if a]"), p("[/if]"), p("[#if a +]x[/if]")} {
+		if _, err := newTestDocx(t, body, nil).WriteTo(new(strings.Builder)); err == nil {
+			t.Errorf("%s: expected error", body)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package docx
+
+// ReplaceBookmark replaces content of a bookmark with a value, like an Image or a BuildingBlock.
+// Values which consist of paragraphs replace paragraphs of the bookmark,
+// a bookmark spanning several paragraphs is replaced as a whole and removed
+func (doc *Docx) ReplaceBookmark(name string, value interface{}) *Docx {
+	if doc.bookmarks == nil {
+		doc.bookmarks = make(map[string]interface{})
+	}
+	doc.bookmarks[name] = value
+	return doc
+}
+
+// replaceBookmarks puts values of bookmarks in a part
+func (ctx *renderContext) replaceBookmarks() error {
+	if len(ctx.doc.bookmarks) == 0 {
+		return nil
+	}
+	ends := make(map[string]*node)
+	for _, n := range ctx.root.find("w:bookmarkEnd") {
+		ends[n.attrValue("w:id")] = n
+	}
+	for _, start := range ctx.root.find("w:bookmarkStart") {
+		v, ok := ctx.doc.bookmarks[start.attrValue("w:name")]
+		end := ends[start.attrValue("w:id")]
+		if !ok || end == nil || start.parent == nil {
+			continue
+		}
+		value, err := toValue(v)
+		if err != nil {
+			return err
+		}
+		if err := ctx.replaceBookmark(start, end, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctx *renderContext) replaceBookmark(start, end *node, value Value) error {
+	p := start.ancestor("w:p")
+	if p == nil || p != end.ancestor("w:p") || start.parent != end.parent {
+		return ctx.replaceBookmarkRange(start, end, value)
+	}
+	// formatting of the first replaced run is kept
+	var rPr *node
+	for i := end.index() - 1; i > start.index(); i-- {
+		if c := start.parent.children[i]; c.is("w:r") {
+			rPr = c.child("w:rPr")
+		}
+		start.parent.children[i].remove()
+	}
+	if block, ok := value.(blockValue); ok && isBlank(newParagraph(p).text) && !hasContent(p) {
+		blocks, err := block.blocks(ctx)
+		if err != nil {
+			return err
+		}
+		start.remove()
+		end.remove()
+		insertBlocks(p, blocks)
+		return ctx.renderBlocks(blocks)
+	}
+	runs, err := value.runs(ctx, rPr)
+	if err != nil {
+		return err
+	}
+	start.parent.insert(start.index()+1, runs...)
+	formatParagraph(p, value)
+	return nil
+}
+
+// replaceBookmarkRange replaces paragraphs (or tables) containing a bookmark
+func (ctx *renderContext) replaceBookmarkRange(start, end *node, value Value) error {
+	first, last := blockRange(start, end)
+	if first == nil || first.parent == nil {
+		return nil
+	}
+	var blocks []*node
+	block, isBlock := value.(blockValue)
+	if isBlock {
+		b, err := block.blocks(ctx)
+		if err != nil {
+			return err
+		}
+		blocks = b
+	} else {
+		p := elem("w:p")
+		if pPr := first.child("w:pPr"); first.is("w:p") && pPr != nil {
+			p.append(pPr.clone())
+			p.child("w:pPr").removeChild("w:sectPr")
+		}
+		runs, err := value.runs(ctx, nil)
+		if err != nil {
+			return err
+		}
+		p.append(runs...)
+		blocks = []*node{p}
+	}
+	parent := first.parent
+	for i := last.index(); i >= first.index(); i-- {
+		c := parent.children[i]
+		if c.is("w:p") && hasSectPr(c) {
+			// paragraphs with section properties are kept, only their content is removed
+			c.children = []*node{c.child("w:pPr")}
+			continue
+		}
+		if i > first.index() {
+			c.remove()
+		}
+	}
+	insertBlocks(first, blocks)
+	if isBlock {
+		return ctx.renderBlocks(blocks)
+	}
+	return nil
+}
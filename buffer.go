@@ -0,0 +1,101 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Buffer is a slice of XML tokes which are buffered before saving them in a file
+//
+// Deprecated: documents are rendered from a tree of nodes and don't use Buffer any more,
+// it's kept for code which streams tokens itself and will be removed in the next major version
+type Buffer []xml.Token
+
+// Flush saves all tokens to XML file and cleans the buffer
+//
+// Deprecated: see Buffer
+func (buffer *Buffer) Flush(encoder *xml.Encoder) error {
+	for _, token := range *buffer {
+		err := encoder.EncodeToken(fixNS(token))
+		if err != nil {
+			return err
+		}
+	}
+	buffer.Clean()
+	return nil
+}
+
+// Clean removes tokens from a buffer and keeps capacity untouched
+//
+// Deprecated: see Buffer
+func (buffer *Buffer) Clean() {
+	*buffer = (*buffer)[:0]
+}
+
+// Process converts CharData tokens from a buffer to one string
+// and replaces variables with values from a dictionary, formatted by fmt
+//
+// Deprecated: see Buffer
+func (buffer *Buffer) Process(encoder *xml.Encoder, dict Dict) error {
+	varName := ""
+	// wt indicates if we are currently in <w:t> XML element (where text is stored)
+	// all non-wt elements should be ignored when extracting a variable name
+	wt := true
+	for _, token := range *buffer {
+		if start, ok := token.(xml.StartElement); ok && isWT(start.Name) {
+			wt = true
+		}
+		if end, ok := token.(xml.EndElement); ok && isWT(end.Name) {
+			wt = false
+		}
+		if charData, ok := token.(xml.CharData); ok && wt {
+			varName += string(charData)
+		}
+	}
+	for key, val := range dict {
+		if strings.Contains(varName, key) {
+			varName = strings.Replace(varName, key, fmt.Sprint(val), 1)
+			// if expected value was found, clean the buffer and store replaced
+			// value as CharData token
+			buffer.Clean()
+			return encoder.EncodeToken(xml.CharData(varName))
+		}
+	}
+	// if expected value can't be found in a dictionary, just write
+	// all nodes to XLS file and clean the buffer
+	return buffer.Flush(encoder)
+}
+
+// isWT checks if current token is <w:t> XML element
+func isWT(name xml.Name) bool {
+	return name.Space == "w" && name.Local == "t"
+}
+
+// fixNS writes names of raw tokens with their prefixes, in the same format as they were read
+func fixNS(token xml.Token) xml.Token {
+	switch t := token.(type) {
+	case xml.StartElement:
+		t.Name = fixName(t.Name)
+		attrs := make([]xml.Attr, len(t.Attr))
+		for i, attr := range t.Attr {
+			attr.Name = fixName(attr.Name)
+			attrs[i] = attr
+		}
+		t.Attr = attrs
+		return t
+	case xml.EndElement:
+		t.Name = fixName(t.Name)
+		return t
+	default:
+		return token
+	}
+}
+
+func fixName(name xml.Name) xml.Name {
+	if name.Space != "" {
+		name.Local = name.Space + ":" + name.Local
+		name.Space = ""
+	}
+	return name
+}
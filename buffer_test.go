@@ -0,0 +1,32 @@
+package docx
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	var out strings.Builder
+	encoder := xml.NewEncoder(&out)
+	buffer := Buffer{
+		xml.CharData("Hello ["), xml.EndElement{Name: xml.Name{Space: "w", Local: "t"}},
+		xml.StartElement{Name: xml.Name{Space: "w", Local: "t"}}, xml.CharData("name]"),
+	}
+	if err := buffer.Process(encoder, Dict{"[name]": "John"}); err != nil {
+		t.Fatal(err)
+	}
+	buffer = Buffer{xml.StartElement{Name: xml.Name{Space: "w", Local: "r"}}, xml.CharData("[x]")}
+	if err := buffer.Process(encoder, Dict{"[name]": "John"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "Hello John<w:r>[x]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if len(buffer) != 0 {
+		t.Errorf("Expected an empty buffer, got %d tokens", len(buffer))
+	}
+}
@@ -0,0 +1,149 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BuildingBlock is a name of a building block (Quick Part, AutoText) stored in the glossary
+// of the template, like a standard clause or a letterhead.
+// It replaces the whole paragraph of a placeholder and its placeholders are replaced too.
+// A building block with a single paragraph can be also used inside text
+type BuildingBlock string
+
+func (b BuildingBlock) blocks(ctx *renderContext) ([]*node, error) {
+	g, err := ctx.loadGlossary()
+	if err != nil {
+		return nil, err
+	}
+	body := g.docPart(string(b))
+	if body == nil {
+		return nil, fmt.Errorf("Building block %s not found", string(b))
+	}
+	var blocks []*node
+	for _, c := range body.children {
+		if c.tag == "" || c.is("w:sectPr") {
+			continue
+		}
+		blocks = append(blocks, c.clone())
+	}
+	for _, n := range blocks {
+		removeIDs(n)
+		if err := ctx.importContent(g, n); err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+func (b BuildingBlock) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	blocks, err := b.blocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) != 1 || !blocks[0].is("w:p") {
+		return nil, fmt.Errorf("Building block %s has several paragraphs, it can't be used inside text", string(b))
+	}
+	var runs []*node
+	for _, c := range blocks[0].children {
+		if !c.is("w:pPr") {
+			runs = append(runs, c)
+		}
+	}
+	return runs, nil
+}
+
+// glossary is a glossary document part which stores building blocks of a template
+type glossary struct {
+	name string
+	root *node
+	// IDs of glossary relationships mapped to IDs of their copies in the rendered part
+	rels map[string]string
+}
+
+// loadGlossary reads glossary document of the template, it's parsed once per rendered part
+func (ctx *renderContext) loadGlossary() (*glossary, error) {
+	if ctx.glossary != nil {
+		return ctx.glossary, nil
+	}
+	name, ok := ctx.pkg.relatedPart(documentXML, relTypeGlossary)
+	if !ok || !ctx.pkg.has(name) {
+		return nil, fmt.Errorf("Invalid DOCX document: template has no building blocks")
+	}
+	root, err := ctx.pkg.parsePart(name)
+	if err != nil {
+		return nil, err
+	}
+	ctx.glossary = &glossary{name: name, root: root, rels: make(map[string]string)}
+	return ctx.glossary, nil
+}
+
+// docPart returns content of a building block with given name
+func (g *glossary) docPart(name string) *node {
+	for _, part := range g.root.find("w:docPart") {
+		pr := part.child("w:docPartPr")
+		if pr == nil || pr.child("w:name") == nil {
+			continue
+		}
+		if strings.EqualFold(pr.child("w:name").attrValue("w:val"), name) {
+			return part.child("w:docPartBody")
+		}
+	}
+	return nil
+}
+
+// importContent prepares content copied from the glossary for the rendered part:
+// relationships of pictures and links are copied, drawings get unique IDs, notes are marked to be copied
+// and missing namespaces are declared
+func (ctx *renderContext) importContent(g *glossary, content *node) error {
+	source, err := ctx.pkg.relationships(g.name)
+	if err != nil {
+		return err
+	}
+	target, err := ctx.pkg.relationships(ctx.part)
+	if err != nil {
+		return err
+	}
+	content.walk(func(n *node) bool {
+		for i, a := range n.attr {
+			if !strings.HasPrefix(a.Name.Local, "r:") {
+				continue
+			}
+			if id, ok := g.rels[a.Value]; ok {
+				n.attr[i].Value = id
+				continue
+			}
+			rel, ok := source.target(a.Value)
+			if !ok {
+				continue
+			}
+			if rel.TargetMode != "External" {
+				rel.Target = relTarget(ctx.part, resolveTarget(g.name, rel.Target))
+			}
+			g.rels[a.Value] = target.addRelationship(rel)
+			n.attr[i].Value = g.rels[a.Value]
+		}
+		if n.is("wp:docPr") {
+			n.setAttr("id", strconv.Itoa(ctx.nextDocPrID()))
+		}
+		// notes are copied from the glossary when notes of the document are renumbered
+		for _, kind := range noteKinds {
+			if !n.is(kind.reference) {
+				continue
+			}
+			if name, ok := ctx.pkg.relatedPart(g.name, kind.relType); ok {
+				ctx.noteSources[n] = name
+			}
+		}
+		return true
+	})
+	if el := g.root.documentElement(); el != nil {
+		for _, a := range el.attr {
+			if strings.HasPrefix(a.Name.Local, "xmlns:") {
+				ctx.declareNS(strings.TrimPrefix(a.Name.Local, "xmlns:"), a.Value)
+			}
+		}
+	}
+	return nil
+}
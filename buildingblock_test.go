@@ -0,0 +1,88 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+// glossaryParts returns parts of a template with building blocks Clause, Logo and Letterhead
+func glossaryParts() map[string]string {
+	docPart := func(name, body string) string {
+		return `<w:docPart><w:docPartPr><w:name w:val="` + name + `"/><w:category><w:name w:val="General"/></w:category>` +
+			`</w:docPartPr><w:docPartBody>` + body + `</w:docPartBody></w:docPart>`
+	}
+	return map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeGlossary + `" Target="glossary/document.xml"/>` +
+			`</Relationships>`,
+		"word/glossary/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/logo.png"/>` +
+			`</Relationships>`,
+		"word/glossary/document.xml": `<w:glossaryDocument xmlns:w="w" xmlns:wp="wp" xmlns:r="r"><w:docParts>` +
+			docPart("Clause", p("Warranty for [customer].")+p("Second paragraph.")) +
+			docPart("Logo", `<w:p><w:r><w:drawing><wp:docPr id="1"/><a:blip r:embed="rId1"/></w:drawing></w:r></w:p>`) +
+			docPart("Letterhead", `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>ACME Inc.</w:t></w:r></w:p>`) +
+			`</w:docParts></w:glossaryDocument>`,
+	}
+}
+
+func TestBuildingBlocks(t *testing.T) {
+	body := p("[clause]") + p("From [letterhead] with love") + p("[logo]") + p("[rest]")
+	dict := Dict{
+		"customer":   "Bob",
+		"clause":     BuildingBlock("Clause"),
+		"letterhead": BuildingBlock("letterhead"),
+		"logo":       BuildingBlock("Logo"),
+		"rest":       BuildingBlock("Logo"),
+	}
+	got := renderParts(t, newTestDocx(t, body, glossaryParts()).ReplaceDict(dict))
+	want := p("Warranty for Bob.") + p("Second paragraph.") +
+		`<w:p><w:r><w:t xml:space="preserve">From </w:t></w:r><w:r><w:t>ACME Inc.</w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve"> with love</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:drawing><wp:docPr id="1"></wp:docPr><a:blip r:embed="rId3"></a:blip></w:drawing></w:r></w:p>` +
+		`<w:p><w:r><w:drawing><wp:docPr id="2"></wp:docPr><a:blip r:embed="rId3"></a:blip></w:drawing></w:r></w:p>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	if !strings.Contains(got[documentXML], `xmlns:wp="wp"`) {
+		t.Errorf("Namespace wasn't declared: %s", got[documentXML])
+	}
+	if !strings.Contains(got["word/_rels/document.xml.rels"], `Id="rId3" Type="`+relTypeImage+`" Target="glossary/media/logo.png"`) {
+		t.Errorf("Relationship wasn't copied: %s", got["word/_rels/document.xml.rels"])
+	}
+
+	for _, value := range []BuildingBlock{"Missing", "Clause"} {
+		doc := newTestDocx(t, p("Inline [x]."), glossaryParts()).ReplaceDict(Dict{"x": value})
+		if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+			t.Errorf("Expected error for building block %s", value)
+		}
+	}
+	doc := newTestDocx(t, p("[x]"), nil).ReplaceDict(Dict{"x": BuildingBlock("Clause")})
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for a template without building blocks")
+	}
+}
+
+func TestReplaceBookmark(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:bookmarkStart w:id="0" w:name="name"/>` +
+		`<w:r><w:rPr><w:b/></w:rPr><w:t>Sir</w:t></w:r><w:bookmarkEnd w:id="0"/><w:r><w:t>,</w:t></w:r></w:p>` +
+		`<w:p><w:bookmarkStart w:id="1" w:name="clause"/><w:bookmarkEnd w:id="1"/></w:p>` +
+		`<w:bookmarkStart w:id="2" w:name="terms"/>` + p("Old terms") + p("More old terms") + `<w:bookmarkEnd w:id="2"/>` +
+		p("End")
+	doc := newTestDocx(t, body, glossaryParts()).
+		ReplaceDict(Dict{"customer": "Alice"}).
+		ReplaceBookmark("name", "Madam").
+		ReplaceBookmark("clause", BuildingBlock("Clause")).
+		ReplaceBookmark("terms", "New terms").
+		ReplaceBookmark("missing", "x")
+	got := renderBody(t, doc)
+	want := `<w:p><w:r><w:t xml:space="preserve">Dear </w:t></w:r><w:bookmarkStart w:id="0" w:name="name"></w:bookmarkStart>` +
+		`<w:r><w:rPr><w:b></w:b></w:rPr><w:t>Madam</w:t></w:r><w:bookmarkEnd w:id="0"></w:bookmarkEnd><w:r><w:t>,</w:t></w:r></w:p>` +
+		p("Warranty for Alice.") + p("Second paragraph.") + p("New terms") + p("End")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"strconv"
+)
+
+// CheckboxStyle are glyphs of ticked and empty boxes, Font is set on runs of glyphs
+// which need a symbol font like Wingdings
+type CheckboxStyle struct {
+	Checked, Unchecked string
+	Font               string
+}
+
+var (
+	// UnicodeCheckboxes are boxes Word uses for checkbox content controls
+	UnicodeCheckboxes = CheckboxStyle{Checked: "☒", Unchecked: "☐", Font: "MS Gothic"}
+	// WingdingsCheckboxes are boxes of the Wingdings font, the tick is like ✔ in a box
+	WingdingsCheckboxes = CheckboxStyle{Checked: "\uF0FE", Unchecked: "\uF0A8", Font: "Wingdings"}
+)
+
+// Checkboxes renders bool values as ticked or empty boxes instead of "true" and "false",
+// so yes/no fields of forms look like filled in by hand
+func (doc *Docx) Checkboxes(style CheckboxStyle) *Docx {
+	doc.checkboxes = &style
+	return doc
+}
+
+// checkboxValue replaces a bool value (which can be formatted) with a glyph when checkboxes are enabled
+func (doc *Docx) checkboxValue(v interface{}) interface{} {
+	if doc.checkboxes == nil {
+		return v
+	}
+	switch b := v.(type) {
+	case bool:
+		return doc.checkboxes.glyph(b)
+	case formattedValue:
+		b.value = doc.checkboxValue(b.value)
+		return b
+	}
+	return v
+}
+
+// glyph returns a box with font of the style
+func (style CheckboxStyle) glyph(checked bool) RichText {
+	text := style.Unchecked
+	if checked {
+		text = style.Checked
+	}
+	return RichText{{Text: text, Font: style.Font}}
+}
+
+// tickCheckbox sets state of a checkbox content control (w14:checkbox) and its glyph,
+// glyphs and fonts of the control's checked and unchecked states are used
+func (ctx *renderContext) tickCheckbox(sdt *node, checked bool) error {
+	box := sdtProperty(sdt, "w14:checkbox")
+	val := "0"
+	state := box.child("w14:uncheckedState")
+	glyph := UnicodeCheckboxes.glyph(checked)
+	if checked {
+		val = "1"
+		state = box.child("w14:checkedState")
+	}
+	box.setChild(elem("w14:checked", "w14:val", val), []string{"w14:checked", "w14:checkedState", "w14:uncheckedState"})
+	if state != nil {
+		if code, err := strconv.ParseUint(state.attrValue("w14:val"), 16, 32); err == nil {
+			glyph = RichText{{Text: string(rune(code)), Font: state.attrValue("w14:font")}}
+		}
+	}
+	return ctx.fillControl(sdt, glyph)
+}
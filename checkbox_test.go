@@ -0,0 +1,45 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckboxes(t *testing.T) {
+	body := p("[agree] I agree") + p("[spam|bold] Newsletter")
+	dict := Dict{"agree": true, "spam": false}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	if want := p("true I agree"); !strings.Contains(got, want) {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict).Checkboxes(UnicodeCheckboxes))
+	want := `<w:p><w:r><w:rPr><w:rFonts w:ascii="MS Gothic" w:hAnsi="MS Gothic" w:cs="MS Gothic"></w:rFonts></w:rPr><w:t>☒</w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve"> I agree</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:rPr><w:rFonts w:ascii="MS Gothic" w:hAnsi="MS Gothic" w:cs="MS Gothic"></w:rFonts><w:b></w:b><w:bCs></w:bCs></w:rPr>` +
+		`<w:t>☐</w:t></w:r><w:r><w:t xml:space="preserve"> Newsletter</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	got = renderBody(t, newTestDocx(t, p("[agree]"), nil).ReplaceDict(dict).Checkboxes(WingdingsCheckboxes))
+	if !strings.Contains(got, `w:ascii="Wingdings"`) || !strings.Contains(got, "<w:t></w:t>") {
+		t.Errorf("Unexpected Wingdings box: %s", got)
+	}
+
+	// checkbox content controls of repeated content are ticked
+	box := sdt(`<w:tag w:val="paid"/><w14:checkbox><w14:checked w14:val="0"/>`+
+		`<w14:checkedState w14:val="2612" w14:font="MS Gothic"/><w14:uncheckedState w14:val="2610" w14:font="MS Gothic"/></w14:checkbox>`,
+		`<w:r><w:rPr><w:rFonts w:ascii="MS Gothic" w:hAnsi="MS Gothic"/></w:rPr><w:t>☐</w:t></w:r>`)
+	item := sdt(`<w15:repeatingSectionItem/>`, `<w:p>`+box+`<w:r><w:t xml:space="preserve"> [name]</w:t></w:r></w:p>`)
+	body = sdt(`<w:tag w:val="invoices"/><w15:repeatingSection/>`, item)
+	dict = Dict{"invoices": []Dict{{"name": "A", "paid": true}, {"name": "B", "paid": false}}}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	for _, want := range []string{
+		`<w14:checked w14:val="1"></w14:checked>`, `<w14:checked w14:val="0"></w14:checked>`,
+		`<w:rFonts w:ascii="MS Gothic" w:hAnsi="MS Gothic" w:cs="MS Gothic"></w:rFonts></w:rPr><w:t>☒</w:t>`,
+		`<w:rFonts w:ascii="MS Gothic" w:hAnsi="MS Gothic" w:cs="MS Gothic"></w:rFonts></w:rPr><w:t>☐</w:t>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Command docxgen generates a Go type for data of a DOCX template, so it's checked
+// at compile time that the data matches the template:
+//
+//	//go:generate docxgen -template invoice.docx -type Invoice -o invoice_docx.go
+//
+// Fields are generated from the schema of the template (see Docx.Schema), values
+// of optional fields (used only in conditional blocks) are left out when they are empty.
+// The type gets Dict and Render methods
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	docx "github.com/elblox/go-docx"
+)
+
+func main() {
+	template := flag.String("template", "", "DOCX template")
+	typeName := flag.String("type", "", "name of the generated type, the name of the template by default")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	output := flag.String("o", "", "output file, standard output by default")
+	flag.Parse()
+	if *template == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *typeName == "" {
+		*typeName = goName(strings.TrimSuffix(filepath.Base(*template), filepath.Ext(*template)))
+	}
+	if err := run(*template, *typeName, *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(template, typeName, pkg, output string) error {
+	doc, err := docx.OpenFile(template)
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+	schema, err := doc.Schema()
+	if err != nil {
+		return err
+	}
+	code, err := generate(schema, filepath.Base(template), pkg, typeName)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(output, code, 0644)
+}
+
+// schema is a part of JSON Schema returned by Docx.Schema
+type schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *schema            `json:"items"`
+}
+
+// generator writes Go code of types for a schema
+type generator struct {
+	buf bytes.Buffer
+	// types are names of generated types, nested objects get types named by their path
+	types map[string]bool
+}
+
+// generate returns a formatted Go file with types for data of a template
+func generate(data []byte, template, pkg, typeName string) ([]byte, error) {
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	g := &generator{types: make(map[string]bool)}
+	fmt.Fprintf(&g.buf, "// Code generated by docxgen from %s; DO NOT EDIT.\n\npackage %s\n\n", template, pkg)
+	fmt.Fprintf(&g.buf, "import (\n\"io\"\n\ndocx %q\n)\n\n", "github.com/elblox/go-docx")
+	fmt.Fprintf(&g.buf, "// %s is data of the template %s\n", typeName, template)
+	g.object(typeName, &s)
+	fmt.Fprintf(&g.buf, "// Render writes a document made from the template %s\n", template)
+	fmt.Fprintf(&g.buf, "func (d %s) Render(doc *docx.Docx, w io.Writer) error {\n", typeName)
+	fmt.Fprintf(&g.buf, "_, err := doc.ReplaceDict(d.Dict()).WriteTo(w)\nreturn err\n}\n")
+	return format.Source(g.buf.Bytes())
+}
+
+// field is a property of an object
+type field struct {
+	key, name, typ string
+	required       bool
+	s              *schema
+}
+
+// object writes a struct type for an object and its Dict method, nested objects come after it
+func (g *generator) object(typeName string, s *schema) {
+	g.types[typeName] = true
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]field, 0, len(keys))
+	// fields can't have names of methods
+	names := map[string]bool{"Dict": true, "Render": true}
+	for _, key := range keys {
+		name := goName(key)
+		for names[name] {
+			name += "_"
+		}
+		names[name] = true
+		fields = append(fields, field{key: key, name: name, required: required[key], s: s.Properties[key]})
+	}
+	var nested []field
+	for i, f := range fields {
+		switch f.s.Type {
+		case "string":
+			fields[i].typ = "string"
+		case "number":
+			fields[i].typ = "float64"
+		case "boolean":
+			fields[i].typ = "bool"
+		case "object":
+			// optional objects are pointers, so they can be left out
+			fields[i].typ = g.typeName(typeName + f.name)
+			if !f.required {
+				fields[i].typ = "*" + fields[i].typ
+			}
+			nested = append(nested, fields[i])
+		case "array":
+			fields[i].typ = "[]" + g.typeName(typeName+f.name+"Item")
+			nested = append(nested, fields[i])
+		default:
+			fields[i].typ = "interface{}"
+		}
+	}
+	fmt.Fprintf(&g.buf, "type %s struct {\n", typeName)
+	for _, f := range fields {
+		omit := ""
+		if !f.required {
+			omit = ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "%s %s `json:\"%s%s\"`\n", f.name, f.typ, f.key, omit)
+	}
+	fmt.Fprintf(&g.buf, "}\n\n")
+	fmt.Fprintf(&g.buf, "// Dict returns values for the template\n")
+	fmt.Fprintf(&g.buf, "func (d %s) Dict() docx.Dict {\ndict := make(docx.Dict, %d)\n", typeName, len(fields))
+	for _, f := range fields {
+		// empty optional values are left out, so conditions using them are false
+		if !f.required {
+			switch f.s.Type {
+			case "string":
+				fmt.Fprintf(&g.buf, "if d.%s != \"\" {\n", f.name)
+			case "number":
+				fmt.Fprintf(&g.buf, "if d.%s != 0 {\n", f.name)
+			case "boolean":
+				fmt.Fprintf(&g.buf, "if d.%s {\n", f.name)
+			case "array":
+				fmt.Fprintf(&g.buf, "if len(d.%s) > 0 {\n", f.name)
+			default:
+				fmt.Fprintf(&g.buf, "if d.%s != nil {\n", f.name)
+			}
+		}
+		switch f.s.Type {
+		case "object":
+			fmt.Fprintf(&g.buf, "dict[%q] = d.%s.Dict()\n", f.key, f.name)
+		case "array":
+			fmt.Fprintf(&g.buf, "{\nitems := make([]docx.Dict, len(d.%s))\n", f.name)
+			fmt.Fprintf(&g.buf, "for i, item := range d.%s {\nitems[i] = item.Dict()\n}\n", f.name)
+			fmt.Fprintf(&g.buf, "dict[%q] = items\n}\n", f.key)
+		default:
+			fmt.Fprintf(&g.buf, "dict[%q] = d.%s\n", f.key, f.name)
+		}
+		if !f.required {
+			fmt.Fprintf(&g.buf, "}\n")
+		}
+	}
+	fmt.Fprintf(&g.buf, "return dict\n}\n\n")
+	for _, f := range nested {
+		if f.s.Type == "array" {
+			items := f.s.Items
+			if items == nil {
+				items = &schema{Type: "object"}
+			}
+			g.object(strings.TrimPrefix(f.typ, "[]"), items)
+		} else {
+			g.object(strings.TrimPrefix(f.typ, "*"), f.s)
+		}
+	}
+}
+
+// typeName returns a unique name of a type
+func (g *generator) typeName(name string) string {
+	for g.types[name] {
+		name += "_"
+	}
+	g.types[name] = true
+	return name
+}
+
+// goName converts a variable name like "customer_id" or "Project Year" to an exported Go name
+func goName(key string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if sb.Len() == 0 && unicode.IsDigit(r) {
+			sb.WriteByte('X')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	name := sb.String()
+	switch strings.ToLower(name) {
+	case "":
+		return "X"
+	case "id", "url":
+		return strings.ToUpper(name)
+	}
+	for _, initialism := range []string{"Id", "Url"} {
+		if strings.HasSuffix(name, initialism) {
+			return strings.TrimSuffix(name, initialism) + strings.ToUpper(initialism)
+		}
+	}
+	return name
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"customer": {"type": "object", "properties": {"name": {"type": "string"}, "vat_id": {"type": "string"}}, "required": ["name"]},
+			"total": {"type": "number"},
+			"vip": {"type": "boolean"},
+			"Project Year": {},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}}
+		},
+		"required": ["customer", "total"]
+	}`
+	code, err := generate([]byte(schema), "invoice.docx", "invoices", "Invoice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by docxgen from invoice.docx; DO NOT EDIT.\n\npackage invoices\n",
+		"type Invoice struct {\n" +
+			"\tProjectYear interface{}        `json:\"Project Year,omitempty\"`\n" +
+			"\tCustomer    InvoiceCustomer    `json:\"customer\"`\n" +
+			"\tItems       []InvoiceItemsItem `json:\"items,omitempty\"`\n" +
+			"\tTotal       float64            `json:\"total\"`\n" +
+			"\tVip         bool               `json:\"vip,omitempty\"`\n" +
+			"}\n",
+		"\tdict[\"customer\"] = d.Customer.Dict()\n",
+		"\tif d.Vip {\n\t\tdict[\"vip\"] = d.Vip\n\t}\n",
+		"type InvoiceCustomer struct {\n" +
+			"\tName  string `json:\"name\"`\n" +
+			"\tVatID string `json:\"vat_id,omitempty\"`\n" +
+			"}\n",
+		"type InvoiceItemsItem struct {\n",
+		"func (d Invoice) Render(doc *docx.Docx, w io.Writer) error {\n",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("got: %s\nwant: %s", code, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command docxwasm renders templates in a browser. It's built with
+//
+//	GOOS=js GOARCH=wasm go build -o docx.wasm github.com/elblox/go-docx/cmd/docxwasm
+//
+// and registers a global JavaScript function renderDocx(template, data) which takes
+// the template as Uint8Array and data as a JSON string. It returns the document as Uint8Array,
+// or an Error, as a panic would stop the program
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	docx "github.com/elblox/go-docx"
+)
+
+func main() {
+	js.Global().Set("renderDocx", js.FuncOf(render))
+	// the functions can be called as long as the program runs
+	select {}
+}
+
+func render(this js.Value, args []js.Value) interface{} {
+	if len(args) != 2 {
+		return errorValue("renderDocx expects a template and JSON data")
+	}
+	template := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(template, args[0])
+	var dict docx.Dict
+	if err := json.Unmarshal([]byte(args[1].String()), &dict); err != nil {
+		return errorValue(err.Error())
+	}
+	document, err := docx.RenderBytes(template, dict)
+	if err != nil {
+		return errorValue(err.Error())
+	}
+	result := js.Global().Get("Uint8Array").New(len(document))
+	js.CopyBytesToJS(result, document)
+	return result
+}
+
+// errorValue returns a JavaScript error
+func errorValue(message string) interface{} {
+	return js.Global().Get("Error").New(message)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// comments prints comments of a document as JSON
+func comments(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	doc, err := docx.OpenFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer doc.Close()
+	list, err := doc.Comments()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		return 2
+	}
+	if list == nil {
+		list = []docx.Comment{}
+	}
+	return printJSON(list)
+}
+
+// printJSON prints indented JSON and returns the exit code
+func printJSON(v interface{}) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// links prints hyperlinks of a document as JSON, with -check only broken ones,
+// it returns 1 if broken links are found
+func links(args []string) int {
+	flags := flag.NewFlagSet("links", flag.ContinueOnError)
+	check := flags.Bool("check", false, "print only links whose targets can't be reached")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	doc, err := docx.OpenFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer doc.Close()
+	list, err := doc.Hyperlinks()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", flags.Arg(0), err)
+		return 2
+	}
+	if !*check {
+		if list == nil {
+			list = []docx.Hyperlink{}
+		}
+		return printJSON(list)
+	}
+	broken := docx.CheckLinks(context.Background(), nil, list)
+	if broken == nil {
+		broken = []docx.BrokenLink{}
+	}
+	if code := printJSON(broken); code != 0 {
+		return code
+	}
+	if len(broken) > 0 {
+		return 1
+	}
+	return 0
+}
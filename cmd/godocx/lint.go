@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// problem is a problem of a template file in JSON output
+type problem struct {
+	File string `json:"file"`
+	docx.Problem
+}
+
+// lint checks templates and returns the exit code: 1 if problems are found, 2 on errors
+func lint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print problems as a JSON array")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	files, err := templateFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	problems := []problem{}
+	code := 0
+	for _, name := range files {
+		found, err := lintFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			code = 2
+			continue
+		}
+		for _, p := range found {
+			problems = append(problems, problem{File: name, Problem: p})
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(problems); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", p.File, p.Problem)
+		}
+	}
+	if code == 0 && len(problems) > 0 {
+		code = 1
+	}
+	return code
+}
+
+func lintFile(name string) ([]docx.Problem, error) {
+	doc, err := docx.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	return doc.Lint()
+}
+
+// templateFiles returns given files and .docx files found in given directories
+func templateFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// lock files of opened documents start with ~$
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(name), ".docx") && !strings.HasPrefix(d.Name(), "~$") {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
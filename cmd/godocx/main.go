@@ -0,0 +1,53 @@
+// Command godocx works with DOCX templates from the command line:
+//
+//	godocx lint [-json] template.docx|directory...
+//	godocx watch template.docx [--data data.json] [-o output.docx]
+//	godocx comments document.docx
+//	godocx revisions document.docx
+//	godocx links [-check] document.docx
+//	godocx optimize [-o output.docx] [-jpeg-quality 1-100] document.docx
+//	godocx pii [-redact output.docx] [-replacement text] document.docx
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage:
+  godocx lint [-json] template.docx|directory...
+  godocx watch template.docx [--data data.json] [-o output.docx]
+  godocx comments document.docx
+  godocx revisions document.docx
+  godocx links [-check] document.docx
+  godocx optimize [-o output.docx] [-jpeg-quality 1-100] document.docx
+  godocx pii [-redact output.docx] [-replacement text] document.docx
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var code int
+	switch os.Args[1] {
+	case "lint":
+		code = lint(os.Args[2:])
+	case "watch":
+		code = watch(os.Args[2:])
+	case "comments":
+		code = comments(os.Args[2:])
+	case "revisions":
+		code = revisions(os.Args[2:])
+	case "links":
+		code = links(os.Args[2:])
+	case "optimize":
+		code = optimize(os.Args[2:])
+	case "pii":
+		code = pii(os.Args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %s\n%s", os.Args[1], usage)
+		code = 2
+	}
+	os.Exit(code)
+}
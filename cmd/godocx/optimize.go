@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// optimize writes a smaller copy of a document and prints how much each pass saved
+func optimize(args []string) int {
+	flags := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	output := flags.String("o", "", "optimized document, document.optimized.docx by default")
+	quality := flags.Int("jpeg-quality", 0, "recompress JPEG images with loss of quality from 1 to 100, 0 keeps them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	name := flags.Arg(0)
+	if *output == "" {
+		*output = strings.TrimSuffix(name, ".docx") + ".optimized.docx"
+	}
+	doc, err := docx.OpenFile(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer doc.Close()
+	report, err := doc.JPEGQuality(*quality).Optimize()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTemplate(&buf); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	if err := ioutil.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	for _, pass := range report.Passes {
+		fmt.Printf("%-8s %4d changed %10d bytes saved\n", pass.Name, pass.Changed, pass.Saved)
+	}
+	fmt.Printf("%s: %d bytes, %s: %d bytes\n", name, report.Before, *output, report.After)
+	return 0
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// pii prints potential personal data of a document as JSON, with -redact it also writes
+// a copy where they're replaced. It returns 1 if personal data are found
+func pii(args []string) int {
+	flags := flag.NewFlagSet("pii", flag.ContinueOnError)
+	redact := flags.String("redact", "", "write a redacted copy of the document")
+	replacement := flags.String("replacement", "[redacted]", "text which replaces personal data")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	name := flags.Arg(0)
+	doc, err := docx.OpenFile(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer doc.Close()
+	matches, err := doc.ScanPII()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 2
+	}
+	if matches == nil {
+		matches = []docx.PIIMatch{}
+	}
+	if *redact != "" {
+		var buf bytes.Buffer
+		if _, err := doc.RedactPII(*replacement).WriteTemplate(&buf); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			return 2
+		}
+		if err := ioutil.WriteFile(*redact, buf.Bytes(), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	if code := printJSON(matches); code != 0 {
+		return code
+	}
+	if len(matches) > 0 {
+		return 1
+	}
+	return 0
+}
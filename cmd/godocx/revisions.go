@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// revisions prints tracked changes of a document as JSON
+func revisions(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	doc, err := docx.OpenFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer doc.Close()
+	list, err := doc.Revisions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		return 2
+	}
+	if list == nil {
+		list = []docx.Revision{}
+	}
+	return printJSON(list)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	docx "github.com/elblox/go-docx"
+)
+
+// watch renders a template whenever it or its data change, it runs until it's interrupted
+func watch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	data := flags.String("data", "", "JSON file with data of the template")
+	output := flags.String("o", "", "rendered document, template.out.docx by default")
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often files are checked")
+	// flags can follow the template, like godocx watch template.docx --data data.json
+	var templates []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return 2
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		templates = append(templates, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(templates) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	template := templates[0]
+	if *output == "" {
+		*output = strings.TrimSuffix(template, ".docx") + ".out.docx"
+	}
+	files := []string{template}
+	if *data != "" {
+		files = append(files, *data)
+	}
+	var last []time.Time
+	for ; ; time.Sleep(*interval) {
+		times, err := modTimes(files)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		if equalTimes(times, last) {
+			continue
+		}
+		last = times
+		if err := render(template, *data, *output); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", time.Now().Format("15:04:05"), template, err)
+			continue
+		}
+		fmt.Printf("%s %s rendered to %s\n", time.Now().Format("15:04:05"), template, *output)
+	}
+}
+
+// modTimes returns modification times of files
+func modTimes(files []string) ([]time.Time, error) {
+	times := make([]time.Time, len(files))
+	for i, name := range files {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		times[i] = info.ModTime()
+	}
+	return times, nil
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// render writes a document made from a template and JSON data
+func render(template, data, output string) error {
+	content, err := ioutil.ReadFile(template)
+	if err != nil {
+		return err
+	}
+	var dict docx.Dict
+	if data != "" {
+		b, err := ioutil.ReadFile(data)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &dict); err != nil {
+			return fmt.Errorf("%s: %v", data, err)
+		}
+	}
+	document, err := docx.RenderBytes(content, dict)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(output, document, 0644)
+}
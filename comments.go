@@ -0,0 +1,311 @@
+package docx
+
+import (
+	"strings"
+	"time"
+)
+
+// commentDirectives turns block directives written in Word comments (like "#if total > 1000")
+// into markers placed at the start and the end of the commented text.
+// Such comments are removed from the document, other comments are kept untouched
+func (ctx *renderContext) commentDirectives() error {
+	name, ok := ctx.pkg.relatedPart(ctx.part, relTypeComments)
+	if !ok || !ctx.pkg.has(name) {
+		return nil
+	}
+	comments, ok, err := ctx.parseAuxiliaryPart(name)
+	if !ok {
+		return err
+	}
+	directives := make(map[string]marker)
+	var removed []*node
+	for _, c := range comments.find("w:comment") {
+		m, ok := ctx.doc.directive(c)
+		if !ok {
+			continue
+		}
+		directives[c.attrValue("w:id")] = m
+		removed = append(removed, c)
+	}
+	if len(directives) == 0 {
+		return nil
+	}
+	opening, closing := ctx.doc.openingBracket, ctx.doc.closingBracket
+	for _, n := range ctx.root.find("w:commentRangeStart") {
+		if m, ok := directives[n.attrValue("w:id")]; ok {
+			insertMarker(n, opening+m.ph.name+closing, false)
+		}
+	}
+	for _, n := range ctx.root.find("w:commentRangeEnd") {
+		if m, ok := directives[n.attrValue("w:id")]; ok {
+			insertMarker(n, opening+"/"+m.kind+closing, true)
+		}
+	}
+	for _, n := range ctx.root.find("w:commentReference") {
+		if _, ok := directives[n.attrValue("w:id")]; ok {
+			if r := n.ancestor("w:r"); r != nil {
+				r.remove()
+			} else {
+				n.remove()
+			}
+		}
+	}
+	paraIDs := make(map[string]bool)
+	for _, c := range removed {
+		for _, p := range c.find("w:p") {
+			if id := p.attrValue("w14:paraId"); id != "" {
+				paraIDs[id] = true
+			}
+		}
+		c.remove()
+	}
+	if err := ctx.pkg.setPart(name, comments); err != nil {
+		return err
+	}
+	return ctx.removeCommentExtensions(paraIDs)
+}
+
+// directive returns a starting marker of a block if a comment contains it
+func (doc *Docx) directive(comment *node) (marker, bool) {
+	var lines []string
+	for _, p := range comment.find("w:p") {
+		lines = append(lines, newParagraph(p).text)
+	}
+	text := strings.TrimSpace(strings.Join(lines, " "))
+	opening, closing := doc.openingBracket, doc.closingBracket
+	if strings.HasPrefix(text, opening) && strings.HasSuffix(text, closing) {
+		text = text[len(opening) : len(text)-len(closing)]
+	}
+	m, ok := parseMarker(placeholder{name: text})
+	return m, ok && !m.end && !m.alternative
+}
+
+// insertMarker puts a run with marker text next to a comment range boundary
+// and removes the boundary element
+func insertMarker(boundary *node, text string, end bool) {
+	r := newRun(nil, text)
+	switch {
+	case boundary.ancestor("w:p") != nil:
+		boundary.replace(r)
+	case end:
+		// range boundaries between paragraphs go to the adjacent paragraph
+		if p := adjacentParagraph(boundary, -1); p != nil {
+			p.append(r)
+		}
+		boundary.remove()
+	default:
+		if p := adjacentParagraph(boundary, 1); p != nil {
+			p.insert(firstRunIndex(p), r)
+		}
+		boundary.remove()
+	}
+}
+
+// firstRunIndex returns position where paragraph content starts (after its properties)
+func firstRunIndex(p *node) int {
+	if pPr := p.child("w:pPr"); pPr != nil {
+		return pPr.index() + 1
+	}
+	return 0
+}
+
+// adjacentParagraph returns the closest paragraph preceding (offset -1)
+// or following (offset 1) a node in document order
+func adjacentParagraph(n *node, offset int) *node {
+	var before, after *node
+	seen := false
+	n.root().walk(func(c *node) bool {
+		switch {
+		case c == n:
+			seen = true
+			return false
+		case c.is("w:p") && !seen:
+			before = c
+		case c.is("w:p") && after == nil:
+			after = c
+		}
+		return after == nil
+	})
+	if offset < 0 {
+		return before
+	}
+	return after
+}
+
+// removeCommentExtensions removes entries of removed comments from parts
+// which store additional information about comments (added in Word 2013 and later)
+func (ctx *renderContext) removeCommentExtensions(paraIDs map[string]bool) error {
+	durableIDs := make(map[string]bool)
+	extensions := []struct {
+		relType, tag, attr string
+	}{
+		{relTypeCommentsExtended, "w15:commentEx", "w15:paraId"},
+		{relTypeCommentsIds, "w16cid:commentId", "w16cid:paraId"},
+		{relTypeCommentsExtensible, "w16cex:commentExtensible", "w16cex:durableId"},
+	}
+	for _, ext := range extensions {
+		name, ok := ctx.pkg.relatedPart(ctx.part, ext.relType)
+		if !ok || !ctx.pkg.has(name) {
+			continue
+		}
+		root, ok, err := ctx.parseAuxiliaryPart(name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		for _, n := range root.find(ext.tag) {
+			id := n.attrValue(ext.attr)
+			if paraIDs[id] || durableIDs[id] {
+				durableIDs[n.attrValue("w16cid:durableId")] = true
+				n.remove()
+			}
+		}
+		if err := ctx.pkg.setPart(name, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Comment is a Word comment of a document
+type Comment struct {
+	ID       string    `json:"id"`
+	Author   string    `json:"author"`
+	Initials string    `json:"initials,omitempty"`
+	Date     time.Time `json:"date"`
+	Text     string    `json:"text"`
+	// Anchor is the commented text of the document body
+	Anchor   string    `json:"anchor"`
+	Resolved bool      `json:"resolved,omitempty"`
+	Replies  []Comment `json:"replies,omitempty"`
+}
+
+// Comments returns comments of the template with the text they comment on, replies
+// (stored by Word 2013 and later) are returned with the comments they answer
+func (doc *Docx) Comments() ([]Comment, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	name, ok := p.relatedPart(documentXML, relTypeComments)
+	if !ok || !p.has(name) {
+		return nil, nil
+	}
+	comments, err := p.parsePart(name)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.parsePart(documentXML)
+	if err != nil {
+		return nil, err
+	}
+	anchors := commentAnchors(body)
+	// parents of replies and resolved comments are kept by paragraph IDs in an extension
+	parents := make(map[string]string)
+	done := make(map[string]bool)
+	if name, ok := p.relatedPart(documentXML, relTypeCommentsExtended); ok && p.has(name) {
+		ext, err := p.parsePart(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range ext.find("w15:commentEx") {
+			id := n.attrValue("w15:paraId")
+			parents[id] = n.attrValue("w15:paraIdParent")
+			done[id] = n.attrValue("w15:done") == "1"
+		}
+	}
+	var list []*Comment
+	byParaID := make(map[string]*Comment)
+	paraIDs := make(map[*Comment]string)
+	for _, n := range comments.find("w:comment") {
+		c := &Comment{
+			ID:       n.attrValue("w:id"),
+			Author:   n.attrValue("w:author"),
+			Initials: n.attrValue("w:initials"),
+			Date:     parseDate(n.attrValue("w:date")),
+			Anchor:   anchors[n.attrValue("w:id")],
+		}
+		var lines []string
+		paraID := ""
+		for _, par := range n.find("w:p") {
+			lines = append(lines, newParagraph(par).text)
+			paraID = par.attrValue("w14:paraId")
+		}
+		c.Text = strings.Join(lines, "\n")
+		c.Resolved = done[paraID]
+		if paraID != "" {
+			byParaID[paraID] = c
+			paraIDs[c] = paraID
+		}
+		list = append(list, c)
+	}
+	// replies are added to their parents from the last, so nested replies are complete
+	for i := len(list) - 1; i >= 0; i-- {
+		if parent, ok := byParaID[parents[paraIDs[list[i]]]]; ok && parent != list[i] {
+			parent.Replies = append([]Comment{*list[i]}, parent.Replies...)
+			list[i] = nil
+		}
+	}
+	var result []Comment
+	for _, c := range list {
+		if c != nil {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+// commentAnchors returns text between starts and ends of comment ranges by IDs of comments
+func commentAnchors(root *node) map[string]string {
+	open := make(map[string]*strings.Builder)
+	anchors := make(map[string]string)
+	write := func(s string) {
+		for _, sb := range open {
+			sb.WriteString(s)
+		}
+	}
+	var visit func(n *node)
+	visit = func(n *node) {
+		switch n.tag {
+		case "w:commentRangeStart":
+			open[n.attrValue("w:id")] = new(strings.Builder)
+		case "w:commentRangeEnd":
+			if sb, ok := open[n.attrValue("w:id")]; ok {
+				anchors[n.attrValue("w:id")] = strings.TrimSuffix(sb.String(), "\n")
+				delete(open, n.attrValue("w:id"))
+			}
+		case "w:t":
+			write(n.text())
+		case "w:tab":
+			write("\t")
+		case "w:br", "w:cr":
+			write("\n")
+		case "w:del", "w:pPr", "w:rPr", "w:instrText":
+		default:
+			for _, c := range n.children {
+				visit(c)
+			}
+			if n.is("w:p") {
+				write("\n")
+			}
+		}
+	}
+	visit(root)
+	return anchors
+}
+
+// parseDate parses a date of a comment or a revision, it returns zero time if it's missing
+func parseDate(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,116 @@
+package docx
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommentDirectives(t *testing.T) {
+	body := p("Intro") +
+		`<w:p><w:commentRangeStart w:id="1"/><w:r><w:t>Discount applies</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>for [customer]</w:t></w:r><w:commentRangeEnd w:id="1"/>` +
+		`<w:r><w:commentReference w:id="1"/></w:r></w:p>` +
+		`<w:p><w:commentRangeStart w:id="2"/><w:r><w:t>Reviewed</w:t></w:r><w:commentRangeEnd w:id="2"/>` +
+		`<w:r><w:commentReference w:id="2"/></w:r></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeComments + `" Target="comments.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeCommentsExtended + `" Target="commentsExtended.xml"/>` +
+			`</Relationships>`,
+		"word/comments.xml": `<w:comments xmlns:w="w" xmlns:w14="w14">` +
+			`<w:comment w:id="1" w:author="Jane"><w:p w14:paraId="AA"><w:r><w:t>#if total &gt; 1000</w:t></w:r></w:p></w:comment>` +
+			`<w:comment w:id="2" w:author="Joe"><w:p w14:paraId="BB"><w:r><w:t>Looks good</w:t></w:r></w:p></w:comment>` +
+			`</w:comments>`,
+		"word/commentsExtended.xml": `<w15:commentsEx xmlns:w15="w15">` +
+			`<w15:commentEx w15:paraId="AA" w15:done="0"/><w15:commentEx w15:paraId="BB" w15:done="0"/>` +
+			`</w15:commentsEx>`,
+	}
+	dict := Dict{"total": 2000, "customer": "ACME"}
+	got := renderParts(t, newTestDocx(t, body, parts).ReplaceDict(dict))
+	want := p("Intro") + p("Discount applies") + p("for ACME") +
+		`<w:p><w:commentRangeStart w:id="2"></w:commentRangeStart><w:r><w:t>Reviewed</w:t></w:r>` +
+		`<w:commentRangeEnd w:id="2"></w:commentRangeEnd><w:r><w:commentReference w:id="2"></w:commentReference></w:r></w:p>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	if strings.Contains(got["word/comments.xml"], "#if") || !strings.Contains(got["word/comments.xml"], "Looks good") {
+		t.Errorf("Directive wasn't removed from comments: %s", got["word/comments.xml"])
+	}
+	if strings.Contains(got["word/commentsExtended.xml"], `"AA"`) || !strings.Contains(got["word/commentsExtended.xml"], `"BB"`) {
+		t.Errorf("Directive wasn't removed from comments: %s", got["word/commentsExtended.xml"])
+	}
+
+	dict["total"] = 10
+	got = renderParts(t, newTestDocx(t, body, parts).ReplaceDict(dict))
+	if strings.Contains(got[documentXML], "Discount") || !strings.Contains(got[documentXML], "Intro") {
+		t.Errorf("Block wasn't removed: %s", got[documentXML])
+	}
+}
+
+func TestComments(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">Pay </w:t></w:r><w:commentRangeStart w:id="1"/><w:commentRangeStart w:id="2"/>` +
+		`<w:r><w:t>within</w:t></w:r><w:r><w:tab/><w:t>30 days</w:t></w:r></w:p><w:p><w:r><w:t>of delivery</w:t></w:r>` +
+		`<w:commentRangeEnd w:id="1"/><w:commentRangeEnd w:id="2"/><w:r><w:commentReference w:id="1"/></w:r></w:p>` +
+		`<w:p><w:commentRangeStart w:id="3"/><w:r><w:t>Signed</w:t></w:r><w:commentRangeEnd w:id="3"/></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeComments + `" Target="comments.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeCommentsExtended + `" Target="commentsExtended.xml"/>` +
+			`</Relationships>`,
+		"word/comments.xml": `<w:comments xmlns:w="w" xmlns:w14="w14">` +
+			`<w:comment w:id="1" w:author="Jane" w:initials="JD" w:date="2024-03-01T10:00:00Z">` +
+			`<w:p w14:paraId="AA"><w:r><w:t>Too short?</w:t></w:r></w:p><w:p w14:paraId="AB"><w:r><w:t>Ask legal</w:t></w:r></w:p></w:comment>` +
+			`<w:comment w:id="2" w:author="Joe" w:date="2024-03-02T08:00:00Z"><w:p w14:paraId="BB"><w:r><w:t>It's fine</w:t></w:r></w:p></w:comment>` +
+			`<w:comment w:id="3" w:author="Joe"><w:p><w:r><w:t>Done</w:t></w:r></w:p></w:comment>` +
+			`</w:comments>`,
+		"word/commentsExtended.xml": `<w15:commentsEx xmlns:w15="w15">` +
+			`<w15:commentEx w15:paraId="AB" w15:done="1"/><w15:commentEx w15:paraId="BB" w15:paraIdParent="AB" w15:done="0"/>` +
+			`</w15:commentsEx>`,
+	}
+	got, err := newTestDocx(t, body, parts).Comments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Comment{
+		{ID: "1", Author: "Jane", Initials: "JD", Date: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+			Text: "Too short?\nAsk legal", Anchor: "within\t30 days\nof delivery", Resolved: true,
+			Replies: []Comment{{ID: "2", Author: "Joe", Date: time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC),
+				Text: "It's fine", Anchor: "within\t30 days\nof delivery"}}},
+		{ID: "3", Author: "Joe", Text: "Done", Anchor: "Signed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestResilientMode(t *testing.T) {
+	body := `<w:p><w:r><w:t>[customer]</w:t></w:r></w:p>`
+	broken := `<w:comments xmlns:w="w"><w:comment w:id="1"><w:p></w:comment`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeComments + `" Target="comments.xml"/>` +
+			`</Relationships>`,
+		"word/comments.xml": broken,
+	}
+	dict := Dict{"customer": "ACME"}
+	if _, err := newTestDocx(t, body, parts).ReplaceDict(dict).WriteTo(ioutil.Discard); err == nil {
+		t.Error("Expected an error of malformed comments")
+	}
+	doc := newTestDocx(t, body, parts).ReplaceDict(dict).Resilient(true)
+	got := renderParts(t, doc)
+	if !strings.Contains(got[documentXML], p("ACME")) {
+		t.Errorf("Document wasn't rendered: %s", got[documentXML])
+	}
+	if got["word/comments.xml"] != broken {
+		t.Errorf("Comments were changed: %s", got["word/comments.xml"])
+	}
+	if len(doc.Warnings()) != 1 || !strings.Contains(doc.Warnings()[0].Error(), "word/comments.xml") {
+		t.Errorf("Unexpected warnings: %v", doc.Warnings())
+	}
+}
@@ -0,0 +1,578 @@
+package docx
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Compare returns the revised document with its differences from the original marked as tracked
+// changes, like Compare of Word, so they can be reviewed, accepted or rejected. Paragraphs are
+// matched by their text and changed paragraphs are compared word by word. Revisions are made
+// by the author set by RevisionAuthor of the revised document. Pictures, hyperlinks, notes and
+// comments of deleted text aren't kept, because they belong to the original package.
+// The result should be saved with WriteTemplate, WriteTo would render it as a template
+func Compare(original, revised *Docx) *Docx {
+	result := *revised
+	result.parts = make(map[string][]byte, len(revised.parts)+1)
+	for name, data := range revised.parts {
+		result.parts[name] = data
+	}
+	result.added = append([]addedPart(nil), revised.added...)
+	result.removed = make(map[string]bool, len(revised.removed))
+	for name := range revised.removed {
+		result.removed[name] = true
+	}
+	if original.err != nil {
+		result.err = original.err
+	}
+	if result.err != nil {
+		return &result
+	}
+	a, err := original.Part(documentXML)
+	if err != nil {
+		result.err = err
+		return &result
+	}
+	b, err := revised.Part(documentXML)
+	if err != nil {
+		result.err = err
+		return &result
+	}
+	data, err := compareDocuments(a, b, revised.revisionAuthor())
+	if err != nil {
+		result.err = err
+		return &result
+	}
+	result.SetPart(documentXML, data)
+	return &result
+}
+
+// comparer marks differences of documents as revisions
+type comparer struct {
+	author Author
+	// next is ID of the next revision
+	next int
+}
+
+// compareDocuments returns the revised document.xml with tracked changes
+func compareDocuments(original, revised []byte, author Author) ([]byte, error) {
+	a, err := scan(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := scan(revised)
+	if err != nil {
+		return nil, err
+	}
+	aBody, bBody := documentBody(a), documentBody(b)
+	if aBody == nil || bBody == nil {
+		return nil, errors.New("Document has no body")
+	}
+	c := &comparer{author: author, next: maxID(a)}
+	if id := maxID(b); id > c.next {
+		c.next = id
+	}
+	c.next++
+	aBlocks, _ := bodyBlocks(aBody)
+	bBlocks, sectPr := bodyBlocks(bBody)
+	aKeys := make([]string, len(aBlocks))
+	for i, n := range aBlocks {
+		aKeys[i] = blockKey(n)
+	}
+	bKeys := make([]string, len(bBlocks))
+	for i, n := range bBlocks {
+		bKeys[i] = blockKey(n)
+	}
+	var blocks, deleted, inserted []*node
+	// flush pairs deleted and inserted paragraphs of a change and compares them word by word
+	flush := func() {
+		for len(deleted) > 0 && len(inserted) > 0 && comparable(deleted[0]) && comparable(inserted[0]) {
+			blocks = append(blocks, c.compareParagraphs(deleted[0], inserted[0]))
+			deleted, inserted = deleted[1:], inserted[1:]
+		}
+		for _, n := range deleted {
+			blocks = append(blocks, c.deleted(n))
+		}
+		for _, n := range inserted {
+			blocks = append(blocks, c.inserted(n))
+		}
+		deleted, inserted = nil, nil
+	}
+	i, j := 0, 0
+	for _, op := range diff(aKeys, bKeys) {
+		switch op {
+		case diffEqual:
+			flush()
+			blocks = append(blocks, bBlocks[j])
+			i++
+			j++
+		case diffDelete:
+			deleted = append(deleted, aBlocks[i])
+			i++
+		case diffInsert:
+			inserted = append(inserted, bBlocks[j])
+			j++
+		}
+	}
+	flush()
+	bBody.children = nil
+	bBody.append(blocks...)
+	if sectPr != nil {
+		bBody.append(sectPr)
+	}
+	return b.bytes()
+}
+
+// documentBody returns <w:body> of a parsed document.xml
+func documentBody(root *node) *node {
+	if el := root.documentElement(); el != nil {
+		return el.child("w:body")
+	}
+	return nil
+}
+
+// bodyBlocks returns elements of a body and its final section properties
+func bodyBlocks(body *node) ([]*node, *node) {
+	var blocks []*node
+	var sectPr *node
+	for _, n := range body.children {
+		switch {
+		case n.is("w:sectPr"):
+			sectPr = n
+		case n.tag != "":
+			blocks = append(blocks, n)
+		}
+	}
+	return blocks, sectPr
+}
+
+// maxID returns the highest numeric w:id of a part, IDs of revisions mustn't collide with them
+func maxID(root *node) int {
+	max := 0
+	root.walk(func(n *node) bool {
+		if id, err := strconv.Atoi(n.attrValue("w:id")); err == nil && id > max {
+			max = id
+		}
+		return true
+	})
+	return max
+}
+
+// blockKey returns a text by which blocks of compared documents are matched
+func blockKey(block *node) string {
+	var sb strings.Builder
+	sb.WriteString(block.tag)
+	visit := func(n *node) bool {
+		switch n.tag {
+		case "w:p":
+			sb.WriteByte(0)
+			if pPr := n.child("w:pPr"); pPr != nil {
+				if style := pPr.child("w:pStyle"); style != nil {
+					sb.WriteString(style.attrValue("w:val"))
+				}
+			}
+			sb.WriteByte(0)
+		case "w:t":
+			sb.WriteString(n.text())
+		case "w:tab":
+			sb.WriteByte('\t')
+		case "w:br", "w:cr":
+			sb.WriteByte('\n')
+		case "w:drawing", "w:pict", "w:object":
+			sb.WriteRune('\ufffc')
+			return false
+		case "w:pPr", "w:rPr", "w:del", "w:delText":
+			return false
+		}
+		return true
+	}
+	if visit(block) {
+		block.walk(visit)
+	}
+	return sb.String()
+}
+
+// comparableContent are elements of paragraphs which can be compared word by word
+var comparableContent = map[string]bool{
+	"w:pPr": true, "w:r": true, "w:bookmarkStart": true, "w:bookmarkEnd": true, "w:proofErr": true,
+	"w:rPr": true, "w:t": true, "w:tab": true, "w:br": true, "w:cr": true, "w:lastRenderedPageBreak": true,
+}
+
+// comparable reports whether a block is a paragraph of plain runs, other paragraphs are replaced whole
+func comparable(block *node) bool {
+	if !block.is("w:p") {
+		return false
+	}
+	ok := true
+	block.walk(func(n *node) bool {
+		if n.tag == "" {
+			return false
+		}
+		if !comparableContent[n.tag] {
+			ok = false
+		}
+		return ok && !n.is("w:pPr") && !n.is("w:rPr") && !n.is("w:t")
+	})
+	return ok
+}
+
+// revision creates <w:ins> or <w:del> element
+func (c *comparer) revision(tag string) *node {
+	id := strconv.Itoa(c.next)
+	c.next++
+	return elem(tag, "w:id", id, "w:author", c.author.Name, "w:date", c.author.Date.Format(time.RFC3339))
+}
+
+// inserted returns a copy of a block of the revised document marked as inserted
+func (c *comparer) inserted(block *node) *node {
+	block = block.clone()
+	c.markRevision(block, "w:ins")
+	return block
+}
+
+// deleted returns a copy of a block of the original document marked as deleted
+func (c *comparer) deleted(block *node) *node {
+	block = block.clone()
+	for _, n := range block.find("w:hyperlink") {
+		children := n.children
+		n.children = nil
+		n.replace(children...)
+	}
+	for _, tag := range []string{"w:drawing", "w:pict", "w:object", "w:footnoteReference", "w:endnoteReference",
+		"w:commentReference", "w:commentRangeStart", "w:commentRangeEnd", "w:bookmarkStart", "w:bookmarkEnd", "w:sectPr"} {
+		for _, n := range block.find(tag) {
+			n.remove()
+		}
+	}
+	for _, t := range block.find("w:t") {
+		t.tag = "w:delText"
+	}
+	for _, t := range block.find("w:instrText") {
+		t.tag = "w:delInstrText"
+	}
+	c.markRevision(block, "w:del")
+	return block
+}
+
+// markRevision wraps runs of a block into revisions and marks its paragraphs and rows
+func (c *comparer) markRevision(block *node, tag string) {
+	for _, r := range block.find("w:r") {
+		if r.ancestor("w:ins") != nil || r.ancestor("w:del") != nil {
+			continue
+		}
+		rev := c.revision(tag)
+		r.replace(rev)
+		rev.append(r)
+	}
+	paragraphs := block.find("w:p")
+	if block.is("w:p") {
+		paragraphs = append(paragraphs, block)
+	}
+	for _, p := range paragraphs {
+		c.markParagraph(p, tag)
+	}
+	for _, tr := range block.find("w:tr") {
+		rowProperties(tr).append(c.revision(tag))
+	}
+}
+
+// markParagraph marks the paragraph mark as inserted or deleted
+func (c *comparer) markParagraph(p *node, tag string) {
+	pPr := p.child("w:pPr")
+	if pPr == nil {
+		pPr = elem("w:pPr")
+		p.insert(0, pPr)
+	}
+	rPr := pPr.child("w:rPr")
+	if rPr == nil {
+		rPr = elem("w:rPr")
+		pPr.setChild(rPr, pPrOrder)
+	}
+	rPr.insert(0, c.revision(tag))
+}
+
+// token is a word, a space or a special character of a compared paragraph
+type token struct {
+	rPr *node
+	// content is text of a word or an element like <w:tab>
+	text    string
+	content *node
+	// before are bookmarks preceding the token
+	before []*node
+}
+
+func (t token) key() string {
+	if t.content != nil {
+		return t.content.tag
+	}
+	return t.text
+}
+
+// tokens splits runs of a paragraph to tokens, bookmarks at the end are returned separately
+func tokens(p *node) ([]token, []*node) {
+	var list []token
+	var before []*node
+	for _, n := range p.children {
+		switch {
+		case n.is("w:bookmarkStart"), n.is("w:bookmarkEnd"):
+			before = append(before, n)
+		case n.is("w:r"):
+			rPr := n.child("w:rPr")
+			for _, c := range n.children {
+				switch {
+				case c.is("w:t"):
+					for _, word := range splitWords(c.text()) {
+						list = append(list, token{rPr: rPr, text: word, before: before})
+						before = nil
+					}
+				case c.is("w:tab"), c.is("w:br"), c.is("w:cr"):
+					list = append(list, token{rPr: rPr, content: c, before: before})
+					before = nil
+				}
+			}
+		}
+	}
+	return list, before
+}
+
+// splitWords splits text into words, spaces and other characters
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	prev := -1
+	for i, r := range s {
+		cls := class(r)
+		if i > start && (cls != prev || cls == 0) {
+			words = append(words, s[start:i])
+			start = i
+		}
+		prev = cls
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// compareParagraphs returns the revised paragraph with words of the original one which were
+// deleted and inserted words marked as revisions
+func (c *comparer) compareParagraphs(original, revised *node) *node {
+	aTokens, _ := tokens(original)
+	bTokens, end := tokens(revised)
+	aKeys := make([]string, len(aTokens))
+	for i, t := range aTokens {
+		aKeys[i] = t.key()
+	}
+	bKeys := make([]string, len(bTokens))
+	for i, t := range bTokens {
+		bKeys[i] = t.key()
+	}
+	p := elem("w:p")
+	for _, a := range revised.attr {
+		p.setAttr(a.Name.Local, a.Value)
+	}
+	if pPr := revised.child("w:pPr"); pPr != nil {
+		p.append(pPr.clone())
+	}
+	var runs []*node
+	var rev *node
+	var op diffOp = -1
+	// emit adds a token to the last run if they have the same formatting and revision
+	emit := func(t token, tokenOp diffOp) {
+		for _, n := range t.before {
+			p.append(n.clone())
+			op = -1
+		}
+		if op != tokenOp {
+			op = tokenOp
+			runs = nil
+			switch op {
+			case diffDelete:
+				rev = c.revision("w:del")
+				p.append(rev)
+			case diffInsert:
+				rev = c.revision("w:ins")
+				p.append(rev)
+			default:
+				rev = p
+			}
+		}
+		var r *node
+		if len(runs) > 0 && sameProperties(runs[len(runs)-1].child("w:rPr"), t.rPr) {
+			r = runs[len(runs)-1]
+		} else {
+			r = elem("w:r")
+			if t.rPr != nil {
+				r.append(t.rPr.clone())
+			}
+			rev.append(r)
+			runs = append(runs, r)
+		}
+		if t.content != nil {
+			r.append(t.content.clone())
+			return
+		}
+		tag := "w:t"
+		if op == diffDelete {
+			tag = "w:delText"
+		}
+		text := t.text
+		if last := r.children; len(last) > 0 && last[len(last)-1].is(tag) {
+			text = last[len(last)-1].text() + text
+			last[len(last)-1].remove()
+		}
+		tNode := elem(tag)
+		setRunText(tNode, text)
+		r.append(tNode)
+	}
+	i, j := 0, 0
+	for _, o := range diff(aKeys, bKeys) {
+		switch o {
+		case diffEqual:
+			emit(bTokens[j], diffEqual)
+			i++
+			j++
+		case diffDelete:
+			t := aTokens[i]
+			t.before = nil
+			emit(t, diffDelete)
+			i++
+		case diffInsert:
+			emit(bTokens[j], diffInsert)
+			j++
+		}
+	}
+	for _, n := range end {
+		p.append(n.clone())
+	}
+	return p
+}
+
+// sameProperties reports whether runs have the same formatting
+func sameProperties(a, b *node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	aData, errA := a.bytes()
+	bData, errB := b.bytes()
+	return errA == nil && errB == nil && string(aData) == string(bData)
+}
+
+// diffOp is an operation of an edit script
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+// diff returns the shortest edit script turning a into b, found by Myers' algorithm in linear
+// space: long documents don't need a table of all pairs of their blocks
+func diff(a, b []string) []diffOp {
+	return appendDiff(make([]diffOp, 0, len(a)+len(b)), a, b)
+}
+
+// appendDiff appends the edit script turning a into b, splitting it at the middle snake
+func appendDiff(ops []diffOp, a, b []string) []diffOp {
+	// common prefix and suffix don't need the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for k := 0; k < prefix; k++ {
+		ops = append(ops, diffEqual)
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	switch {
+	case len(a) == 0:
+		for range b {
+			ops = append(ops, diffInsert)
+		}
+	case len(b) == 0:
+		for range a {
+			ops = append(ops, diffDelete)
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		ops = appendDiff(ops, a[:x], b[:y])
+		for k := x; k < u; k++ {
+			ops = append(ops, diffEqual)
+		}
+		ops = appendDiff(ops, a[u:], b[v:])
+	}
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, diffEqual)
+	}
+	return ops
+}
+
+// middleSnake returns the snake from (x, y) to (u, v) in the middle of a shortest edit path,
+// searching from both ends at once. a and b differ in their first and last elements, so the
+// parts before and after the snake are always smaller
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	max := (n + m + 1) / 2
+	delta := n - m
+	odd := delta%2 != 0
+	// forward[offset+k] is the furthest x on diagonal k = x-y from the start,
+	// backward[offset+k] is the furthest distance on diagonal k of the reversed sequences
+	offset := max + 1
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+			forward[offset+k] = u
+			if c := delta - k; odd && c >= -(d-1) && c <= d-1 && u+backward[offset+c] >= n {
+				return x, y, u, v
+			}
+		}
+		for c := -d; c <= d; c += 2 {
+			var rx int
+			if c == -d || (c != d && backward[offset+c-1] < backward[offset+c+1]) {
+				rx = backward[offset+c+1]
+			} else {
+				rx = backward[offset+c-1] + 1
+			}
+			ry := rx - c
+			ru, rv := rx, ry
+			for ru < n && rv < m && a[n-1-ru] == b[m-1-rv] {
+				ru++
+				rv++
+			}
+			backward[offset+c] = ru
+			if k := delta - c; !odd && k >= -d && k <= d && forward[offset+k]+ru >= n {
+				return n - ru, m - rv, n - rx, m - ry
+			}
+		}
+	}
+	// unreachable, a path of at most n+m edits always exists
+	return n, m, n, m
+}
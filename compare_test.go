@@ -0,0 +1,131 @@
+package docx
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	original := newTestDocx(t, `<w:p><w:r><w:t>The quick fox</w:t></w:r></w:p>`+
+		`<w:p><w:bookmarkStart w:id="7" w:name="gone"/><w:hyperlink r:id="rId9"><w:r><w:t>Gone</w:t></w:r></w:hyperlink><w:bookmarkEnd w:id="7"/></w:p>`+
+		`<w:p><w:r><w:t>Unchanged</w:t></w:r></w:p><w:sectPr></w:sectPr>`, nil)
+	revised := newTestDocx(t, `<w:p><w:r><w:t>The slow fox</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>Unchanged</w:t></w:r></w:p><w:tbl><w:tr><w:tc><w:p><w:r><w:t>Added</w:t></w:r></w:p></w:tc></w:tr></w:tbl><w:sectPr></w:sectPr>`, nil).
+		RevisionAuthor(Author{Name: "Jane", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	got, err := Compare(original, revised).Part(documentXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rev := func(tag string, id int) string {
+		return `<w:` + tag + ` w:id="` + strconv.Itoa(id) + `" w:author="Jane" w:date="2024-03-01T00:00:00Z">`
+	}
+	want := `<w:body><w:p><w:r><w:t xml:space="preserve">The </w:t></w:r>` +
+		rev("del", 8) + `<w:r><w:delText>quick</w:delText></w:r></w:del>` +
+		rev("ins", 9) + `<w:r><w:t>slow</w:t></w:r></w:ins><w:r><w:t xml:space="preserve"> fox</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:rPr>` + rev("del", 11) + `</w:del></w:rPr></w:pPr>` +
+		rev("del", 10) + `<w:r><w:delText>Gone</w:delText></w:r></w:del></w:p>` +
+		`<w:p><w:r><w:t>Unchanged</w:t></w:r></w:p>` +
+		`<w:tbl><w:tr><w:trPr>` + rev("ins", 14) + `</w:ins></w:trPr><w:tc><w:p><w:pPr><w:rPr>` + rev("ins", 13) + `</w:ins></w:rPr></w:pPr>` +
+		rev("ins", 12) + `<w:r><w:t>Added</w:t></w:r></w:ins></w:p></w:tc></w:tr></w:tbl>` +
+		`<w:sectPr></w:sectPr></w:body>`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := diff(strings.Split("a b c d", " "), strings.Split("a x c d e", " "))
+	want := []diffOp{diffEqual, diffDelete, diffInsert, diffEqual, diffEqual, diffInsert}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	// a table of all pairs would take 10001*10001 ints
+	a, b := make([]string, 10000), make([]string, 10000)
+	for i := range a {
+		a[i] = strconv.Itoa(i)
+		b[i] = a[i]
+	}
+	b[0], b[5000], b[len(b)-1] = "first", "middle", "last"
+	got := diff(a, b)
+	if len(got) != len(a)+3 {
+		t.Fatalf("Expected 3 changed blocks, got %d operations", len(got))
+	}
+	if out := applyDiff(a, b, got); !reflect.DeepEqual(out, b) {
+		t.Error("Expected the edit script to turn a into b")
+	}
+}
+
+func TestDiffShortest(t *testing.T) {
+	words := func(s string) []string { return strings.Fields(s) }
+	for _, c := range [][2]string{
+		{"", "a b"}, {"a b", ""}, {"a", "b"}, {"a b c a b b a", "c b a b a c"},
+		{"x a y b z c", "a b c"}, {"a b c d e f", "f e d c b a"}, {"a a a b", "b a a a"},
+	} {
+		a, b := words(c[0]), words(c[1])
+		got := diff(a, b)
+		if out := applyDiff(a, b, got); !reflect.DeepEqual(out, b) {
+			t.Errorf("%q to %q: got %v", c[0], c[1], got)
+		}
+		equal := 0
+		for _, op := range got {
+			if op == diffEqual {
+				equal++
+			}
+		}
+		if want := lcsLength(a, b); equal != want {
+			t.Errorf("%q to %q: expected %d equal elements, got %d", c[0], c[1], want, equal)
+		}
+	}
+}
+
+// applyDiff turns a into b following ops, taking inserted elements from b
+func applyDiff(a, b []string, ops []diffOp) []string {
+	out := []string{}
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case diffEqual:
+			if i >= len(a) || j >= len(b) || a[i] != b[j] {
+				return nil
+			}
+			out = append(out, a[i])
+			i++
+			j++
+		case diffDelete:
+			i++
+		case diffInsert:
+			out = append(out, b[j])
+			j++
+		}
+	}
+	if i != len(a) {
+		return nil
+	}
+	return out
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b
+func lcsLength(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] > lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs[0][0]
+}
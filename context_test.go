@@ -0,0 +1,33 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWriteToContext(t *testing.T) {
+	body := strings.Repeat(p("[name]"), 100)
+	var buf bytes.Buffer
+	if _, err := newTestDocx(t, body, nil).ReplaceDict(Dict{"name": "Ann"}).WriteToContext(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// values are computed while the document is rendered, so the context can be cancelled in the middle
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	doc := newTestDocx(t, body, nil).ReplaceFunc(func(placeholder string) (string, bool) {
+		calls++
+		if calls == 10 {
+			cancel()
+		}
+		return "Ann", true
+	})
+	if _, err := doc.WriteToContext(ctx, new(bytes.Buffer)); err != context.Canceled {
+		t.Errorf("Expected error of the cancelled context, got %v", err)
+	}
+	if calls != 10 {
+		t.Errorf("Expected rendering to stop after 10 placeholders, got %d", calls)
+	}
+}
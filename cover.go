@@ -0,0 +1,81 @@
+package docx
+
+// coverFields are variables shown on the built-in cover page with styles of their paragraphs
+var coverFields = []struct {
+	name, style string
+}{
+	{"logo", ""},
+	{"title", "Title"},
+	{"subtitle", "Subtitle"},
+	{"author", ""},
+	{"date", ""},
+}
+
+// CoverPage adds a cover page at the beginning of the document, it's followed by a page break.
+// The cover page is a building block of the template (like one of Word cover pages
+// with placeholders) or, when the name is empty, a simple built-in layout which shows
+// logo, title, subtitle, author and date variables of the dictionary
+func (doc *Docx) CoverPage(buildingBlock BuildingBlock) *Docx {
+	doc.coverPage = &buildingBlock
+	return doc
+}
+
+// insertCoverPage puts the cover page before the first paragraph of the document body
+func (ctx *renderContext) insertCoverPage() error {
+	body := ctx.root.find("w:body")
+	if ctx.doc.coverPage == nil || len(body) == 0 {
+		return nil
+	}
+	var blocks []*node
+	if *ctx.doc.coverPage != "" {
+		b, err := ctx.doc.coverPage.blocks(ctx)
+		if err != nil {
+			return err
+		}
+		blocks = b
+	} else {
+		blocks = ctx.builtinCoverPage()
+	}
+	if !hasPageBreak(blocks) {
+		blocks = append(blocks, pageBreak())
+	}
+	body[0].insert(0, blocks...)
+	return ctx.renderBlocks(blocks)
+}
+
+// builtinCoverPage returns paragraphs with placeholders of cover page variables
+// which are present in the dictionary
+func (ctx *renderContext) builtinCoverPage() []*node {
+	var blocks []*node
+	for _, field := range coverFields {
+		if _, ok := ctx.global.lookup(field.name); !ok {
+			continue
+		}
+		pPr := elem("w:pPr")
+		if field.style != "" {
+			pPr.setChild(elem("w:pStyle", "w:val", field.style), pPrOrder)
+		}
+		if len(blocks) == 0 {
+			// the first line is moved down to the upper third of the page
+			pPr.setChild(elem("w:spacing", "w:before", "2880"), pPrOrder)
+		}
+		pPr.setChild(elem("w:jc", "w:val", "center"), pPrOrder)
+		p := elem("w:p")
+		p.append(pPr, newRun(nil, ctx.doc.openingBracket+field.name+ctx.doc.closingBracket))
+		blocks = append(blocks, p)
+	}
+	return blocks
+}
+
+// hasPageBreak checks if content ends with a page break
+func hasPageBreak(blocks []*node) bool {
+	if len(blocks) == 0 {
+		return false
+	}
+	found := false
+	blocks[len(blocks)-1].walk(func(n *node) bool {
+		found = found || (n.is("w:br") && n.attrValue("w:type") == "page")
+		return !found
+	})
+	return found
+}
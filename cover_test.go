@@ -0,0 +1,28 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestCoverPage(t *testing.T) {
+	dict := Dict{"title": "Annual report", "author": "Jane Doe", "year": 2024}
+	got := renderBody(t, newTestDocx(t, p("Content"), nil).ReplaceDict(dict).CoverPage(""))
+	want := `<w:p><w:pPr><w:pStyle w:val="Title"></w:pStyle><w:spacing w:before="2880"></w:spacing>` +
+		`<w:jc w:val="center"></w:jc></w:pPr><w:r><w:t>Annual report</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:jc w:val="center"></w:jc></w:pPr><w:r><w:t>Jane Doe</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:br w:type="page"></w:br></w:r></w:p>` + p("Content")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	parts := glossaryParts()
+	parts["word/glossary/document.xml"] = `<w:glossaryDocument xmlns:w="w"><w:docParts><w:docPart>` +
+		`<w:docPartPr><w:name w:val="Report cover"/></w:docPartPr><w:docPartBody>` +
+		p("[title] ([year])") + `<w:p><w:r><w:br w:type="page"/></w:r></w:p>` +
+		`</w:docPartBody></w:docPart></w:docParts></w:glossaryDocument>`
+	got = renderBody(t, newTestDocx(t, p("Content"), parts).ReplaceDict(dict).CoverPage("Report cover"))
+	want = p("Annual report (2024)") + `<w:p><w:r><w:br w:type="page"></w:br></w:r></w:p>` + p("Content")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDateFormat is the format of date content controls without w:dateFormat
+const defaultDateFormat = "M/d/yyyy"
+
+// SetDate puts a date into date picker content controls with given tag (or title).
+// Both the stored value and the text shown in the document are set, the text uses the format
+// of the control, like "dd.MM.yyyy" or "MMMM d, yyyy". Names of months and days are English
+func (doc *Docx) SetDate(tag string, t time.Time) *Docx {
+	if doc.dates == nil {
+		doc.dates = make(map[string]time.Time)
+	}
+	doc.dates[tag] = t
+	return doc
+}
+
+// setDates fills date content controls of a part
+func (ctx *renderContext) setDates() error {
+	if len(ctx.doc.dates) == 0 {
+		return nil
+	}
+	for _, sdt := range ctx.root.find("w:sdt") {
+		t, ok := ctx.doc.dates[sdtName(sdt)]
+		date := sdtProperty(sdt, "w:date")
+		if !ok || date == nil {
+			continue
+		}
+		// Word stores the date as local time marked as UTC
+		date.setAttr("w:fullDate", t.Format("2006-01-02T15:04:05")+"Z")
+		format := defaultDateFormat
+		if f := date.child("w:dateFormat"); f != nil && f.attrValue("w:val") != "" {
+			format = f.attrValue("w:val")
+		}
+		if err := ctx.fillControl(sdt, formatWordDate(t, format)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatWordDate formats a time with a Word date picture like "dddd, MMMM d, yyyy h:mm am/pm",
+// text in quotes is copied
+func formatWordDate(t time.Time, format string) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c == '\'' {
+			end := strings.IndexByte(format[i+1:], '\'')
+			if end == -1 {
+				end = len(format) - i - 1
+			}
+			sb.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(format[i:]), "am/pm") {
+			ampm := t.Format("PM")
+			if format[i] == 'a' {
+				ampm = strings.ToLower(ampm)
+			}
+			sb.WriteString(ampm)
+			i += len("am/pm")
+			continue
+		}
+		n := 1
+		for i+n < len(format) && format[i+n] == c {
+			n++
+		}
+		sb.WriteString(dateToken(t, c, n, format[i:i+n]))
+		i += n
+	}
+	return sb.String()
+}
+
+// dateToken formats a run of n equal letters of a Word date picture, other characters are copied
+func dateToken(t time.Time, c byte, n int, token string) string {
+	number := func(v int) string {
+		if n >= 2 {
+			return fmt.Sprintf("%02d", v)
+		}
+		return strconv.Itoa(v)
+	}
+	switch c {
+	case 'd':
+		switch n {
+		case 1, 2:
+			return number(t.Day())
+		case 3:
+			return t.Format("Mon")
+		}
+		return t.Format("Monday")
+	case 'M':
+		switch n {
+		case 1, 2:
+			return number(int(t.Month()))
+		case 3:
+			return t.Format("Jan")
+		}
+		return t.Format("January")
+	case 'y':
+		if n <= 2 {
+			return fmt.Sprintf("%02d", t.Year()%100)
+		}
+		return strconv.Itoa(t.Year())
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return number(h)
+	case 'H':
+		return number(t.Hour())
+	case 'm':
+		return number(t.Minute())
+	case 's':
+		return number(t.Second())
+	}
+	return token
+}
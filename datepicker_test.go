@@ -0,0 +1,47 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetDate(t *testing.T) {
+	date := func(tag, format string) string {
+		return `<w:p>` + sdt(`<w:tag w:val="`+tag+`"/><w:showingPlcHdr/><w:date>`+format+`<w:lid w:val="en-US"/></w:date>`,
+			`<w:r><w:rPr><w:rStyle w:val="PlaceholderText"/><w:i/></w:rPr><w:t>Click to enter a date</w:t></w:r>`) + `</w:p>`
+	}
+	body := date("signed", `<w:dateFormat w:val="dd.MM.yyyy"/>`) + date("due", "") + date("other", "")
+	day := time.Date(2024, time.May, 1, 14, 5, 0, 0, time.UTC)
+	got := renderBody(t, newTestDocx(t, body, nil).SetDate("signed", day).SetDate("due", day))
+	for _, want := range []string{
+		`<w:date w:fullDate="2024-05-01T14:05:00Z"><w:dateFormat w:val="dd.MM.yyyy"></w:dateFormat>`,
+		`<w:r><w:rPr><w:i></w:i></w:rPr><w:t>01.05.2024</w:t></w:r>`,
+		`<w:r><w:rPr><w:i></w:i></w:rPr><w:t>5/1/2024</w:t></w:r>`,
+		`Click to enter a date`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+	if strings.Count(got, "<w:showingPlcHdr>") != 1 {
+		t.Errorf("Placeholder flags weren't removed: %s", got)
+	}
+}
+
+func TestFormatWordDate(t *testing.T) {
+	day := time.Date(2024, time.May, 1, 0, 5, 9, 0, time.UTC)
+	for format, want := range map[string]string{
+		"dddd, MMMM d, yyyy":    "Wednesday, May 1, 2024",
+		"ddd d MMM yy":          "Wed 1 May 24",
+		"yyyy-MM-dd HH:mm:ss":   "2024-05-01 00:05:09",
+		"h:mm AM/PM":            "12:05 AM",
+		"hh:mm am/pm":           "12:05 am",
+		"'Day' d 'of' MMMM":     "Day 1 of May",
+		"d. M. yyyy 'o''clock'": "1. 5. 2024 oclock",
+	} {
+		if got := formatWordDate(day, format); got != want {
+			t.Errorf("%s: got %q, want %q", format, got, want)
+		}
+	}
+}
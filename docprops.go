@@ -0,0 +1,221 @@
+package docx
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	relTypeCustomProperties     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	contentTypeCustomProperties = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+	customPropertiesXML         = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" ` +
+		`xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"></Properties>`
+	// fmtidUserDefined is the format ID of user defined properties
+	fmtidUserDefined = "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}"
+)
+
+// customProperty is a custom property set by an option, typ is a type
+// of its value like "vt:filetime", the default is text
+type customProperty struct {
+	name, value, typ string
+}
+
+// setCustomProperties updates custom document properties (File → Properties → Custom)
+// which names are keys of the dictionary, like "Client" or "[Client]"
+func (ctx *renderContext) setCustomProperties() error {
+	name, ok := ctx.pkg.relatedPart("", relTypeCustomProperties)
+	if !ok || !ctx.pkg.has(name) {
+		return nil
+	}
+	root, ok, err := ctx.parseAuxiliaryPart(name)
+	if !ok {
+		return err
+	}
+	changed := false
+	for _, property := range root.find("property") {
+		text, ok, err := ctx.propertyValue(ctx.global, property.attrValue("name"))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		// the value becomes a text property, whatever its type was
+		value := elem("vt:lpwstr")
+		value.setText(text)
+		property.children = nil
+		property.append(value)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return ctx.pkg.setPart(name, root)
+}
+
+// addCustomProperties sets custom properties, they are added if they don't exist
+// and the part with custom properties is created if the document has none
+func (ctx *renderContext) addCustomProperties(properties []customProperty) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	name, ok := ctx.pkg.relatedPart("", relTypeCustomProperties)
+	var root *node
+	if ok && ctx.pkg.has(name) {
+		var err error
+		if root, ok, err = ctx.parseAuxiliaryPart(name); !ok {
+			return err
+		}
+	} else {
+		name = "docProps/custom.xml"
+		var err error
+		if root, err = scan([]byte(customPropertiesXML)); err != nil {
+			return err
+		}
+		rels, err := ctx.pkg.relationships("")
+		if err != nil {
+			return err
+		}
+		ct, err := ctx.pkg.types()
+		if err != nil {
+			return err
+		}
+		rels.add(relTypeCustomProperties, name)
+		ct.addOverride(name, contentTypeCustomProperties)
+	}
+	el := root.documentElement()
+	if el == nil {
+		return nil
+	}
+	existing := make(map[string]*node)
+	// IDs of properties start at 2
+	pid := 1
+	for _, property := range el.elements("property") {
+		existing[property.attrValue("name")] = property
+		if id, err := strconv.Atoi(property.attrValue("pid")); err == nil && id > pid {
+			pid = id
+		}
+	}
+	for _, p := range properties {
+		property, ok := existing[p.name]
+		if !ok {
+			pid++
+			property = elem("property", "fmtid", fmtidUserDefined, "pid", strconv.Itoa(pid), "name", p.name)
+			el.append(property)
+		}
+		typ := p.typ
+		if typ == "" {
+			typ = "vt:lpwstr"
+		}
+		value := elem(typ)
+		value.setText(p.value)
+		property.children = nil
+		property.append(value)
+	}
+	return ctx.pkg.setPart(name, root)
+}
+
+// propertyValue returns a text value of a document property from the dictionary
+func (ctx *renderContext) propertyValue(s *scope, name string) (string, bool, error) {
+	if name == "" {
+		return "", false, nil
+	}
+	v, ok := s.lookup(name)
+	if !ok {
+		return "", false, nil
+	}
+	value, err := toValue(v)
+	if err != nil {
+		return "", false, err
+	}
+	text, ok := value.(Text)
+	return string(text), ok, nil
+}
+
+// updatePropertyFields puts values of document properties into cached results
+// of DOCPROPERTY fields, so they are right even when fields aren't updated
+func (ctx *renderContext) updatePropertyFields() error {
+	for _, field := range ctx.root.find("w:fldSimple") {
+		if err := ctx.setFieldResult(field, field.attrValue("w:instr"), field.find("w:t")); err != nil {
+			return err
+		}
+	}
+	for _, p := range ctx.root.find("w:p") {
+		// only fields which aren't nested in other fields are updated
+		var depth int
+		var instr strings.Builder
+		var result []*node
+		var inResult bool
+		var err error
+		p.walk(func(n *node) bool {
+			switch {
+			case n.is("w:p"), n.is("w:fldSimple"):
+				return false
+			case n.is("w:fldChar"):
+				switch n.attrValue("w:fldCharType") {
+				case "begin":
+					depth++
+					if depth == 1 {
+						instr.Reset()
+						result, inResult = nil, false
+					}
+				case "separate":
+					inResult = inResult || depth == 1
+				case "end":
+					if depth == 1 && err == nil {
+						err = ctx.setFieldResult(p, instr.String(), result)
+					}
+					if depth > 0 {
+						depth--
+					}
+				}
+			case n.is("w:instrText") && depth == 1 && !inResult:
+				instr.WriteString(n.text())
+			case n.is("w:t") && depth == 1 && inResult:
+				result = append(result, n)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFieldResult replaces the result of a DOCPROPERTY field, the result keeps formatting of its first run
+func (ctx *renderContext) setFieldResult(n *node, instr string, result []*node) error {
+	name, ok := propertyName(instr)
+	if !ok || len(result) == 0 {
+		return nil
+	}
+	text, ok, err := ctx.propertyValue(ctx.scopeOf(n), name)
+	if err != nil || !ok {
+		return err
+	}
+	setRunText(result[0], text)
+	for _, t := range result[1:] {
+		t.setText("")
+	}
+	return nil
+}
+
+// propertyName returns a property name of a DOCPROPERTY field instruction,
+// like Client in DOCPROPERTY Client \* MERGEFORMAT
+func propertyName(instr string) (string, bool) {
+	fields := strings.Fields(instr)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "DOCPROPERTY") {
+		return "", false
+	}
+	rest := strings.TrimSpace(instr)[len(fields[0]):]
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.Index(rest[1:], `"`)
+		if end == -1 {
+			return "", false
+		}
+		return rest[1 : end+1], true
+	}
+	return fields[1], true
+}
@@ -0,0 +1,50 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocProperties(t *testing.T) {
+	parts := map[string]string{
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeCustomProperties + `" Target="docProps/custom.xml"/>` +
+			`</Relationships>`,
+		"docProps/custom.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" ` +
+			`xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes">` +
+			`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="Client"><vt:lpwstr>Client name</vt:lpwstr></property>` +
+			`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="3" name="Project Year"><vt:i4>2020</vt:i4></property>` +
+			`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="4" name="Other"><vt:lpwstr>Other</vt:lpwstr></property>` +
+			`</Properties>`,
+	}
+	body := `<w:p><w:fldSimple w:instr=" DOCPROPERTY Client \* MERGEFORMAT "><w:r><w:t>Client name</w:t></w:r></w:fldSimple></w:p>` +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"></w:fldChar></w:r>` +
+		`<w:r><w:instrText xml:space="preserve"> DOCPROPERTY  &#34;Project Year&#34; </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"></w:fldChar></w:r>` +
+		`<w:r><w:t>20</w:t></w:r><w:r><w:t>20</w:t></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="end"></w:fldChar></w:r></w:p>` +
+		`<w:p><w:fldSimple w:instr=" DOCPROPERTY Other "><w:r><w:t>Other</w:t></w:r></w:fldSimple></w:p>`
+	doc := newTestDocx(t, body, parts).ReplaceDict(Dict{"Client": "ACME Inc.", "[Project Year]": 2024})
+	got := renderParts(t, doc)
+	for _, want := range []string{
+		`<w:fldSimple w:instr=" DOCPROPERTY Client \* MERGEFORMAT "><w:r><w:t>ACME Inc.</w:t></w:r></w:fldSimple>`,
+		`<w:r><w:t>2024</w:t></w:r><w:r><w:t></w:t></w:r>`,
+		`<w:fldSimple w:instr=" DOCPROPERTY Other "><w:r><w:t>Other</w:t></w:r></w:fldSimple>`,
+	} {
+		if !strings.Contains(got[documentXML], want) {
+			t.Errorf("%s not found in %s", want, got[documentXML])
+		}
+	}
+	for _, want := range []string{
+		`name="Client"><vt:lpwstr>ACME Inc.</vt:lpwstr></property>`,
+		`name="Project Year"><vt:lpwstr>2024</vt:lpwstr></property>`,
+		`name="Other"><vt:lpwstr>Other</vt:lpwstr></property>`,
+	} {
+		if !strings.Contains(got["docProps/custom.xml"], want) {
+			t.Errorf("%s not found in %s", want, got["docProps/custom.xml"])
+		}
+	}
+}
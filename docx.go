@@ -3,37 +3,117 @@ package docx
 import (
 	"archive/zip"
 	"bytes"
-	"encoding/xml"
-	"fmt"
+	"context"
+	"errors"
 	"io"
-	"strings"
+	"time"
 )
 
 const documentXML = "word/document.xml"
 
 // Docx can manipulate .docx files created by MS Word 2007+
 type Docx struct {
-	zipReader      *zip.Reader
+	zipReader *zip.Reader
+	// file is a template opened by OpenFile or OpenFS
+	file           io.Closer
 	err            error
 	dict           Dict
-	openingBracket rune
-	closingBracket rune
+	replaceFunc    func(placeholder string) (string, bool)
+	openingBracket string
+	closingBracket string
+	filters        map[string]Filter
+	formats        map[string][]FormatRule
+	checkboxes     *CheckboxStyle
+	locale         string
+	bookmarks      map[string]interface{}
+	pictures       map[string]Image
+	images         map[string]Image
+	dates          map[string]time.Time
+	regexps        []regexpReplacement
+	partials       map[string]SubDocument
+	coverPage      *BuildingBlock
+	labels         []Dict
+	envelopes      []Dict
+	invoice        *Invoice
+	headers        []headerContent
+	titlePage      *bool
+	evenAndOdd     *bool
+	lineNumbers    *LineNumbering
+	// renderedParts are patterns of parts rendered besides document.xml, nil is defaultRenderedParts
+	renderedParts []string
+	// simpleFieldInstructions enables replacing placeholders in instructions of simple fields
+	simpleFieldInstructions bool
+	// lineBreaks turns new lines of values into line breaks and paragraphs
+	lineBreaks bool
+	// tabs turns tab characters of values into tabs
+	tabs bool
+	// invalidCharacters is a policy of characters of values which can't be written in XML
+	invalidCharacters CharacterPolicy
+	// goTemplate executes text of rendered parts as a text/template
+	goTemplate *goTemplate
+	// mustache turns tags of Mustache sections into blocks
+	mustache bool
+	// limits are maximum numbers of replaced placeholders of variables
+	limits map[string]int
+	// ignoreCase and normalizeSpace relax matching of placeholders with keys of the dictionary
+	ignoreCase     bool
+	normalizeSpace bool
+	// parts override or add parts of the archive
+	parts         map[string][]byte
+	added         []addedPart
+	removed       map[string]bool
+	thumbnail     []byte
+	thumbnailFunc func(document []byte) ([]byte, error)
+	// resilient mode leaves malformed auxiliary parts unchanged
+	resilient bool
+	// strict mode fails on placeholders without values
+	strict   bool
+	warnings []error
+	// memoryLimit is the maximum size of changed parts kept in memory
+	memoryLimit int64
+	flushPolicy FlushPolicy
+	// jpegQuality is the quality of JPEG images recompressed by Optimize, 0 keeps them
+	jpegQuality int
+	// reproducible is the modification time of all entries of the output, nil keeps times of the template
+	reproducible *time.Time
+	// templateInfo is stamped into custom properties
+	templateInfo *templateInfo
+	audit        *audit
+	// reviewComments attaches comments to replaced values
+	reviewComments bool
+	// author of comments and revisions written by the library
+	author Author
+	// values are added by helpers, the dictionary takes precedence over them
+	values Dict
 }
 
-// Dict is a dictionary with variables and values to which they should be replaced
-type Dict map[string]string
+// Dict is a dictionary with variables and values to which they should be replaced.
+// Values can be strings, Text, RichText, Image, BuildingBlock, types implementing Valuer,
+// LazyValue computed while rendering or anything else which is formatted with fmt.Sprint
+type Dict map[string]interface{}
 
-// New creates Docx instance
+// New creates Docx instance, errors of reading the archive are returned when the document
+// is rendered. Open returns them at once
 func New(r io.ReaderAt, size int64) *Docx {
 	doc := new(Docx)
 	doc.zipReader, doc.err = zip.NewReader(r, size)
-	doc.openingBracket = '['
-	doc.closingBracket = ']'
+	doc.openingBracket = "["
+	doc.closingBracket = "]"
 	return doc
 }
 
 // Brackets allows to configure characters which symbolice start and end of variable
 func (doc *Docx) Brackets(opening, closing rune) *Docx {
+	return doc.Delimiters(string(opening), string(closing))
+}
+
+// Delimiters allows to configure strings which start and end variables, like "{{" and "}}"
+// or "${" and "}", so placeholders don't collide with brackets of normal text
+func (doc *Docx) Delimiters(opening, closing string) *Docx {
+	if opening == "" || closing == "" {
+		doc.err = errors.New("Delimiters of placeholders can't be empty")
+		return doc
+	}
 	doc.openingBracket = opening
 	doc.closingBracket = closing
 	return doc
@@ -41,194 +121,146 @@ func (doc *Docx) Brackets(opening, closing rune) *Docx {
 
 // Replace stores dictionary of words to replace
 func (doc *Docx) Replace(dict map[string]string) *Docx {
+	doc.dict = make(Dict, len(dict))
+	for key, val := range dict {
+		doc.dict[key] = val
+	}
+	return doc
+}
+
+// ReplaceDict stores dictionary of variables and values of any supported type
+func (doc *Docx) ReplaceDict(dict Dict) *Docx {
 	doc.dict = dict
 	return doc
 }
 
-// Buffer is a slice of XML tokes which are buffered before saving them in a file
-type Buffer []xml.Token
+// ReplaceFunc computes values of placeholders which aren't found in the dictionary, fn gets
+// the name of a placeholder without brackets and filters and is called for every occurrence
+// in document order, so it can produce sequence numbers. Placeholders for which fn returns
+// false are left untouched
+func (doc *Docx) ReplaceFunc(fn func(placeholder string) (string, bool)) *Docx {
+	doc.replaceFunc = fn
+	return doc
+}
 
-// Flush saves all tokens to XML file and cleans the buffer
-func (buffer *Buffer) Flush(encoder *xml.Encoder) error {
-	for _, token := range *buffer {
-		err := encoder.EncodeToken(fixNS(token))
-		if err != nil {
-			return err
-		}
-	}
-	buffer.Clean()
-	return nil
+// MemoryLimit sets the maximum size in bytes of generated parts kept in memory while
+// the document is written, other parts are stored in temporary files. Output is always
+// written directly to the writer. It lets many large documents be rendered at once,
+// the limit is 0 (no limit) by default. Other than that no files are used
+func (doc *Docx) MemoryLimit(bytes int64) *Docx {
+	doc.memoryLimit = bytes
+	return doc
 }
 
-// Clean removes tokens from a buffer and keeps capacity untouched
-func (buffer *Buffer) Clean() {
-	*buffer = (*buffer)[:0]
+// Resilient enables passing malformed auxiliary parts (like comments or settings)
+// through unchanged instead of failing, such problems are reported by Warnings.
+// A malformed document.xml is always an error
+func (doc *Docx) Resilient(enabled bool) *Docx {
+	doc.resilient = enabled
+	return doc
 }
 
-// Process converts CharData tokens from a buffer to one string
-// and replaces variables with values from a dictionary
-func (buffer *Buffer) Process(encoder *xml.Encoder, dict Dict) error {
-	varName := ""
-	// wt indicates if we are currently in <w:t> XML element (where text is stored)
-	// all non-wt elements should be ignored when extracting a variable name
-	wt := true
-	for _, token := range *buffer {
-		if start, ok := token.(xml.StartElement); ok && isWT(start.Name) {
-			wt = true
-		}
-		if end, ok := token.(xml.EndElement); ok && isWT(end.Name) {
-			wt = false
-		}
-		if charData, ok := token.(xml.CharData); ok && wt {
-			varName += string(charData)
-		}
-	}
-	for key, val := range dict {
-		if strings.Index(varName, key) != -1 {
-			varName = strings.Replace(varName, key, val, 1)
-			// if expected value was found, clean the buffer and store replaced
-			// value as CharData token
-			buffer.Clean()
-			return encoder.EncodeToken(xml.CharData(varName))
+// Warnings returns problems of the last WriteTo which didn't stop it, like malformed parts
+// in resilient mode or expressions in brackets which can't be computed
+func (doc *Docx) Warnings() []error {
+	return doc.warnings
+}
+
+// warn records a problem which doesn't stop rendering, each one is reported once
+func (doc *Docx) warn(err error) {
+	for _, w := range doc.warnings {
+		if w.Error() == err.Error() {
+			return
 		}
 	}
-	// if expected value can't be found in a dictionary, just write
-	// all nodes to XLS file and clean the buffer
-	return buffer.Flush(encoder)
+	doc.warnings = append(doc.warnings, err)
 }
 
 // WriteTo puts ZIP content to given writer (like a file of HTTP response)
 func (doc *Docx) WriteTo(w io.Writer) (int64, error) {
-	if doc.err != nil {
-		return 0, doc.err
-	}
-	var total int64
-	// store data in newly created zip file
-	zipOut := zip.NewWriter(w)
-	defer zipOut.Close()
-	// we will look for document.xml file
-	foundDoc := false
-	// read data from a zip file
-	for _, zipFile := range doc.zipReader.File {
-		// create file inside zip archive
-		w, err := zipOut.Create(zipFile.Name)
-		if err != nil {
-			return total, err
-		}
-		// read a file from inside of a zip archive
-		r, err := zipFile.Open()
-		if err != nil {
-			return total, err
-		}
-		defer r.Close()
-
-		// look for document.xml file, otherwise, just copy data
-		if zipFile.Name != documentXML {
-			n, err := io.Copy(w, r)
-			total += n
-			if err != nil {
-				return total, err
-			}
-			continue
-		}
-		foundDoc = true
-		decoder := xml.NewDecoder(r)
-		encoder := xml.NewEncoder(w)
-		buffer := make(Buffer, 0, 50)
-		for {
-			if err != nil {
-				return total, err
-			}
-			// flush the buffer if we didn't find matching bracket in 50 tokens
-			if cap(buffer)-len(buffer) == 0 {
-				buffer.Flush(encoder)
-			}
-			token, err := decoder.RawToken()
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				return total, err
-			}
-			charData, isCharData := token.(xml.CharData)
-			// we can look for brackets now even if it's not CharData token
-			openingBracketIdx := bytes.IndexRune(charData, doc.openingBracket)
-			closingBracketIdx := bytes.IndexRune(charData, doc.closingBracket)
-			if len(buffer) == 0 {
-				if !isCharData {
-					err = encoder.EncodeToken(fixNS(token))
-					if err != nil {
-						return total, err
-					}
-					continue
-				}
-				if openingBracketIdx != -1 {
-					buffer = append(buffer, xml.CopyToken(token))
-				} else {
-					err = encoder.EncodeToken(fixNS(token))
-					if err != nil {
-						return total, err
-					}
-				}
-				if closingBracketIdx > openingBracketIdx {
-					buffer.Process(encoder, doc.dict)
-				}
-			} else {
-				buffer = append(buffer, xml.CopyToken(token))
-				if !isCharData {
-					continue
-				}
-				if closingBracketIdx != -1 { // TODO: this logic is broken
-					buffer.Process(encoder, doc.dict)
-				}
-				// if closingBracketIdx < openingBracketIdx {
-				// 	buffer.Process(encoder)
-				// }
-			}
-		}
-		err = buffer.Flush(encoder)
-		if err != nil {
-			return total, err
-		}
-		err = encoder.Flush()
-		if err != nil {
-			return total, err
-		}
+	return doc.WriteToContext(context.Background(), w)
+}
+
+// WriteToContext is like WriteTo, but rendering and writing stop with the error of the context
+// when it's done, e.g. when a request is cancelled or a deadline passes. Parts are checked
+// paragraph by paragraph, the output is left incomplete
+func (doc *Docx) WriteToContext(ctx context.Context, w io.Writer) (int64, error) {
+	p, err := doc.render(ctx, false)
+	if err != nil {
+		return 0, err
 	}
-	if !foundDoc {
-		return total, fmt.Errorf("Invalid DOCX document: %s not found in the archive", documentXML)
+	defer p.close()
+	if err := doc.makeThumbnail(p); err != nil {
+		return 0, err
 	}
-	return total, nil
+	return p.writeTo(w)
 }
 
-// isWT checks if current token is <w:t> XML element
-func isWT(name xml.Name) bool {
-	return name.Space == "w" && name.Local == "t"
+// RenderBytes replaces variables of the template in memory and returns the document.
+// It's meant for environments without files, like js/wasm in a browser
+func RenderBytes(template []byte, dict Dict) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if _, err := New(bytes.NewReader(template), int64(len(template))).ReplaceDict(dict).WriteTo(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-// fixNS is needed to write data in the same format as it was read
-// Without it we would end up with broken DOCX
-// not sure why `encoding/xml` stores data differently from how it was read
-func fixNS(token xml.Token) xml.Token {
-	switch token.(type) {
-	case xml.StartElement:
-		t := token.(xml.StartElement)
-		t.Name = fixName(t.Name)
-		for i := range t.Attr {
-			t.Attr[i].Name = fixName(t.Attr[i].Name)
-		}
-		return t
-	case xml.EndElement:
-		t := token.(xml.EndElement)
-		t.Name = fixName(t.Name)
-		return t
-	default:
-		return token
+// WriteTemplate writes the template with parts set by SetPart, AddPart or RenamePlaceholders
+// without replacing any placeholders
+func (doc *Docx) WriteTemplate(w io.Writer) (int64, error) {
+	if doc.err != nil {
+		return 0, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	p.modified = doc.reproducible
+	if err := doc.setParts(p); err != nil {
+		return 0, err
 	}
+	return p.writeTo(w)
 }
 
-func fixName(name xml.Name) xml.Name {
-	name.Local = name.Space + ":" + name.Local
-	name.Space = ""
-	return name
+// render replaces placeholders in parts of the template, the package has to be closed
+func (doc *Docx) render(ctx context.Context, dryRun bool) (*pkg, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	doc.warnings = nil
+	p := newPkg(doc.zipReader)
+	p.limit = doc.memoryLimit
+	p.flushPolicy = doc.flushPolicy
+	p.modified = doc.reproducible
+	p.dryRun = dryRun
+	p.ctx = ctx
+	if err := doc.setParts(p); err != nil {
+		p.close()
+		return nil, err
+	}
+	// we will look for document.xml file, other files are just copied
+	if !p.has(documentXML) {
+		p.close()
+		return nil, &partError{documentXML}
+	}
+	// parts added while rendering the document (like new headers) aren't rendered again
+	parts := doc.partsToRender(p)
+	if err := doc.renderPart(p, documentXML); err != nil {
+		p.close()
+		return nil, err
+	}
+	for _, name := range parts {
+		if err := p.cancelled(); err != nil {
+			p.close()
+			return nil, err
+		}
+		if err := doc.renderPart(p, name); err != nil {
+			p.close()
+			return nil, err
+		}
+	}
+	if len(p.unresolved) > 0 && !dryRun {
+		p.close()
+		return nil, &UnresolvedError{Placeholders: p.unresolved}
+	}
+	return p, nil
 }
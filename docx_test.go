@@ -3,11 +3,15 @@ package docx
 import (
 	"archive/zip"
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -74,3 +78,160 @@ func TestOpenAndSaveDocx(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRenderBytes(t *testing.T) {
+	template, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := RenderBytes(template, Dict{"[simple]": "SiMPlE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := renderParts(t, New(bytes.NewReader(document), int64(len(document))))[documentXML]
+	if !strings.Contains(content, "SiMPlE") {
+		t.Errorf("Can't find value in %s", content)
+	}
+	if _, err := RenderBytes([]byte("not a zip"), nil); err == nil {
+		t.Error("Expected an error of an invalid template")
+	}
+}
+
+// testParts are minimal parts of a DOCX archive used by tests
+var testParts = map[string]string{
+	contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+		`</Types>`,
+	"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+		`</Relationships>`,
+	"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`,
+}
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>%s</w:body></w:document>`
+
+// newTestDocx creates a document with given content of <w:body> element,
+// parts can contain additional files of the archive
+func newTestDocx(t *testing.T, body string, parts map[string]string) *Docx {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	files := map[string]string{documentXML: fmt.Sprintf(testDocumentXML, body)}
+	for name, content := range testParts {
+		files[name] = content
+	}
+	for name, content := range parts {
+		files[name] = content
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return New(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+// renderParts writes a document and returns content of all parts of the result
+func renderParts(t *testing.T, doc *Docx) map[string]string {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	for _, f := range reader.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+// renderBody writes a document and returns content of its <w:body> element
+func renderBody(t *testing.T, doc *Docx) string {
+	t.Helper()
+	content := renderParts(t, doc)[documentXML]
+	start := strings.Index(content, "<w:body>")
+	end := strings.LastIndex(content, "</w:body>")
+	if start == -1 || end == -1 {
+		t.Fatalf("Can't find body in %s", content)
+	}
+	return content[start+len("<w:body>") : end]
+}
+
+func TestReplaceInRuns(t *testing.T) {
+	body := `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Dear [name],</w:t></w:r><w:r><w:t xml:space="preserve"> [x] and [x] </w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>[x]</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"[name]": "John", "x": 1})
+	got := renderBody(t, doc)
+	// every occurrence of a variable is replaced
+	want := `<w:p><w:r><w:rPr><w:b></w:b></w:rPr><w:t>Dear John,</w:t></w:r><w:r><w:t xml:space="preserve"> 1 and 1 </w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>1</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestReplaceFunc(t *testing.T) {
+	body := p("[n]. [name] [n]") + p("[n|plural:# item,# items] [unknown]")
+	n := 0
+	doc := newTestDocx(t, body, nil).
+		ReplaceDict(Dict{"name": "Apple"}).
+		ReplaceFunc(func(placeholder string) (string, bool) {
+			if placeholder != "n" {
+				return "", false
+			}
+			n++
+			return strconv.Itoa(n), true
+		})
+	got := renderBody(t, doc)
+	if want := p("1. Apple 2") + p("3 items [unknown]"); got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestDelimiters(t *testing.T) {
+	body := `<w:p><w:r><w:t>Dear {</w:t></w:r><w:r><w:t>{name}}, [x] {{</w:t></w:r><w:r><w:t>total|money:EUR}</w:t></w:r>` +
+		`<w:r><w:t>}</w:t></w:r></w:p>` + p("{{#if paid}}Paid{{/if}} {{{{name}}")
+	doc := newTestDocx(t, body, nil).Delimiters("{{", "}}").ReplaceDict(Dict{"name": "John", "x": 1, "total": 10, "paid": true})
+	got := renderBody(t, doc)
+	if want := p("Dear John, [x] €10.00") + p("Paid {{John"); got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+	doc = newTestDocx(t, p("Total: ${total}"), nil).Delimiters("${", "}").ReplaceDict(Dict{"total": 10})
+	if got, want := renderBody(t, doc), p("Total: 10"); got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+	if _, err := newTestDocx(t, p("x"), nil).Delimiters("", "}").WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of empty delimiters")
+	}
+}
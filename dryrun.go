@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"context"
+	"fmt"
+)
+
+// Substitution is a replacement of a placeholder which would be done by WriteTo
+type Substitution struct {
+	// Part is a name of the part with the placeholder, like "word/document.xml"
+	Part string
+	// Placeholder is the replaced text including brackets, like "[name]"
+	Placeholder string
+	// Value is the new text, or a type of other values like docx.Image
+	Value string
+	// Resolved is false for placeholders without a value, which are left untouched
+	Resolved bool
+}
+
+// DryRun renders the document without writing it and returns substitutions of placeholders
+// in document order, e.g. for previews or checks of templates. Placeholders inserted
+// by other values (like building blocks) follow the placeholder of the value
+func (doc *Docx) DryRun() ([]Substitution, error) {
+	p, err := doc.render(context.Background(), true)
+	if err != nil {
+		return nil, err
+	}
+	defer p.close()
+	return p.substitutions, nil
+}
+
+// recordSubstitution remembers a replacement of a placeholder in dry-run mode.
+// Placeholders of a paragraph are replaced from the end, so they are inserted
+// at the position of the first one to keep document order
+func (ctx *renderContext) recordSubstitution(at int, ph placeholder, value Value, resolved bool) {
+	if !ctx.pkg.dryRun {
+		return
+	}
+	s := Substitution{
+		Part:        ctx.part,
+		Placeholder: ctx.doc.openingBracket + ph.name + ctx.doc.closingBracket,
+		Resolved:    resolved,
+	}
+	if text, ok := value.(Text); ok {
+		s.Value = string(text)
+	} else if value != nil {
+		s.Value = fmt.Sprintf("%T", value)
+	}
+	subs := append(ctx.pkg.substitutions, Substitution{})
+	copy(subs[at+1:], subs[at:])
+	subs[at] = s
+	ctx.pkg.substitutions = subs
+}
@@ -0,0 +1,28 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	body := p("[a] and [b] of [missing]") + p("[logo]")
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{
+		"a":    "first",
+		"b":    2,
+		"logo": Image{Data: testPNG(t, 2, 2).Bytes()},
+	})
+	got, err := doc.DryRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Substitution{
+		{Part: documentXML, Placeholder: "[a]", Value: "first", Resolved: true},
+		{Part: documentXML, Placeholder: "[b]", Value: "2", Resolved: true},
+		{Part: documentXML, Placeholder: "[missing]"},
+		{Part: documentXML, Placeholder: "[logo]", Value: "docx.Image", Resolved: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %+v\nwant: %+v", got, want)
+	}
+}
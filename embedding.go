@@ -0,0 +1,92 @@
+package docx
+
+import (
+	"sort"
+)
+
+// embeddingRelTypes are types of relationships referring to embedded objects
+var embeddingRelTypes = map[string]bool{
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships/oleObject": true,
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships/package":   true,
+}
+
+// Embedding is an embedded object of a document, like a spreadsheet or a PDF file
+type Embedding struct {
+	// Name is the name of the part, like "word/embeddings/Microsoft_Excel_Worksheet.xlsx"
+	Name        string
+	ContentType string
+	// ProgID identifies the application of the object, like "Excel.Sheet.12" or "AcroExch.Document.DC"
+	ProgID string
+	Data   []byte
+	// References are relationships of parts referring to the object
+	References []MediaReference
+}
+
+// ForEachEmbedding calls fn for all embedded objects of the template (or parts set by SetPart)
+// in order of their names, it stops on the first error returned by fn
+func (doc *Docx) ForEachEmbedding(fn func(e Embedding) error) error {
+	if doc.err != nil {
+		return doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return err
+	}
+	refs, err := referencedParts(p, "/embeddings/", embeddingRelTypes)
+	if err != nil {
+		return err
+	}
+	ct, err := p.types()
+	if err != nil {
+		return err
+	}
+	progIDs, err := oleProgIDs(p, refs)
+	if err != nil {
+		return err
+	}
+	list := make([]Embedding, 0, len(refs))
+	for name, references := range refs {
+		list = append(list, Embedding{Name: name, ContentType: ct.contentType(name), ProgID: progIDs[name], References: references})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	for _, e := range list {
+		if e.Data, err = p.read(e.Name); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oleProgIDs returns ProgIDs of <o:OLEObject> elements referring to embedded objects
+func oleProgIDs(p *pkg, refs map[string][]MediaReference) (map[string]string, error) {
+	// embedded objects by referring parts and IDs of relationships
+	targets := make(map[string]map[string]string)
+	for name, references := range refs {
+		for _, ref := range references {
+			if targets[ref.Part] == nil {
+				targets[ref.Part] = make(map[string]string)
+			}
+			targets[ref.Part][ref.ID] = name
+		}
+	}
+	progIDs := make(map[string]string)
+	for part, ids := range targets {
+		if !p.has(part) {
+			continue
+		}
+		root, err := p.parsePart(part)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range root.find("o:OLEObject") {
+			if name, ok := ids[n.attrValue("r:id")]; ok && progIDs[name] == "" {
+				progIDs[name] = n.attrValue("ProgID")
+			}
+		}
+	}
+	return progIDs, nil
+}
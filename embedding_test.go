@@ -0,0 +1,43 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForEachEmbedding(t *testing.T) {
+	relTypeOLE := "http://schemas.openxmlformats.org/officeDocument/2006/relationships/oleObject"
+	relTypePackage := "http://schemas.openxmlformats.org/officeDocument/2006/relationships/package"
+	body := `<w:p><w:r><w:object><v:shape id="_x0000_i1025"></v:shape>` +
+		`<o:OLEObject Type="Embed" ProgID="Excel.Sheet.12" ShapeID="_x0000_i1025" r:id="rId2"/></w:object></w:r></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId2" Type="` + relTypePackage + `" Target="embeddings/Sheet1.xlsx"/>` +
+			`<Relationship Id="rId3" Type="` + relTypeOLE + `" Target="embeddings/oleObject1.bin"/>` +
+			`</Relationships>`,
+		"word/embeddings/Sheet1.xlsx":    "xlsx",
+		"word/embeddings/oleObject1.bin": "ole",
+		contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="xlsx" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"/>` +
+			`<Default Extension="bin" ContentType="application/vnd.openxmlformats-officedocument.oleObject"/></Types>`,
+	}
+	var got []Embedding
+	err := newTestDocx(t, body, parts).ForEachEmbedding(func(e Embedding) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Embedding{
+		{Name: "word/embeddings/Sheet1.xlsx", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			ProgID: "Excel.Sheet.12", Data: []byte("xlsx"), References: []MediaReference{{Part: documentXML, ID: "rId2", Type: relTypePackage}}},
+		{Name: "word/embeddings/oleObject1.bin", ContentType: "application/vnd.openxmlformats-officedocument.oleObject",
+			Data: []byte("ole"), References: []MediaReference{{Part: documentXML, ID: "rId3", Type: relTypeOLE}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,376 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expr is a parsed expression like "price*quantity" used in placeholders
+// or `total > 1000 && country == "DE"` used in conditions
+type expr interface {
+	// eval computes value of an expression, ok is false when some variable can't be found
+	eval(lookup func(string) (interface{}, bool)) (v interface{}, ok bool, err error)
+}
+
+type numberExpr float64
+
+type stringExpr string
+
+type boolExpr bool
+
+type identExpr string
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op   string
+	x, y expr
+}
+
+func (e numberExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	return float64(e), true, nil
+}
+
+func (e stringExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	return string(e), true, nil
+}
+
+func (e boolExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	return bool(e), true, nil
+}
+
+func (e identExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	v, ok := lookup(string(e))
+	return v, ok, nil
+}
+
+func (e unaryExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	v, ok, err := e.x.eval(lookup)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	if e.op == "!" {
+		return !truthy(v), true, nil
+	}
+	n, err := number(v)
+	if err != nil {
+		return nil, true, err
+	}
+	return -n, true, nil
+}
+
+func (e binaryExpr) eval(lookup func(string) (interface{}, bool)) (interface{}, bool, error) {
+	x, ok, err := e.x.eval(lookup)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	// logical operators don't evaluate the right side when it's not needed
+	switch e.op {
+	case "&&":
+		if !truthy(x) {
+			return false, true, nil
+		}
+	case "||":
+		if truthy(x) {
+			return true, true, nil
+		}
+	}
+	y, ok, err := e.y.eval(lookup)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	switch e.op {
+	case "&&", "||":
+		return truthy(y), true, nil
+	case "==":
+		return equal(x, y), true, nil
+	case "!=":
+		return !equal(x, y), true, nil
+	case "<", "<=", ">", ">=":
+		return compare(e.op, x, y), true, nil
+	}
+	a, err := number(x)
+	if err != nil {
+		return nil, true, err
+	}
+	b, err := number(y)
+	if err != nil {
+		return nil, true, err
+	}
+	switch e.op {
+	case "+":
+		return a + b, true, nil
+	case "-":
+		return a - b, true, nil
+	case "*":
+		return a * b, true, nil
+	case "/", "%":
+		if b == 0 {
+			return nil, true, fmt.Errorf("Division by zero")
+		}
+		if e.op == "%" {
+			return math.Mod(a, b), true, nil
+		}
+		return a / b, true, nil
+	}
+	return nil, true, fmt.Errorf("Unknown operator %s", e.op)
+}
+
+// equal compares values as numbers if both of them are numeric, otherwise as texts
+func equal(x, y interface{}) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	a, okA := toNumber(x)
+	b, okB := toNumber(y)
+	if okA && okB {
+		return a == b
+	}
+	if bx, ok := x.(bool); ok {
+		return bx == truthy(y)
+	}
+	if by, ok := y.(bool); ok {
+		return by == truthy(x)
+	}
+	return fmt.Sprint(x) == fmt.Sprint(y)
+}
+
+// compare checks order of numbers or texts, values of different types are never ordered
+func compare(op string, x, y interface{}) bool {
+	var c int
+	a, okA := toNumber(x)
+	b, okB := toNumber(y)
+	switch {
+	case okA && okB:
+		c = int(sign(a - b))
+	case x != nil && y != nil && !okA && !okB:
+		c = strings.Compare(fmt.Sprint(x), fmt.Sprint(y))
+	default:
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func sign(n float64) float64 {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// truthy checks if a value means "yes": non-zero number, non-empty text or collection
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	case string:
+		return b != "" && b != "false" && b != "0"
+	case Text:
+		return truthy(string(b))
+	}
+	if n, ok := toNumber(v); ok {
+		return n != 0
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() > 0
+	case reflect.Ptr, reflect.Interface:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+// number converts a value used in arithmetic to float64
+func number(v interface{}) (float64, error) {
+	n, ok := toNumber(v)
+	if !ok {
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+	return n, nil
+}
+
+// formatNumber prints a number without exponent and without floating point noise
+// like 59.97000000000001
+func formatNumber(n float64) string {
+	if math.Abs(n) < 1e15 {
+		n = math.Round(n*1e10) / 1e10
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// binaryPrecedence of operators, higher binds stronger
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+// parseExpr parses an expression, it fails for texts which are not expressions
+func parseExpr(s string) (expr, error) {
+	tokens, err := tokenizeExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("Unexpected %s in expression %s", p.tokens[p.pos].text, s)
+	}
+	return e, nil
+}
+
+type exprToken struct {
+	kind byte // 'n'umber, 's'tring, 'i'dentifier, 'o'perator
+	text string
+}
+
+// quotes maps opening quotes to closing ones,
+// typographic quotes are included as Word replaces straight quotes while typing
+var quotes = map[rune]rune{'"': '"', '\'': '\'', '“': '”', '„': '“', '‘': '’'}
+
+func tokenizeExpr(s string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, exprToken{'n', string(runes[i:j])})
+			i = j
+		case isIdentRune(r):
+			j := i
+			for j < len(runes) && (isIdentRune(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, exprToken{'i', string(runes[i:j])})
+			i = j
+		case quotes[r] != 0:
+			j := i + 1
+			for j < len(runes) && runes[j] != quotes[r] {
+				j++
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("Unterminated string in expression %s", s)
+			}
+			tokens = append(tokens, exprToken{'s', string(runes[i+1 : j])})
+			i = j + 1
+		case i+1 < len(runes) && binaryPrecedence[string(runes[i:i+2])] != 0:
+			tokens = append(tokens, exprToken{'o', string(runes[i : i+2])})
+			i += 2
+		case strings.ContainsRune("+-*/%()<>!", r):
+			tokens = append(tokens, exprToken{'o', string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("Unexpected character %q in expression %s", r, s)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// exprParser is a precedence climbing parser of expressions
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) next() (exprToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return exprToken{}, false
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, true
+}
+
+func (p *exprParser) parse(minPrecedence int) (expr, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) {
+		op := p.tokens[p.pos]
+		precedence, ok := binaryPrecedence[op.text]
+		if op.kind != 'o' || !ok || precedence <= minPrecedence {
+			break
+		}
+		p.pos++
+		y, err := p.parse(precedence)
+		if err != nil {
+			return nil, err
+		}
+		x = binaryExpr{op: op.text, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) primary() (expr, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("Unexpected end of expression")
+	}
+	switch {
+	case t.kind == 'n':
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, err
+		}
+		return numberExpr(n), nil
+	case t.kind == 's':
+		return stringExpr(t.text), nil
+	case t.kind == 'i' && (t.text == "true" || t.text == "false"):
+		return boolExpr(t.text == "true"), nil
+	case t.kind == 'i':
+		return identExpr(t.text), nil
+	case t.text == "-" || t.text == "!":
+		x, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryExpr{op: t.text, x: x}, nil
+	case t.text == "(":
+		x, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.next(); !ok || t.text != ")" {
+			return nil, fmt.Errorf("Missing closing parenthesis")
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("Unexpected %s in expression", t.text)
+}
@@ -0,0 +1,89 @@
+package docx
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	dict := Dict{
+		"price":    19.99,
+		"quantity": 3,
+		"subtotal": "100",
+		"[name]":   "John",
+		"order-id": 42,
+	}
+	tests := []struct {
+		placeholder string
+		want        string
+	}{
+		{"[price*quantity]", "59.97"},
+		{"[subtotal*0.21]", "21"},
+		{"[subtotal * (1 + 0.21)]", "121"},
+		{"[subtotal - 10 / 4]", "97.5"},
+		{"[-quantity + 1]", "-2"},
+		{"[quantity % 2]", "1"},
+		{"[order-id]", "42"},
+		{"[subtotal*1.21|money:EUR,de]", "121,00\u00a0€"},
+		{"[price*missing]", "[price*missing]"},
+		{"[see note 1]", "[see note 1]"},
+		// literals and names of missing variables are texts, like citations or years
+		{"[1]", "[1]"},
+		{"[2024]", "[2024]"},
+		{`["q"]`, `[&#34;q&#34;]`},
+		{"[-3]", "[-3]"},
+		{"[(quantity)]", "[(quantity)]"},
+	}
+	for _, test := range tests {
+		body := `<w:p><w:r><w:t>` + test.placeholder + `</w:t></w:r></w:p>`
+		want := `<w:p><w:r><w:t>` + test.want + `</w:t></w:r></w:p>`
+		if got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict)); got != want {
+			t.Errorf("%s: got %s, want %s", test.placeholder, got, want)
+		}
+	}
+	// expressions which can't be computed are left as they are, strict mode reports them
+	for _, placeholder := range []string{"[name*2]", "[price/(quantity-3)]"} {
+		body := `<w:p><w:r><w:t>` + placeholder + `</w:t></w:r></w:p>`
+		if got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict)); got != body {
+			t.Errorf("%s: got %s, want %s", placeholder, got, body)
+		}
+		if _, err := newTestDocx(t, body, nil).ReplaceDict(dict).Strict(true).WriteTo(new(strings.Builder)); err == nil {
+			t.Errorf("%s: expected error", placeholder)
+		}
+	}
+}
+
+func TestBracketedLiterals(t *testing.T) {
+	body := p(`See [1], [2024], ["q"], [-3] and [n+1]`)
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"n": 1}))
+	if want := p(`See [1], [2024], ["q"], [-3] and 2`); got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	// spaces around names match keys only with NormalizeSpace
+	body = p("{{ a }}")
+	doc := newTestDocx(t, body, nil).Delimiters("{{", "}}").ReplaceDict(Dict{"a": "x"})
+	if got := renderBody(t, doc); got != body {
+		t.Errorf("got: %s\nwant: %s", got, body)
+	}
+	if got := renderBody(t, doc.NormalizeSpace(true)); got != p("x") {
+		t.Errorf("got: %s\nwant: %s", got, p("x"))
+	}
+}
+
+func TestFailedExpressions(t *testing.T) {
+	body := p("[a/b] and [name+1]")
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"a": 1, "b": 0, "name": "John"})
+	if got := renderBody(t, doc); got != body {
+		t.Errorf("got: %s\nwant: %s", got, body)
+	}
+	if len(doc.Warnings()) != 2 || !strings.Contains(doc.Warnings()[0].Error(), "Division by zero") ||
+		!strings.Contains(doc.Warnings()[1].Error(), "John is not a number") {
+		t.Errorf("Unexpected warnings: %v", doc.Warnings())
+	}
+	_, err := doc.Strict(true).WriteTo(ioutil.Discard)
+	if u, ok := err.(*UnresolvedError); !ok || !reflect.DeepEqual(u.Placeholders, []string{"[a/b]", "[name+1]"}) {
+		t.Errorf("Expected unresolved expressions in strict mode, got %v", err)
+	}
+}
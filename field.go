@@ -0,0 +1,68 @@
+package docx
+
+// Field is a Word field like PAGE or DATE \@ "d MMMM yyyy", which Word updates itself.
+// Result is shown until the field is updated
+type Field struct {
+	Code   string
+	Result string
+}
+
+func (f Field) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	run := func(c *node) *node {
+		r := elem("w:r")
+		if rPr != nil {
+			r.append(rPr.clone())
+		}
+		r.append(c)
+		return r
+	}
+	instr := elem("w:instrText", "xml:space", "preserve")
+	instr.setText(" " + f.Code + " ")
+	runs := []*node{
+		run(elem("w:fldChar", "w:fldCharType", "begin")),
+		run(instr),
+		run(elem("w:fldChar", "w:fldCharType", "separate")),
+	}
+	if f.Result != "" {
+		runs = append(runs, newRun(rPr, f.Result))
+	}
+	return append(runs, run(elem("w:fldChar", "w:fldCharType", "end"))), nil
+}
+
+// SimpleFieldInstructions enables replacing placeholders in instructions of simple fields
+// (<w:fldSimple>), like HYPERLINK "https://example.com/[id]". Only text values are used
+// and instructions of complex fields are never changed
+func (doc *Docx) SimpleFieldInstructions(enabled bool) *Docx {
+	doc.simpleFieldInstructions = enabled
+	return doc
+}
+
+// replaceSimpleFields replaces placeholders in instructions of simple fields
+func (ctx *renderContext) replaceSimpleFields() error {
+	if !ctx.doc.simpleFieldInstructions {
+		return nil
+	}
+	for _, field := range ctx.root.find("w:fldSimple") {
+		instr := field.attrValue("w:instr")
+		found := ctx.doc.placeholders(instr)
+		for i := len(found) - 1; i >= 0; i-- {
+			ph := found[i]
+			v, ok, err := ctx.scopeOf(field).resolve(ph.name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			value, err := toValue(v)
+			if err != nil {
+				return err
+			}
+			if text, ok := value.(Text); ok {
+				instr = instr[:ph.start] + string(text) + instr[ph.end:]
+			}
+		}
+		field.setAttr("w:instr", instr)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestFieldInstructions(t *testing.T) {
+	r := func(content string) string { return `<w:r>` + content + `</w:r>` }
+	fldChar := func(kind string) string { return r(`<w:fldChar w:fldCharType="` + kind + `"></w:fldChar>`) }
+	instr := func(text string) string { return r(`<w:instrText xml:space="preserve">` + text + `</w:instrText>`) }
+	text := func(text string) string { return r(`<w:t xml:space="preserve">` + text + `</w:t>`) }
+	body := `<w:p>` + fldChar("begin") + instr(` HYPERLINK https://example.com/?q=[id] `) + fldChar("separate") +
+		text("Order [id]") + fldChar("end") + `</w:p>` +
+		`<w:p>` + fldChar("begin") + instr(` IF `) + fldChar("begin") + instr(` MERGEFIELD a `) + fldChar("separate") +
+		text("[id]") + fldChar("end") + instr(` = 1 yes no `) + fldChar("separate") + text("[id]") + fldChar("end") + `</w:p>` +
+		`<w:p>` + text("[i") + fldChar("begin") + instr(` PAGE `) + fldChar("end") + text("d]") + `</w:p>`
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"id": 42}))
+	want := `<w:p>` + fldChar("begin") + instr(` HYPERLINK https://example.com/?q=[id] `) + fldChar("separate") +
+		`<w:r><w:t xml:space="preserve">Order 42</w:t></w:r>` + fldChar("end") + `</w:p>` +
+		`<w:p>` + fldChar("begin") + instr(` IF `) + fldChar("begin") + instr(` MERGEFIELD a `) + fldChar("separate") +
+		text("[id]") + fldChar("end") + instr(` = 1 yes no `) + fldChar("separate") + text("42") +
+		fldChar("end") + `</w:p>` +
+		`<w:p>` + text("[i") + fldChar("begin") + instr(` PAGE `) + fldChar("end") + text("d]") + `</w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSimpleFields(t *testing.T) {
+	body := `<w:p><w:r><w:t>[a</w:t></w:r><w:fldSimple w:instr=" HYPERLINK https://example.com/[id] ">` +
+		`<w:r><w:t>Order [id]</w:t></w:r></w:fldSimple><w:r><w:t>]</w:t></w:r></w:p>`
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"id": 42, "a": "x"}))
+	want := `<w:p><w:r><w:t>[a</w:t></w:r><w:fldSimple w:instr=" HYPERLINK https://example.com/[id] ">` +
+		`<w:r><w:t>Order 42</w:t></w:r></w:fldSimple><w:r><w:t>]</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"id": 42}).SimpleFieldInstructions(true))
+	want = `<w:p><w:r><w:t>[a</w:t></w:r><w:fldSimple w:instr=" HYPERLINK https://example.com/42 ">` +
+		`<w:r><w:t>Order 42</w:t></w:r></w:fldSimple><w:r><w:t>]</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
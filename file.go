@@ -0,0 +1,127 @@
+package docx
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Open creates Docx instance like New, but an invalid archive is reported at once instead of
+// when the document is rendered, as well as an archive which isn't a document (without word/document.xml)
+func Open(r io.ReaderAt, size int64) (*Docx, error) {
+	doc := New(r, size)
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	for _, f := range doc.zipReader.File {
+		if f.Name == documentXML {
+			return doc, nil
+		}
+	}
+	return nil, &partError{documentXML}
+}
+
+// OpenFile opens a template file, it's read while the document is rendered,
+// so the file is kept open until Close is called
+func OpenFile(name string) (*Docx, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	doc, err := Open(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	doc.file = f
+	return doc, nil
+}
+
+// OpenFS opens a template of a file system, like templates embedded by go:embed:
+// docx.OpenFS(templates, "contract.docx"). Files which can't be read at any offset are read
+// into memory, others are kept open until Close is called
+func OpenFS(fsys fs.FS, name string) (*Docx, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := f.(io.ReaderAt)
+	if !ok {
+		f.Close()
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return NewFromBytes(data)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	doc, err := Open(r, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	doc.file = f
+	return doc, nil
+}
+
+// NewFromBytes creates Docx instance of a template held in memory, like a download or a database blob.
+// The data is read while the document is rendered, so it shouldn't be changed
+func NewFromBytes(data []byte) (*Docx, error) {
+	return Open(bytes.NewReader(data), int64(len(data)))
+}
+
+// Close closes the file of a template opened by OpenFile or OpenFS,
+// it does nothing when the template was given by New or NewFromBytes
+func (doc *Docx) Close() error {
+	if doc.file == nil {
+		return nil
+	}
+	err := doc.file.Close()
+	doc.file = nil
+	return err
+}
+
+// Save renders the document into a file. It's written to a temporary file in the same directory first,
+// which then replaces the file, so a failed rendering never leaves a truncated document behind.
+// The template can be saved over, permissions of a replaced file are kept
+func (doc *Docx) Save(name string) (err error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err = doc.WriteTo(f); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Chmod(mode); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
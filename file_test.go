@@ -0,0 +1,166 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOpenFile(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newTestDocx(t, p("Hello [name]"), nil).WriteTemplate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	name := filepath.Join(dir, "template.docx")
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := OpenFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := renderBody(t, doc.ReplaceDict(Dict{"name": "Ann"}))
+	if !strings.Contains(got, "Hello Ann") {
+		t.Errorf("Unexpected body: %s", got)
+	}
+	if err := doc.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := doc.Close(); err != nil {
+		t.Errorf("Expected no error of closing twice, got %v", err)
+	}
+
+	if _, err := OpenFile(filepath.Join(dir, "missing.docx")); err == nil {
+		t.Error("Expected error of a missing file")
+	}
+	invalid := filepath.Join(dir, "invalid.docx")
+	if err := ioutil.WriteFile(invalid, []byte("not a zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFile(invalid); err == nil {
+		t.Error("Expected error of a file which isn't a document")
+	}
+}
+
+func TestNewFromBytes(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newTestDocx(t, p("Hello [name]"), nil).WriteTemplate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewFromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderBody(t, doc.ReplaceDict(Dict{"name": "Ann"})); !strings.Contains(got, "Hello Ann") {
+		t.Errorf("Unexpected body: %s", got)
+	}
+	if _, err := NewFromBytes([]byte("not a zip")); err == nil {
+		t.Error("Expected error of data which isn't a document")
+	}
+}
+
+func TestOpenFS(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newTestDocx(t, p("Hello [name]"), nil).WriteTemplate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "template.docx"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// files of streamFS can't be read at offsets, so they are read into memory
+	mapFS := fstest.MapFS{"template.docx": {Data: buf.Bytes()}}
+	for _, fsys := range []fs.FS{os.DirFS(dir), mapFS, streamFS{mapFS}} {
+		doc, err := OpenFS(fsys, "template.docx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := renderBody(t, doc.ReplaceDict(Dict{"name": "Ann"})); !strings.Contains(got, "Hello Ann") {
+			t.Errorf("Unexpected body: %s", got)
+		}
+		if err := doc.Close(); err != nil {
+			t.Error(err)
+		}
+		if _, err := OpenFS(fsys, "missing.docx"); err == nil {
+			t.Error("Expected error of a missing file")
+		}
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "output.docx")
+	if err := ioutil.WriteFile(name, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newTestDocx(t, p("Hello [name]"), nil).ReplaceDict(Dict{"name": "Ann"}).Save(name); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := OpenFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.Close()
+	if got := renderBody(t, doc); !strings.Contains(got, "Hello Ann") {
+		t.Errorf("Unexpected body: %s", got)
+	}
+	if info, err := os.Stat(name); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected permissions of the replaced file, got %v %v", info.Mode(), err)
+	}
+
+	// a failed rendering leaves the file as it was and no temporary files
+	if err := newTestDocx(t, p("[x|unknown]"), nil).ReplaceDict(Dict{"x": 1}).Save(name); err == nil {
+		t.Error("Expected error of an unknown filter")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected only the saved file, got %d files", len(files))
+	}
+	if got := renderBody(t, doc); !strings.Contains(got, "Hello Ann") {
+		t.Errorf("Unexpected body after a failed rendering: %s", got)
+	}
+}
+
+// streamFS is a file system whose files are only read sequentially
+type streamFS struct {
+	fs.FS
+}
+
+func (s streamFS) Open(name string) (fs.File, error) {
+	f, err := s.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ fs.File }{f}, nil
+}
+
+func TestOpen(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := newTestDocx(t, p("Hello"), nil).WriteTemplate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Error(err)
+	}
+	if _, err := Open(strings.NewReader("not a zip"), 9); err == nil {
+		t.Error("Expected error of data which isn't an archive")
+	}
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	if _, err := w.Create("word/styles.xml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Open(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if _, ok := err.(*partError); !ok {
+		t.Errorf("Expected error of a missing document.xml, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Filter transforms value of a variable before it's rendered.
+// Filters are used in templates after a pipe: [count|plural:item,items]
+type Filter func(value interface{}, args []string, locale string) (interface{}, error)
+
+// builtinFilters are available in all documents
+var builtinFilters = map[string]Filter{
+	"plural":    pluralFilter,
+	"money":     moneyFilter,
+	"color":     colorFilter,
+	"highlight": highlightFilter,
+	"bold":      boldFilter,
+	"italic":    italicFilter,
+	"underline": underlineFilter,
+}
+
+// Filter registers a custom filter which can be used in the document
+func (doc *Docx) Filter(name string, fn Filter) *Docx {
+	if doc.filters == nil {
+		doc.filters = make(map[string]Filter)
+	}
+	doc.filters[name] = fn
+	return doc
+}
+
+// Locale sets language used by filters (like "de-DE"), it's en-US by default
+func (doc *Docx) Locale(locale string) *Docx {
+	doc.locale = locale
+	return doc
+}
+
+// filterCall is a filter used in a placeholder, i.e. "plural:item,items"
+type filterCall struct {
+	name string
+	args []string
+}
+
+// parseFilters splits placeholder name to a variable and filters applied to it
+func parseFilters(name string) (string, []filterCall) {
+	parts := strings.Split(name, "|")
+	calls := make([]filterCall, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		call := filterCall{name: strings.TrimSpace(part)}
+		if i := strings.IndexByte(part, ':'); i != -1 {
+			call.name = strings.TrimSpace(part[:i])
+			for _, arg := range strings.Split(part[i+1:], ",") {
+				call.args = append(call.args, strings.TrimSpace(arg))
+			}
+		}
+		calls = append(calls, call)
+	}
+	if len(calls) == 0 {
+		// spaces around a name are kept, NormalizeSpace decides if they match
+		return name, nil
+	}
+	return strings.TrimSpace(parts[0]), calls
+}
+
+// applyFilters passes a value through all filters of a placeholder
+func (doc *Docx) applyFilters(v interface{}, calls []filterCall) (interface{}, error) {
+	locale := doc.locale
+	if locale == "" {
+		locale = "en-US"
+	}
+	for _, call := range calls {
+		fn, ok := doc.filters[call.name]
+		if !ok {
+			fn, ok = builtinFilters[call.name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("Unknown filter: %s", call.name)
+		}
+		var err error
+		v, err = fn(v, call.args, locale)
+		if err != nil {
+			return nil, fmt.Errorf("Filter %s: %v", call.name, err)
+		}
+	}
+	return v, nil
+}
+
+// toNumber converts numeric values and strings to float64
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case Money:
+		return n.Amount, true
+	case formattedValue:
+		return toNumber(n.value)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	case Text:
+		return toNumber(string(n))
+	}
+	return 0, false
+}
+
+// pluralFilter chooses a word form according to a number:
+// [count|plural:item,items] or [count|plural:# plik,# pliki,# plików] in Polish
+// Forms are given in order of CLDR plural categories used by the language
+// (one, few, many, other) and "#" is replaced with the number
+func pluralFilter(v interface{}, args []string, locale string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("word forms are required")
+	}
+	n, ok := toNumber(v)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", v)
+	}
+	i := pluralForm(locale, n)
+	if i >= len(args) {
+		i = len(args) - 1
+	}
+	return strings.Replace(args[i], "#", strconv.FormatFloat(n, 'f', -1, 64), -1), nil
+}
+
+// pluralForm returns index of a plural category used by a language for number n,
+// fractions like 1.5 are "other" unless the language counts them by their integer part
+func pluralForm(locale string, n float64) int {
+	tags := strings.SplitN(strings.ToLower(strings.Replace(locale, "_", "-", -1)), "-", 2)
+	language := tags[0]
+	if language == "pt" && len(tags) > 1 && strings.HasPrefix(tags[1], "pt") {
+		// only 1 is singular in Portugal, unlike Brazil
+		language = "pt-pt"
+	}
+	n = math.Abs(n)
+	i := int64(n)
+	fraction := n != math.Trunc(n)
+	mod10, mod100 := i%10, i%100
+	switch language {
+	case "ja", "zh", "ko", "th", "vi", "id":
+		return 0
+	case "fr", "pt":
+		// one is 0 and 1 with any fraction, like "1,5 kilo"
+		if i <= 1 {
+			return 0
+		}
+		return 1
+	case "cs", "sk":
+		switch {
+		case fraction:
+			return 2
+		case i == 1:
+			return 0
+		case i >= 2 && i <= 4:
+			return 1
+		}
+		return 3
+	case "pl", "ru", "uk", "be":
+		one := i == 1
+		if language != "pl" {
+			one = mod10 == 1 && mod100 != 11
+		}
+		switch {
+		case fraction:
+			return 3
+		case one:
+			return 0
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return 1
+		}
+		return 2
+	}
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+// moneyFilter formats a number as money: [total|money:EUR] or [total|money:EUR,de-DE]
+func moneyFilter(v interface{}, args []string, locale string) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("currency is required")
+	}
+	n, ok := toNumber(v)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a number", v)
+	}
+	if len(args) > 1 {
+		locale = args[1]
+	}
+	return Money{Amount: n, Currency: args[0], Locale: locale}, nil
+}
@@ -0,0 +1,68 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPluralFilter(t *testing.T) {
+	tests := []struct {
+		locale string
+		count  float64
+		forms  string
+		want   string
+	}{
+		{"", 1, "item,items", "item"},
+		{"", 0, "item,items", "items"},
+		{"en-GB", 21, "# item,# items", "21 items"},
+		{"fr", 0, "article,articles", "article"},
+		{"pl-PL", 1, "# plik,# pliki,# plików", "1 plik"},
+		{"pl-PL", 22, "# plik,# pliki,# plików", "22 pliki"},
+		{"pl-PL", 12, "# plik,# pliki,# plików", "12 plików"},
+		{"cs", 5, "soubor,soubory,souborů", "souborů"},
+		{"cs", 1.5, "soubor,soubory,souboru,souborů", "souboru"},
+		{"cs", 7, "soubor,soubory,souboru,souborů", "souborů"},
+		{"ja", 3, "件", "件"},
+		{"en", 1.5, "# item,# items", "1.5 items"},
+		{"en", -1, "# item,# items", "-1 item"},
+		{"pl", 1.5, "# plik,# pliki,# plików,# pliku", "1.5 pliku"},
+		{"ru", 21, "# файл,# файла,# файлов", "21 файл"},
+		{"ru", 2.5, "# файл,# файла,# файлов,# файла", "2.5 файла"},
+		{"fr", 1.5, "# kilo,# kilos", "1.5 kilo"},
+		{"pt-BR", 0, "# arquivo,# arquivos", "0 arquivo"},
+		{"pt-PT", 0, "# ficheiro,# ficheiros", "0 ficheiros"},
+		{"pt_PT", 1, "# ficheiro,# ficheiros", "1 ficheiro"},
+	}
+	for _, test := range tests {
+		body := `<w:p><w:r><w:t>[count|plural:` + test.forms + `]</w:t></w:r></w:p>`
+		doc := newTestDocx(t, body, nil).Locale(test.locale).ReplaceDict(Dict{"count": test.count})
+		want := `<w:p><w:r><w:t>` + test.want + `</w:t></w:r></w:p>`
+		if got := renderBody(t, doc); got != want {
+			t.Errorf("%s %v: got %s, want %s", test.locale, test.count, got, want)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	upper := func(v interface{}, args []string, locale string) (interface{}, error) {
+		return strings.ToUpper(v.(string)), nil
+	}
+	dict := Dict{"total": "1234.5", "[name]": "John"}
+	body := `<w:p><w:r><w:t>[total|money:EUR] [name|upper] [missing|upper]</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).Locale("de-DE").Filter("upper", upper).ReplaceDict(dict)
+	want := "<w:p><w:r><w:t>1.234,50\u00a0€ JOHN [missing|upper]</w:t></w:r></w:p>"
+	if got := renderBody(t, doc); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	body = `<w:p><w:r><w:t>[name | money:EUR]</w:t></w:r></w:p>`
+	_, err := newTestDocx(t, body, nil).ReplaceDict(dict).WriteTo(new(strings.Builder))
+	if err == nil || !strings.Contains(err.Error(), "money") {
+		t.Errorf("Expected error of money filter, got %v", err)
+	}
+	body = `<w:p><w:r><w:t>[name|unknown]</w:t></w:r></w:p>`
+	_, err = newTestDocx(t, body, nil).ReplaceDict(dict).WriteTo(new(strings.Builder))
+	if err == nil {
+		t.Error("Expected error of unknown filter")
+	}
+}
@@ -0,0 +1,48 @@
+package docx
+
+import "io"
+
+// FlushPolicy decides when the output is flushed while a document is written, so e.g.
+// an HTTP response starts streaming before the whole document is generated.
+// Output is flushed by the writer's Flush method, like that of http.ResponseWriter
+// or bufio.Writer, writers without it are only written to
+type FlushPolicy struct {
+	// Parts flushes the output after every part of the archive
+	Parts bool
+	// Bytes flushes the output after every given number of bytes, 0 disables it.
+	// Compressed data is buffered in chunks of 4 KB, so smaller values don't flush more often
+	Bytes int
+}
+
+// FlushPolicy sets when the output of WriteTo is flushed, by default it's flushed only at the end
+func (doc *Docx) FlushPolicy(policy FlushPolicy) *Docx {
+	doc.flushPolicy = policy
+	return doc
+}
+
+// flushWriter flushes the output after every given number of bytes
+type flushWriter struct {
+	w            io.Writer
+	every, count int
+}
+
+func (fw *flushWriter) Write(b []byte) (int, error) {
+	n, err := fw.w.Write(b)
+	fw.count += n
+	if err == nil && fw.every > 0 && fw.count >= fw.every {
+		err = fw.flush()
+	}
+	return n, err
+}
+
+// flush flushes the output if it can be flushed
+func (fw *flushWriter) flush() error {
+	fw.count = 0
+	switch f := fw.w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+// flushRecorder records sizes of output at every flush
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Len())
+}
+
+func TestFlushPolicy(t *testing.T) {
+	body := p("[a]")
+	tests := []struct {
+		policy FlushPolicy
+		min    int
+	}{
+		{FlushPolicy{}, 1},
+		{FlushPolicy{Parts: true}, len(testParts) + 2},
+		{FlushPolicy{Bytes: 100}, 2},
+	}
+	for _, tt := range tests {
+		out := new(flushRecorder)
+		if _, err := newTestDocx(t, body, nil).FlushPolicy(tt.policy).WriteTo(out); err != nil {
+			t.Fatal(err)
+		}
+		if tt.policy == (FlushPolicy{}) && len(out.flushes) != 1 {
+			t.Errorf("flushed at %v without a policy", out.flushes)
+		}
+		if len(out.flushes) < tt.min {
+			t.Errorf("%+v: flushed at %v, want at least %d flushes", tt.policy, out.flushes, tt.min)
+		}
+		if last := out.flushes[len(out.flushes)-1]; last != out.Len() {
+			t.Errorf("%+v: last flush at %d of %d bytes", tt.policy, last, out.Len())
+		}
+	}
+}
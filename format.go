@@ -0,0 +1,173 @@
+package docx
+
+import (
+	"fmt"
+)
+
+// FormatRule formats a replaced value when its condition is true, like a negative amount in red.
+// The value is called "value" in the condition, other variables can be used too:
+// FormatRule{When: "value < 0", Format: Run{Color: "FF0000"}}
+type FormatRule struct {
+	// When is a condition, the rule is always applied when it's empty
+	When string
+	// Format is applied on top of formatting of the placeholder, its Text isn't used
+	Format Run
+}
+
+// FormatValue sets rules formatting values of a variable, formats of all rules
+// whose conditions are true are applied in order
+func (doc *Docx) FormatValue(name string, rules ...FormatRule) *Docx {
+	if doc.formats == nil {
+		doc.formats = make(map[string][]FormatRule)
+	}
+	doc.formats[name] = append(doc.formats[name], rules...)
+	return doc
+}
+
+// formattedValue is a value with formatting added by rules or formatting filters
+type formattedValue struct {
+	value  interface{}
+	format Run
+}
+
+func (f formattedValue) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	value, err := toValue(f.value)
+	if err != nil {
+		return nil, err
+	}
+	rPr = f.format.properties(rPr)
+	switch v := value.(type) {
+	case Text:
+		return []*node{newRun(rPr, string(v))}, nil
+	case RichText:
+		return v.runs(ctx, rPr)
+	}
+	return value.runs(ctx, rPr)
+}
+
+// withFormat adds formatting to a value, formats of already formatted values are merged
+func withFormat(v interface{}, format Run) formattedValue {
+	f, ok := v.(formattedValue)
+	if !ok {
+		return formattedValue{value: v, format: format}
+	}
+	f.format = f.format.merge(format)
+	return f
+}
+
+// merge returns formatting with properties of another run set on top
+func (run Run) merge(other Run) Run {
+	run.Bold = run.Bold || other.Bold
+	run.Italic = run.Italic || other.Italic
+	run.Underline = run.Underline || other.Underline
+	run.Strike = run.Strike || other.Strike
+	if other.Color != "" {
+		run.Color = other.Color
+	}
+	if other.Highlight != "" {
+		run.Highlight = other.Highlight
+	}
+	if other.Font != "" {
+		run.Font = other.Font
+	}
+	if other.Size > 0 {
+		run.Size = other.Size
+	}
+	return run
+}
+
+// formatValue applies formatting rules of a variable used by a placeholder
+func (s *scope) formatValue(name string, v interface{}) (interface{}, error) {
+	key, _ := parseFilters(name)
+	rules := s.doc.formats[key]
+	if len(rules) == 0 {
+		return v, nil
+	}
+	// conditions use the value before filters (like money) were applied
+	raw, ok := s.lookup(key)
+	if !ok {
+		raw = v
+	}
+	for _, rule := range rules {
+		ok, err := evalCondition(rule.When, raw, func(name string) interface{} {
+			v, _ := s.lookup(name)
+			return v
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			v = withFormat(v, rule.Format)
+		}
+	}
+	return v, nil
+}
+
+// evalCondition checks a condition of a value, variables other than "value" are looked up
+func evalCondition(condition string, value interface{}, lookup func(string) interface{}) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+	e, err := parseExpr(condition)
+	if err != nil {
+		return false, fmt.Errorf("Invalid condition %s: %v", condition, err)
+	}
+	v, _, err := e.eval(func(name string) (interface{}, bool) {
+		if name == "value" {
+			return value, true
+		}
+		if lookup == nil {
+			return nil, true
+		}
+		return lookup(name), true
+	})
+	if err != nil {
+		return false, fmt.Errorf("Invalid condition %s: %v", condition, err)
+	}
+	return truthy(v), nil
+}
+
+// formatFilter returns a filter which formats a value, the last argument can be a condition:
+// [balance|color:FF0000,value < 0] or [total|bold:value > 1000]
+func formatFilter(format func(args []string) (Run, []string, error)) Filter {
+	return func(v interface{}, args []string, locale string) (interface{}, error) {
+		run, rest, err := format(args)
+		if err != nil {
+			return nil, err
+		}
+		condition := ""
+		if len(rest) > 0 {
+			condition = rest[0]
+		}
+		raw := v
+		if f, ok := v.(formattedValue); ok {
+			raw = f.value
+		}
+		ok, err := evalCondition(condition, raw, nil)
+		if err != nil || !ok {
+			return v, err
+		}
+		return withFormat(v, run), nil
+	}
+}
+
+// formatting filters, colors are given before an optional condition
+var (
+	boldFilter      = formatFilter(func(args []string) (Run, []string, error) { return Run{Bold: true}, args, nil })
+	italicFilter    = formatFilter(func(args []string) (Run, []string, error) { return Run{Italic: true}, args, nil })
+	underlineFilter = formatFilter(func(args []string) (Run, []string, error) { return Run{Underline: true}, args, nil })
+	colorFilter     = formatFilter(colorArgument(func(run *Run, color string) { run.Color = color }))
+	highlightFilter = formatFilter(colorArgument(func(run *Run, color string) { run.Highlight = color }))
+)
+
+// colorArgument returns formatting with a color given as the first argument
+func colorArgument(set func(run *Run, color string)) func(args []string) (Run, []string, error) {
+	return func(args []string) (Run, []string, error) {
+		var run Run
+		if len(args) == 0 || args[0] == "" {
+			return run, nil, fmt.Errorf("color is required")
+		}
+		set(&run, args[0])
+		return run, args[1:], nil
+	}
+}
@@ -0,0 +1,39 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatValue(t *testing.T) {
+	body := p("[balance|money:EUR]") + p("[total]") + p("[count|bold:value > 1|highlight:yellow,value > 5]") +
+		p("[x|color:00FF00]")
+	doc := newTestDocx(t, body, nil).
+		FormatValue("balance", FormatRule{When: "value < 0", Format: Run{Color: "FF0000"}}).
+		FormatValue("total",
+			FormatRule{When: "value > limit", Format: Run{Highlight: "yellow"}},
+			FormatRule{When: "value > limit * 10", Format: Run{Bold: true}}).
+		ReplaceDict(Dict{"balance": -5, "total": 150, "limit": 100, "count": 2, "x": RichText{{Text: "a", Italic: true}}})
+	got := renderBody(t, doc)
+	want := `<w:p><w:r><w:rPr><w:color w:val="FF0000"></w:color></w:rPr><w:t>-€5.00</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:rPr><w:highlight w:val="yellow"></w:highlight></w:rPr><w:t>150</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:rPr><w:b></w:b><w:bCs></w:bCs></w:rPr><w:t>2</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:rPr><w:i></w:i><w:iCs></w:iCs><w:color w:val="00FF00"></w:color></w:rPr><w:t>a</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	doc = newTestDocx(t, body, nil).
+		FormatValue("balance", FormatRule{When: "value < 0", Format: Run{Color: "FF0000"}}).
+		ReplaceDict(Dict{"balance": 5, "total": 1, "count": 0, "x": "b"})
+	got = renderBody(t, doc)
+	if strings.Count(got, "<w:rPr>") != 1 || !strings.Contains(got, `<w:color w:val="00FF00">`) {
+		t.Errorf("Unexpected formatting: %s", got)
+	}
+	for _, placeholder := range []string{"[x|color]", "[x|bold:value >]"} {
+		doc := newTestDocx(t, p(placeholder), nil).ReplaceDict(Dict{"x": 1})
+		if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+			t.Errorf("Expected error of %s", placeholder)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// actionDelimiters enclose actions of Go templates
+var actionDelimiters = [2]string{"{{", "}}"}
+
+// goTemplate is data and functions of text/template rendering
+type goTemplate struct {
+	data  interface{}
+	funcs template.FuncMap
+}
+
+// TextTemplate renders text of the document as a Go text/template executed against data,
+// like {{.Name}}, {{if .Paid}}...{{end}} or {{range .Items}}...{{end}}. Actions can be split
+// across runs and printed values are escaped. Actions which don't print anything (like {{range}})
+// in paragraphs or table rows of their own leave no empty lines, so paragraphs and rows can be repeated.
+// Placeholders in brackets are replaced after the template is executed
+func (doc *Docx) TextTemplate(data interface{}) *Docx {
+	if doc.goTemplate == nil {
+		doc.goTemplate = new(goTemplate)
+	}
+	doc.goTemplate.data = data
+	return doc
+}
+
+// TemplateFuncs adds functions to templates rendered by TextTemplate
+func (doc *Docx) TemplateFuncs(funcs template.FuncMap) *Docx {
+	if doc.goTemplate == nil {
+		doc.goTemplate = new(goTemplate)
+	}
+	if doc.goTemplate.funcs == nil {
+		doc.goTemplate.funcs = make(template.FuncMap)
+	}
+	for name, fn := range funcs {
+		doc.goTemplate.funcs[name] = fn
+	}
+	return doc
+}
+
+// controlActions are keywords of actions which don't print values
+var controlActions = map[string]bool{
+	"if": true, "else": true, "end": true, "range": true, "with": true, "define": true,
+	"template": true, "block": true, "break": true, "continue": true,
+}
+
+// assignment matches actions declaring or assigning variables, like {{$total := .Total}}
+var assignment = regexp.MustCompile(`^\$\w*\s*:?=`)
+
+// tokenStart and tokenEnd are private use characters enclosing numbers of actions
+const (
+	tokenStart = "\uE000"
+	tokenEnd   = "\uE001"
+)
+
+// actionToken matches tokens which stand for actions in the XML of a part
+var actionToken = regexp.MustCompile(tokenStart + "([0-9]+)" + tokenEnd)
+
+// templateQuotes are typographic quotes Word puts into actions, they are replaced with plain ones
+var templateQuotes = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`, "‘", "'", "’", "'")
+
+// templateAction is an action of a template found in text of a paragraph
+type templateAction struct {
+	start, end int
+	// left and right are trim markers, inner is the pipeline or the control keyword with arguments
+	left, inner, right string
+}
+
+// control checks if an action prints nothing
+func (a templateAction) control() bool {
+	keyword := strings.Fields(a.inner + " ")[0]
+	return controlActions[keyword] || strings.HasPrefix(a.inner, "/*") || assignment.MatchString(a.inner)
+}
+
+// source returns the action in template syntax, printed values are escaped
+func (a templateAction) source() string {
+	if a.control() {
+		return actionDelimiters[0] + a.left + a.inner + a.right + actionDelimiters[1]
+	}
+	return actionDelimiters[0] + a.left + "(" + a.inner + ") | docxText" + a.right + actionDelimiters[1]
+}
+
+// templateActions returns actions of a text
+func templateActions(text string) []templateAction {
+	var actions []templateAction
+	for from := 0; ; {
+		i := strings.Index(text[from:], actionDelimiters[0])
+		if i == -1 {
+			return actions
+		}
+		start := from + i
+		j := strings.Index(text[start+len(actionDelimiters[0]):], actionDelimiters[1])
+		if j == -1 {
+			return actions
+		}
+		end := start + len(actionDelimiters[0]) + j + len(actionDelimiters[1])
+		a := templateAction{start: start, end: end}
+		a.inner = templateQuotes.Replace(text[start+len(actionDelimiters[0]) : end-len(actionDelimiters[1])])
+		if strings.HasPrefix(a.inner, "- ") {
+			a.left, a.inner = "- ", a.inner[2:]
+		}
+		if strings.HasSuffix(a.inner, " -") {
+			a.right, a.inner = " -", a.inner[:len(a.inner)-2]
+		}
+		a.inner = strings.TrimSpace(a.inner)
+		actions = append(actions, a)
+		from = end
+	}
+}
+
+// execute renders a part as a template: actions are replaced with tokens, so the part
+// can be written as XML, and tokens of the XML are then replaced with actions
+func (t *goTemplate) execute(name string, root *node, strict bool) (*node, error) {
+	var sources []string
+	token := func(a templateAction) string {
+		sources = append(sources, a.source())
+		return tokenStart + strconv.Itoa(len(sources)-1) + tokenEnd
+	}
+	for _, p := range root.find("w:p") {
+		para := newParagraph(p)
+		actions := templateActions(para.text)
+		if len(actions) == 0 {
+			continue
+		}
+		if p.parent != nil && isControlOnly(para.text, actions) && !hasContent(p) {
+			// the paragraph (or the row) is replaced with its actions, so repeated content has no empty lines
+			var tokens strings.Builder
+			for _, a := range actions {
+				tokens.WriteString(token(a))
+			}
+			if tr := actionRow(p); tr != nil {
+				tr.replace(textNode(tokens.String()))
+			} else {
+				p.replace(textNode(tokens.String()))
+			}
+			continue
+		}
+		tokens := make([]string, len(actions))
+		for i, a := range actions {
+			tokens[i] = token(a)
+		}
+		for i := len(actions) - 1; i >= 0; i-- {
+			para.replace(actions[i].start, actions[i].end, tokens[i])
+		}
+	}
+	if len(sources) == 0 {
+		return root, nil
+	}
+	for _, n := range root.find("w:t") {
+		if strings.Contains(n.text(), tokenStart) {
+			n.setAttr("xml:space", "preserve")
+		}
+	}
+	data, err := root.bytes()
+	if err != nil {
+		return nil, err
+	}
+	src := actionToken.ReplaceAllStringFunc(string(data), func(s string) string {
+		i, _ := strconv.Atoi(actionToken.FindStringSubmatch(s)[1])
+		return sources[i]
+	})
+	tmpl := template.New(name).Funcs(template.FuncMap{"docxText": templateText}).Funcs(t.funcs)
+	if strict {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	if tmpl, err = tmpl.Parse(src); err != nil {
+		return nil, fmt.Errorf("Invalid template %s: %v", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, t.data); err != nil {
+		return nil, fmt.Errorf("Can't execute template %s: %v", name, err)
+	}
+	// the scanner doesn't match end tags, blocks which don't nest with paragraphs break them
+	if err = wellFormed(buf.Bytes()); err == nil {
+		root, err = scan(buf.Bytes())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Template %s produced invalid XML, actions like {{range}} should be in the same paragraph or in paragraphs or rows of their own: %v", name, err)
+	}
+	return root, nil
+}
+
+// wellFormed checks if elements of XML are properly nested
+func wellFormed(data []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// isControlOnly checks if a text has nothing else than actions which print nothing
+func isControlOnly(text string, actions []templateAction) bool {
+	last := 0
+	for _, a := range actions {
+		if !a.control() || !isBlank(text[last:a.start]) {
+			return false
+		}
+		last = a.end
+	}
+	return isBlank(text[last:])
+}
+
+// actionRow returns a table row of a paragraph when there's nothing else in the row
+func actionRow(p *node) *node {
+	if !p.parent.is("w:tc") {
+		return nil
+	}
+	tr := p.ancestor("w:tr")
+	if tr == nil {
+		return nil
+	}
+	for _, q := range tr.find("w:p") {
+		if q != p && (!isBlank(newParagraph(q).text) || hasContent(q)) {
+			return nil
+		}
+	}
+	return tr
+}
+
+// templateText escapes a printed value, missing values print nothing
+func templateText(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(fmt.Sprint(v)))
+	return buf.String()
+}
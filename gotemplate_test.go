@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTextTemplate(t *testing.T) {
+	type item struct {
+		Name  string
+		Price float64
+	}
+	data := struct {
+		Customer string
+		VIP      bool
+		Items    []item
+	}{"Tom & <Jerry>", true, []item{{"Pen", 2}, {"Book", 12.5}}}
+	row := func(cells ...string) string {
+		s := `<w:tr>`
+		for _, cell := range cells {
+			s += `<w:tc>` + p(cell) + `</w:tc>`
+		}
+		return s + `</w:tr>`
+	}
+	body := `<w:p><w:r><w:t>Dear {{if .VIP}}valued {{end}}{{.Cust</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>omer}},</w:t></w:r></w:p>` +
+		p("{{range .Items}}") + p("{{.Name | upper}}: {{printf “%.2f” .Price}}") + p("{{end}}") +
+		`<w:tbl>` + row("Item", "Price") + row("{{range $i, $item := .Items}}", "") + row("{{$i}}. {{$item.Name}}", "{{$item.Price}}") + row("{{end}}") + `</w:tbl>` +
+		p("[note]")
+	doc := newTestDocx(t, body, nil).
+		TextTemplate(data).
+		TemplateFuncs(template.FuncMap{"upper": strings.ToUpper}).
+		ReplaceDict(Dict{"note": "Thanks"})
+	got := renderBody(t, doc)
+	preserve := func(s string) string {
+		return strings.ReplaceAll(s, "<w:t>", `<w:t xml:space="preserve">`)
+	}
+	want := `<w:p><w:r><w:t xml:space="preserve">Dear valued Tom &amp; &lt;Jerry&gt;</w:t></w:r><w:r><w:rPr><w:b></w:b></w:rPr><w:t>,</w:t></w:r></w:p>` +
+		preserve(p("PEN: 2.00")+p("BOOK: 12.50")) +
+		`<w:tbl>` + row("Item", "Price") + preserve(row("0. Pen", "2")+row("1. Book", "12.5")) + `</w:tbl>` +
+		p("Thanks")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	// a block has to end in the same paragraph or in paragraphs of the same level
+	for _, body := range []string{p("{{if}}"), p("{{.Missing}}"), `<w:tbl>` + row("x {{range .Items}}") + `</w:tbl>` + p("{{end}}")} {
+		if _, err := newTestDocx(t, body, nil).TextTemplate(data).WriteTo(new(bytes.Buffer)); err == nil {
+			t.Errorf("Expected error of %s", body)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// loopOptions sort and group records of repeated content, they are written like filters
+// after the name of a collection: items|sort:category,-price|group:category
+type loopOptions struct {
+	// sortKeys are fields of records, descending ones start with "-"
+	sortKeys []string
+	group    string
+}
+
+// parseLoopOptions splits a name of a collection to the name and its options
+func parseLoopOptions(name string) (string, loopOptions, error) {
+	name, calls := parseFilters(name)
+	var o loopOptions
+	for _, call := range calls {
+		switch {
+		case call.name == "sort" && len(call.args) > 0:
+			o.sortKeys = append(o.sortKeys, call.args...)
+		case call.name == "group" && len(call.args) == 1:
+			o.group = call.args[0]
+		default:
+			return name, o, fmt.Errorf("Invalid option %s of %s, only sort:field,-field and group:field can be used", call.name, name)
+		}
+	}
+	return name, o, nil
+}
+
+// recordGroup are records with the same value of the group field
+type recordGroup struct {
+	key     interface{}
+	records []Dict
+}
+
+// groups sorts records and splits them to groups in order of their first records,
+// all records make a single group when they aren't grouped
+func (o loopOptions) groups(records []Dict) []recordGroup {
+	records = append([]Dict(nil), records...)
+	if len(o.sortKeys) > 0 {
+		sort.SliceStable(records, func(i, j int) bool {
+			for _, key := range o.sortKeys {
+				descending := strings.HasPrefix(key, "-")
+				key = strings.TrimPrefix(key, "-")
+				x, _ := recordField(records[i], key)
+				y, _ := recordField(records[j], key)
+				if c := compareValues(x, y); c != 0 {
+					return (c < 0) != descending
+				}
+			}
+			return false
+		})
+	}
+	if o.group == "" {
+		return []recordGroup{{records: records}}
+	}
+	var groups []recordGroup
+	index := make(map[string]int)
+	for _, r := range records {
+		key, _ := recordField(r, o.group)
+		id := fmt.Sprint(key)
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, recordGroup{key: key})
+		}
+		groups[i].records = append(groups[i].records, r)
+	}
+	return groups
+}
+
+// recordField returns a value of a record, dotted names refer to nested values
+func recordField(r Dict, name string) (interface{}, bool) {
+	var v interface{} = r
+	for _, key := range strings.Split(name, ".") {
+		var ok bool
+		if v, ok = field(v, key); !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// compareValues orders numbers by their values and other values as text, missing values go first
+func compareValues(x, y interface{}) int {
+	switch {
+	case x == nil && y == nil:
+		return 0
+	case x == nil:
+		return -1
+	case y == nil:
+		return 1
+	}
+	if a, ok := toNumber(x); ok {
+		if b, ok := toNumber(y); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(x), fmt.Sprint(y))
+}
@@ -0,0 +1,284 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Pages selects pages of a section which use a header or a footer
+type Pages string
+
+// Pages of a section
+const (
+	// DefaultPages are all pages, or odd pages when even pages have their own headers
+	DefaultPages Pages = "default"
+	// FirstPage is the first page of a section, it's shown when title page is enabled
+	FirstPage Pages = "first"
+	// EvenPages are shown when even and odd headers are enabled
+	EvenPages Pages = "even"
+)
+
+const (
+	nsW = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+	contentTypeHeader = "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"
+	contentTypeFooter = "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"
+)
+
+// settingsOrder is an order of <w:settings> child elements required by the schema
+var settingsOrder = []string{
+	"w:writeProtection", "w:view", "w:zoom", "w:removePersonalInformation", "w:removeDateAndTime",
+	"w:doNotDisplayPageBoundaries", "w:displayBackgroundShape", "w:printPostScriptOverText",
+	"w:printFractionalCharacterWidth", "w:printFormsData", "w:embedTrueTypeFonts", "w:embedSystemFonts",
+	"w:saveSubsetFonts", "w:saveFormsData", "w:mirrorMargins", "w:alignBordersAndEdges",
+	"w:bordersDoNotSurroundHeader", "w:bordersDoNotSurroundFooter", "w:gutterAtTop",
+	"w:hideSpellingErrors", "w:hideGrammaticalErrors", "w:activeWritingStyle", "w:proofState",
+	"w:formsDesign", "w:attachedTemplate", "w:linkStyles", "w:stylePaneFormatFilter",
+	"w:stylePaneSortMethod", "w:documentType", "w:mailMerge", "w:revisionView", "w:trackRevisions",
+	"w:doNotTrackMoves", "w:doNotTrackFormatting", "w:documentProtection", "w:autoFormatOverride",
+	"w:styleLockTheme", "w:styleLockQFSet", "w:defaultTabStop", "w:autoHyphenation",
+	"w:consecutiveHyphenLimit", "w:hyphenationZone", "w:doNotHyphenateCaps", "w:showEnvelope",
+	"w:summaryLength", "w:clickAndTypeStyle", "w:defaultTableStyle", "w:evenAndOddHeaders",
+	"w:bookFoldRevPrinting", "w:bookFold", "w:bookFoldPrinting", "w:drawingGridHorizontalSpacing",
+	"w:drawingGridVerticalSpacing", "w:displayHorizontalDrawingGridEvery",
+	"w:displayVerticalDrawingGridEvery", "w:doNotUseMarginsForDrawingGridOrigin",
+	"w:drawingGridHorizontalOrigin", "w:drawingGridVerticalOrigin", "w:doNotShadeFormData",
+	"w:noPunctuationKerning", "w:characterSpacingControl", "w:printTwoOnOne",
+	"w:strictFirstAndLastChars", "w:noLineBreaksAfter", "w:noLineBreaksBefore", "w:savePreviewPicture",
+	"w:doNotValidateAgainstSchema", "w:saveInvalidXml", "w:ignoreMixedContent",
+	"w:alwaysShowPlaceholderText", "w:doNotDemarcateInvalidXml", "w:saveXmlDataOnly",
+	"w:useXSLTWhenSaving", "w:saveThroughXslt", "w:showXMLTags", "w:alwaysMergeEmptyNamespace",
+	"w:updateFields", "w:hdrShapeDefaults", "w:footnotePr", "w:endnotePr", "w:compat", "w:docVars",
+	"w:rsids", "m:mathPr", "w:attachedSchema", "w:themeFontLang", "w:clrSchemeMapping",
+	"w:doNotIncludeSubdocsInStats", "w:doNotAutoCompressPictures", "w:forceUpgrade", "w:captions",
+	"w:readModeInkLockDown", "w:smartTagType", "sl:schemaLibrary", "w:shapeDefaults",
+	"w:doNotEmbedSmartTags", "w:decimalSymbol", "w:listSeparator",
+}
+
+// headerContent is a header or a footer which replaces the one of the template
+type headerContent struct {
+	footer bool
+	pages  Pages
+	// content is a value like in Dict
+	content interface{}
+}
+
+// Header sets a header of given pages of all sections, its content is a value like in Dict
+// (a text, RichText, Image, BuildingBlock...). A header of the first page enables
+// title page in sections and a header of even pages enables even and odd headers,
+// unless they are set explicitly
+func (doc *Docx) Header(pages Pages, content interface{}) *Docx {
+	doc.headers = append(doc.headers, headerContent{pages: pages, content: content})
+	return doc
+}
+
+// Footer sets a footer of given pages of all sections, like Header
+func (doc *Docx) Footer(pages Pages, content interface{}) *Docx {
+	doc.headers = append(doc.headers, headerContent{footer: true, pages: pages, content: content})
+	return doc
+}
+
+// TitlePage enables or disables different header and footer of the first page of all sections
+func (doc *Docx) TitlePage(enabled bool) *Docx {
+	doc.titlePage = &enabled
+	return doc
+}
+
+// EvenAndOddHeaders enables or disables different headers and footers of even and odd pages
+func (doc *Docx) EvenAndOddHeaders(enabled bool) *Docx {
+	doc.evenAndOdd = &enabled
+	return doc
+}
+
+// setHeaders creates header and footer parts and refers them from all sections
+func (ctx *renderContext) setHeaders() error {
+	titlePage, evenAndOdd := ctx.doc.titlePage, ctx.doc.evenAndOdd
+	for _, h := range ctx.doc.headers {
+		tag, relType := "w:headerReference", relTypeHeader
+		if h.footer {
+			tag, relType = "w:footerReference", relTypeFooter
+		}
+		name, err := ctx.addHeaderPart(h)
+		if err != nil {
+			return err
+		}
+		rels, err := ctx.pkg.relationships(ctx.part)
+		if err != nil {
+			return err
+		}
+		id := rels.add(relType, relTarget(ctx.part, name))
+		ctx.declareNS("r", nsR)
+		for _, sectPr := range sections(ctx.root) {
+			for _, ref := range sectPr.elements(tag) {
+				if ref.attrValue("w:type") == string(h.pages) {
+					ref.remove()
+				}
+			}
+			// references are the first children of section properties
+			sectPr.insert(0, elem(tag, "w:type", string(h.pages), "r:id", id))
+		}
+		enabled := true
+		if h.pages == FirstPage && titlePage == nil {
+			titlePage = &enabled
+		}
+		if h.pages == EvenPages && evenAndOdd == nil {
+			evenAndOdd = &enabled
+		}
+	}
+	if titlePage != nil {
+		for _, sectPr := range sections(ctx.root) {
+			sectPr.removeChild("w:titlePg")
+			if *titlePage {
+				sectPr.setChild(elem("w:titlePg"), sectPrOrder)
+			}
+		}
+	}
+	if evenAndOdd != nil {
+		return ctx.setEvenAndOddHeaders(*evenAndOdd)
+	}
+	return nil
+}
+
+// addHeaderPart stores a header or a footer with given content in a new part
+func (ctx *renderContext) addHeaderPart(h headerContent) (string, error) {
+	tag, prefix, contentType, style := "w:hdr", "word/header", contentTypeHeader, "Header"
+	if h.footer {
+		tag, prefix, contentType, style = "w:ftr", "word/footer", contentTypeFooter, "Footer"
+	}
+	name := ctx.pkg.uniqueName(prefix, ".xml")
+	root, err := parse(strings.NewReader(fmt.Sprintf(`%s<%s xmlns:w="%s" xmlns:r="%s"></%s>`, xml.Header, tag, nsW, nsR, tag)))
+	if err != nil {
+		return "", err
+	}
+	el := root.documentElement()
+	header := newRenderContext(ctx.doc, ctx.pkg, name, root)
+	value, err := toValue(h.content)
+	if err != nil {
+		return "", err
+	}
+	if block, ok := value.(blockValue); ok {
+		blocks, err := block.blocks(header)
+		if err != nil {
+			return "", err
+		}
+		el.append(blocks...)
+		if err := header.renderBlocks(blocks); err != nil {
+			return "", err
+		}
+	} else {
+		p := elem("w:p")
+		pPr := elem("w:pPr")
+		pPr.append(elem("w:pStyle", "w:val", style))
+		runs, err := value.runs(header, nil)
+		if err != nil {
+			return "", err
+		}
+		p.append(pPr)
+		p.append(runs...)
+		formatParagraph(p, value)
+		el.append(p)
+	}
+	if el.child("w:p") == nil {
+		el.append(elem("w:p"))
+	}
+	ct, err := ctx.pkg.types()
+	if err != nil {
+		return "", err
+	}
+	ct.addOverride(name, contentType)
+	return name, ctx.pkg.setPart(name, root)
+}
+
+// setEvenAndOddHeaders changes the document setting of different even and odd headers
+func (ctx *renderContext) setEvenAndOddHeaders(enabled bool) error {
+	name, ok := ctx.pkg.relatedPart(documentXML, relTypeSettings)
+	if !ok || !ctx.pkg.has(name) {
+		if !enabled {
+			return nil
+		}
+		return fmt.Errorf("Invalid DOCX document: settings of the document not found")
+	}
+	root, ok, err := ctx.parseAuxiliaryPart(name)
+	if !ok {
+		return err
+	}
+	settings := root.documentElement()
+	settings.removeChild("w:evenAndOddHeaders")
+	if enabled {
+		settings.setChild(elem("w:evenAndOddHeaders"), settingsOrder)
+	}
+	return ctx.pkg.setPart(name, root)
+}
+
+// Alignment of a paragraph
+type Alignment string
+
+// Alignments of paragraphs
+const (
+	AlignLeft   Alignment = "left"
+	AlignCenter Alignment = "center"
+	AlignRight  Alignment = "right"
+)
+
+// PageNumbers is a text with the number of the current page and the number of pages,
+// like "Page 2 of 10". It's a paragraph of its own unless it's used inside text
+type PageNumbers struct {
+	// Format is a text where {page} is the page number and {pages} is the number of pages,
+	// it's "Page {page} of {pages}" by default
+	Format string
+	// Style is a paragraph style, "Footer" by default
+	Style string
+	Align Alignment
+}
+
+func (pn PageNumbers) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	format := pn.Format
+	if format == "" {
+		format = "Page {page} of {pages}"
+	}
+	var runs []*node
+	for format != "" {
+		i, field, token := len(format), Field{}, ""
+		for t, f := range map[string]Field{"{page}": {"PAGE", "1"}, "{pages}": {"NUMPAGES", "1"}} {
+			if j := strings.Index(format, t); j != -1 && j < i {
+				i, field, token = j, f, t
+			}
+		}
+		if i > 0 {
+			runs = append(runs, newRun(rPr, format[:i]))
+		}
+		if token == "" {
+			break
+		}
+		fieldRuns, _ := field.runs(ctx, rPr)
+		runs = append(runs, fieldRuns...)
+		format = format[i+len(token):]
+	}
+	return runs, nil
+}
+
+func (pn PageNumbers) blocks(ctx *renderContext) ([]*node, error) {
+	style := pn.Style
+	if style == "" {
+		style = "Footer"
+	}
+	pPr := elem("w:pPr")
+	pPr.append(elem("w:pStyle", "w:val", style))
+	if pn.Align != "" {
+		pPr.setChild(elem("w:jc", "w:val", string(pn.Align)), pPrOrder)
+	}
+	runs, err := pn.runs(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	p := elem("w:p")
+	p.append(pPr)
+	p.append(runs...)
+	return []*node{p}, nil
+}
+
+// PageNumbers sets the footer of all sections to page numbers like "Page 2 of 10"
+func (doc *Docx) PageNumbers(pn PageNumbers) *Docx {
+	return doc.Footer(DefaultPages, pn)
+}
@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeSettings + `" Target="settings.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeHeader + `" Target="header1.xml"/>` +
+			`</Relationships>`,
+		"word/settings.xml": `<w:settings xmlns:w="w"><w:zoom w:percent="100"/><w:characterSpacingControl w:val="doNotCompress"/></w:settings>`,
+		"word/header1.xml":  `<w:hdr xmlns:w="w"><w:p><w:r><w:t>Old</w:t></w:r></w:p></w:hdr>`,
+	}
+	body := p("Text") + `<w:p><w:pPr><w:sectPr><w:headerReference w:type="default" r:id="rId2"/><w:pgSz w:w="11906" w:h="16838"/>` +
+		`</w:sectPr></w:pPr></w:p>` + `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`
+	doc := newTestDocx(t, body, parts).
+		Header(DefaultPages, "Report").
+		Header(FirstPage, RichText{{Text: "Confidential", Bold: true}}).
+		Footer(EvenPages, "Even footer")
+	got := renderParts(t, doc)
+	want := `<w:sectPr><w:footerReference w:type="even" r:id="rId5"></w:footerReference>` +
+		`<w:headerReference w:type="first" r:id="rId4"></w:headerReference>` +
+		`<w:headerReference w:type="default" r:id="rId3"></w:headerReference>` +
+		`<w:pgSz w:w="11906" w:h="16838"></w:pgSz><w:titlePg></w:titlePg></w:sectPr>`
+	if strings.Count(got[documentXML], want) != 2 {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	for name, want := range map[string]string{
+		"word/header2.xml": `<w:hdr xmlns:w="` + nsW + `" xmlns:r="` + nsR + `"><w:p><w:pPr><w:pStyle w:val="Header"></w:pStyle></w:pPr>` +
+			`<w:r><w:t>Report</w:t></w:r></w:p></w:hdr>`,
+		"word/header3.xml":             `<w:r><w:rPr><w:b></w:b><w:bCs></w:bCs></w:rPr><w:t>Confidential</w:t></w:r>`,
+		"word/footer1.xml":             `<w:pStyle w:val="Footer"></w:pStyle></w:pPr><w:r><w:t>Even footer</w:t></w:r>`,
+		"word/settings.xml":            `<w:zoom w:percent="100"></w:zoom><w:evenAndOddHeaders></w:evenAndOddHeaders><w:characterSpacingControl`,
+		"word/_rels/document.xml.rels": `Id="rId5" Type="` + relTypeFooter + `" Target="footer1.xml"`,
+		contentTypesXML:                `<Override PartName="/word/footer1.xml" ContentType="` + contentTypeFooter + `"></Override>`,
+	} {
+		if !strings.Contains(got[name], want) {
+			t.Errorf("%s: %s\nwant: %s", name, got[name], want)
+		}
+	}
+
+	got = renderParts(t, newTestDocx(t, body, parts).Header(FirstPage, "First").TitlePage(false).EvenAndOddHeaders(false))
+	if strings.Contains(got[documentXML], "titlePg") || strings.Contains(got["word/settings.xml"], "evenAndOdd") {
+		t.Errorf("Settings weren't disabled: %s %s", got[documentXML], got["word/settings.xml"])
+	}
+}
+
+func TestPageNumbers(t *testing.T) {
+	body := p("Text") + `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr>`
+	got := renderParts(t, newTestDocx(t, body, nil).PageNumbers(PageNumbers{Align: AlignCenter}))
+	field := func(code string) string {
+		return `<w:r><w:fldChar w:fldCharType="begin"></w:fldChar></w:r>` +
+			`<w:r><w:instrText xml:space="preserve"> ` + code + ` </w:instrText></w:r>` +
+			`<w:r><w:fldChar w:fldCharType="separate"></w:fldChar></w:r><w:r><w:t>1</w:t></w:r>` +
+			`<w:r><w:fldChar w:fldCharType="end"></w:fldChar></w:r>`
+	}
+	want := `<w:p><w:pPr><w:pStyle w:val="Footer"></w:pStyle><w:jc w:val="center"></w:jc></w:pPr>` +
+		`<w:r><w:t xml:space="preserve">Page </w:t></w:r>` + field("PAGE") +
+		`<w:r><w:t xml:space="preserve"> of </w:t></w:r>` + field("NUMPAGES") + `</w:p>`
+	if !strings.Contains(got["word/footer1.xml"], want) {
+		t.Errorf("got: %s\nwant: %s", got["word/footer1.xml"], want)
+	}
+	if !strings.Contains(got[documentXML], `<w:footerReference w:type="default" r:id="rId1">`) {
+		t.Errorf("Footer wasn't referenced: %s", got[documentXML])
+	}
+
+	got = renderParts(t, newTestDocx(t, p("[pages]"), nil).ReplaceDict(Dict{"pages": PageNumbers{Format: "{page}/{pages}"}}))
+	if !strings.Contains(got[documentXML], `<w:r><w:fldChar w:fldCharType="end"></w:fldChar></w:r><w:r><w:t>/</w:t></w:r>`) {
+		t.Errorf("Page numbers weren't inserted: %s", got[documentXML])
+	}
+}
+
+func TestRenderHeaderParts(t *testing.T) {
+	parts := map[string]string{
+		"word/header1.xml": `<w:hdr xmlns:w="w">` + p("[company] No. [number]") + `</w:hdr>`,
+		"word/footer1.xml": `<w:ftr xmlns:w="w">` + p("Issued [date]") + `</w:ftr>`,
+	}
+	dict := Dict{"company": "ACME", "number": 42, "date": "May 1"}
+	got := renderParts(t, newTestDocx(t, p("[company]"), parts).ReplaceDict(dict))
+	for name, want := range map[string]string{"word/header1.xml": "ACME No. 42", "word/footer1.xml": "Issued May 1"} {
+		if !strings.Contains(got[name], want) {
+			t.Errorf("Expected %q in %s: %s", want, name, got[name])
+		}
+	}
+
+	got = renderParts(t, newTestDocx(t, p("[company]"), parts).ReplaceDict(dict).RenderParts("word/footer*.xml"))
+	if !strings.Contains(got["word/header1.xml"], "[company]") || !strings.Contains(got["word/footer1.xml"], "May 1") {
+		t.Errorf("Expected only footers to be rendered: %s %s", got["word/header1.xml"], got["word/footer1.xml"])
+	}
+	got = renderParts(t, newTestDocx(t, p("[company]"), parts).ReplaceDict(dict).RenderParts())
+	if !strings.Contains(got["word/footer1.xml"], "[date]") || !strings.Contains(got[documentXML], "ACME") {
+		t.Errorf("Expected only the document to be rendered: %s", got["word/footer1.xml"])
+	}
+}
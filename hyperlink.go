@@ -0,0 +1,55 @@
+package docx
+
+import (
+	"net/url"
+	"strings"
+)
+
+// replaceHyperlinks replaces placeholders in addresses of hyperlinks, like https://example.com/track/[id].
+// Text of hyperlinks is replaced like any other text. Every changed hyperlink gets its own relationship,
+// so copies of a hyperlink (e.g. in repeated rows) can point to different addresses
+func (ctx *renderContext) replaceHyperlinks() error {
+	links := ctx.root.find("w:hyperlink")
+	if len(links) == 0 {
+		return nil
+	}
+	rels, err := ctx.pkg.relationships(ctx.part)
+	if err != nil {
+		return err
+	}
+	opening, closing := ctx.doc.openingBracket, ctx.doc.closingBracket
+	// Word writes brackets of addresses percent-encoded
+	unescape := strings.NewReplacer(url.QueryEscape(opening), opening, url.QueryEscape(closing), closing)
+	for _, link := range links {
+		rel, ok := rels.target(link.attrValue("r:id"))
+		if !ok || rel.TargetMode != "External" {
+			continue
+		}
+		target := unescape.Replace(rel.Target)
+		found := ctx.doc.placeholders(target)
+		changed := false
+		for i := len(found) - 1; i >= 0; i-- {
+			ph := found[i]
+			v, ok, err := ctx.scopeOf(link).resolve(ph.name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			value, err := toValue(v)
+			if err != nil {
+				return err
+			}
+			if text, ok := value.(Text); ok {
+				target = target[:ph.start] + string(text) + target[ph.end:]
+				changed = true
+			}
+		}
+		if changed {
+			rel.Target = target
+			link.setAttr("r:id", rels.addRelationship(rel))
+		}
+	}
+	return nil
+}
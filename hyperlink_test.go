@@ -0,0 +1,31 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHyperlinks(t *testing.T) {
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" ` +
+			`Target="https://example.com/track/%5Btracking_number%5D" TargetMode="External"/>` +
+			`</Relationships>`,
+	}
+	body := `<w:p><w:r><w:t xml:space="preserve">Parcel </w:t></w:r><w:hyperlink r:id="rId1">` +
+		`<w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t>[tracking</w:t></w:r>` +
+		`<w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t>_number]</w:t></w:r></w:hyperlink></w:p>`
+	doc := newTestDocx(t, body, parts).ReplaceDict(Dict{"tracking_number": "AB123"})
+	got := renderParts(t, doc)
+	want := `<w:p><w:r><w:t xml:space="preserve">Parcel </w:t></w:r><w:hyperlink r:id="rId2">` +
+		`<w:r><w:rPr><w:rStyle w:val="Hyperlink"></w:rStyle></w:rPr><w:t>AB123</w:t></w:r></w:hyperlink></w:p>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	want = `Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" ` +
+		`Target="https://example.com/track/AB123" TargetMode="External"`
+	if !strings.Contains(got["word/_rels/document.xml.rels"], want) {
+		t.Errorf("got: %s\nwant: %s", got["word/_rels/document.xml.rels"], want)
+	}
+}
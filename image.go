@@ -0,0 +1,302 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	// image formats which dimensions can be detected
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"strconv"
+)
+
+const (
+	nsWP = "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"
+	nsR  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// Length is a distance in EMUs (English Metric Units) used by DrawingML
+type Length int64
+
+// Units which can be used to express Length
+const (
+	EMU        Length = 1
+	Pixel      Length = 9525 // at 96 DPI
+	Point      Length = 12700
+	Twip       Length = 635 // twentieth of a point
+	Inch       Length = 914400
+	Centimeter Length = 360000
+	Millimeter Length = 36000
+)
+
+// Image is a PNG, JPEG, GIF, SVG, EMF or WMF picture inserted inline with text
+type Image struct {
+	Data []byte
+	// Width and Height of the picture in a document, if only one of them is set the other one
+	// keeps the aspect ratio, if none is set native size of the image is used
+	Width, Height Length
+	// DPI is the resolution of PNG, JPEG and GIF pictures which gives their native size, 96 by default
+	DPI int
+	// Crop cuts edges of the picture, Width, Height and native size are those of the cropped picture
+	Crop Crop
+	// Description is an alternative text of the picture
+	Description string
+	// Fallback is a PNG picture of an SVG image shown by applications which don't support SVG,
+	// a blank picture of the same size is used when it's missing
+	Fallback []byte
+}
+
+// Crop are parts of a picture cut from its edges, as fractions of its width or height (0.1 is 10%)
+type Crop struct {
+	Left, Top, Right, Bottom float64
+}
+
+// valid checks if the cropped picture isn't empty
+func (c Crop) valid() bool {
+	for _, v := range []float64{c.Left, c.Top, c.Right, c.Bottom} {
+		if v < 0 || v >= 1 {
+			return false
+		}
+	}
+	return c.Left+c.Right < 1 && c.Top+c.Bottom < 1
+}
+
+// imageTypes maps supported content types to file extensions
+var imageTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/gif":  "gif",
+	// SVG pictures have a PNG fallback for applications which don't support them
+	svgContentType: "svg",
+	emfContentType: "emf",
+	wmfContentType: "wmf",
+}
+
+func (img Image) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	drawing, err := img.drawing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := elem("w:r")
+	if rPr != nil {
+		r.append(rPr.clone())
+	}
+	r.append(drawing)
+	return []*node{r}, nil
+}
+
+// size returns dimensions of the picture in a document
+func (img Image) size() (Length, Length, error) {
+	if img.Width > 0 && img.Height > 0 {
+		return img.Width, img.Height, nil
+	}
+	width, height, err := img.croppedSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	switch {
+	case img.Width > 0:
+		height = height * img.Width / width
+		width = img.Width
+	case img.Height > 0:
+		width = width * img.Height / height
+		height = img.Height
+	}
+	return width, height, nil
+}
+
+// croppedSize returns native dimensions of the cropped picture
+func (img Image) croppedSize() (Length, Length, error) {
+	if !img.Crop.valid() {
+		return 0, 0, fmt.Errorf("Invalid crop of image: %+v", img.Crop)
+	}
+	width, height, err := img.nativeSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	c := img.Crop
+	return Length(float64(width) * (1 - c.Left - c.Right)), Length(float64(height) * (1 - c.Top - c.Bottom)), nil
+}
+
+// nativeSize returns dimensions of the picture, pixels of raster pictures have size given by DPI
+func (img Image) nativeSize() (Length, Length, error) {
+	switch contentType := imageContentType(img.Data); contentType {
+	case svgContentType:
+		return svgSize(img.Data)
+	case emfContentType, wmfContentType:
+		return metafileSize(img.Data, contentType)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil {
+		return 0, 0, err
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return 0, 0, fmt.Errorf("Invalid image size: %dx%d", cfg.Width, cfg.Height)
+	}
+	pixel := Pixel
+	if img.DPI > 0 {
+		pixel = Inch / Length(img.DPI)
+	}
+	return Length(cfg.Width) * pixel, Length(cfg.Height) * pixel, nil
+}
+
+// imageContentType detects the type of a picture, like "image/png"
+func imageContentType(data []byte) string {
+	if isSVG(data) {
+		return svgContentType
+	}
+	if contentType := metafileType(data); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
+
+// drawing stores the picture in the archive and returns <w:drawing> element referring to it
+func (img Image) drawing(ctx *renderContext) (*node, error) {
+	if contentType := imageContentType(img.Data); imageTypes[contentType] == "" {
+		return nil, fmt.Errorf("Unsupported image type: %s", contentType)
+	}
+	width, height, err := img.size()
+	if err != nil {
+		return nil, err
+	}
+	ctx.declareNS("wp", nsWP)
+	ctx.declareNS("r", nsR)
+	id := ctx.nextDocPrID()
+	nodes, err := parseFragment(fmt.Sprintf(inlineDrawingXML,
+		width, height, id, id, escape(img.Description), id, width, height))
+	if err != nil {
+		return nil, err
+	}
+	blip := nodes[0].find("a:blip")[0]
+	if err := img.setBlip(ctx, blip); err != nil {
+		return nil, err
+	}
+	img.setCrop(blip.parent)
+	return nodes[0], nil
+}
+
+// setBlip stores the picture in the archive and points a blip to it
+func (img Image) setBlip(ctx *renderContext, blip *node) error {
+	contentType := imageContentType(img.Data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return fmt.Errorf("Unsupported image type: %s", contentType)
+	}
+	relID, err := ctx.addMedia(img.Data, ext, contentType)
+	if err != nil {
+		return err
+	}
+	blip.setAttr("r:embed", relID)
+	blip.removeAttr("r:link")
+	blip.removeChild("a:extLst")
+	if contentType == svgContentType {
+		return img.addSVGFallback(ctx, blip)
+	}
+	return nil
+}
+
+// setCrop replaces the crop of a picture's fill with the crop of the image
+func (img Image) setCrop(fill *node) {
+	fill.removeChild("a:srcRect")
+	if img.Crop == (Crop{}) {
+		return
+	}
+	// the crop is in thousandths of a percent
+	rect := elem("a:srcRect")
+	for _, edge := range []struct {
+		name string
+		v    float64
+	}{{"l", img.Crop.Left}, {"t", img.Crop.Top}, {"r", img.Crop.Right}, {"b", img.Crop.Bottom}} {
+		if edge.v > 0 {
+			rect.setAttr(edge.name, strconv.Itoa(int(edge.v*100000+0.5)))
+		}
+	}
+	i := 0
+	if blip := fill.child("a:blip"); blip != nil {
+		i = blip.index() + 1
+	}
+	fill.insert(i, rect)
+}
+
+const inlineDrawingXML = `<w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">` +
+	`<wp:extent cx="%d" cy="%d"/><wp:effectExtent l="0" t="0" r="0" b="0"/>` +
+	`<wp:docPr id="%d" name="Picture %d" descr="%s"/>` +
+	`<wp:cNvGraphicFramePr><a:graphicFrameLocks xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" noChangeAspect="1"/></wp:cNvGraphicFramePr>` +
+	`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">` +
+	`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">` +
+	`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">` +
+	`<pic:nvPicPr><pic:cNvPr id="%d" name="Picture"/><pic:cNvPicPr/></pic:nvPicPr>` +
+	`<pic:blipFill><a:blip/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>` +
+	`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm>` +
+	`<a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>` +
+	`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing>`
+
+// Wrap is a way text flows around a floating picture
+type Wrap int
+
+// Wrapping styles of floating pictures
+const (
+	// WrapInFront puts the picture over the text
+	WrapInFront Wrap = iota
+	// WrapBehind puts the picture behind the text, like a watermark
+	WrapBehind
+	// WrapSquare makes text flow around the picture bounds
+	WrapSquare
+	// WrapTopAndBottom keeps text only above and below the picture
+	WrapTopAndBottom
+)
+
+// FloatingImage is a picture placed at a position of the page, so it doesn't move text.
+// It's useful for stamps, seals or "COPY" overlays. The picture is anchored
+// to the paragraph of the replaced variable and shown on its page
+type FloatingImage struct {
+	Image
+	// X and Y are distances of the top left corner of the picture from the page edges
+	X, Y Length
+	Wrap Wrap
+}
+
+func (img FloatingImage) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	drawing, err := img.drawing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inline := drawing.child("wp:inline")
+	behind := 0
+	if img.Wrap == WrapBehind {
+		behind = 1
+	}
+	nodes, err := parseFragment(fmt.Sprintf(anchorXML, ctx.docPrID, behind, img.X, img.Y))
+	if err != nil {
+		return nil, err
+	}
+	anchor := nodes[0]
+	wrap := elem("wp:wrapNone")
+	switch img.Wrap {
+	case WrapSquare:
+		wrap = elem("wp:wrapSquare", "wrapText", "bothSides")
+	case WrapTopAndBottom:
+		wrap = elem("wp:wrapTopAndBottom")
+	}
+	anchor.append(inline.child("wp:extent"), inline.child("wp:effectExtent"), wrap)
+	for _, tag := range []string{"wp:docPr", "wp:cNvGraphicFramePr", "a:graphic"} {
+		anchor.append(inline.child(tag))
+	}
+	inline.replace(anchor)
+	r := elem("w:r")
+	if rPr != nil {
+		r.append(rPr.clone())
+	}
+	r.append(drawing)
+	return []*node{r}, nil
+}
+
+// anchorXML is a start of <wp:anchor> element with z-order, behindDoc flag and position on the page
+const anchorXML = `<wp:anchor distT="0" distB="0" distL="114300" distR="114300" simplePos="0" ` +
+	`relativeHeight="%d" behindDoc="%d" locked="0" layoutInCell="1" allowOverlap="1">` +
+	`<wp:simplePos x="0" y="0"/>` +
+	`<wp:positionH relativeFrom="page"><wp:posOffset>%d</wp:posOffset></wp:positionH>` +
+	`<wp:positionV relativeFrom="page"><wp:posOffset>%d</wp:posOffset></wp:positionV></wp:anchor>`
@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"sort"
+)
+
+// IntegrityReport lists media which aren't used and relationships to missing parts
+type IntegrityReport struct {
+	// OrphanedMedia are names of media parts no relationship refers to
+	OrphanedMedia       []string             `json:"orphanedMedia"`
+	BrokenRelationships []BrokenRelationship `json:"brokenRelationships"`
+}
+
+// BrokenRelationship is a relationship whose target part doesn't exist
+type BrokenRelationship struct {
+	// Part is the source of the relationship, it's empty for relationships of the package
+	Part   string `json:"part"`
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Target string `json:"target"`
+}
+
+// CheckIntegrity reports media of the template which aren't referenced by any relationship
+// and relationships pointing at parts which don't exist
+func (doc *Docx) CheckIntegrity() (IntegrityReport, error) {
+	var report IntegrityReport
+	if doc.err != nil {
+		return report, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return report, err
+	}
+	refs, err := referencedParts(p, "/media/", mediaRelTypes)
+	if err != nil {
+		return report, err
+	}
+	for name, references := range refs {
+		if len(references) == 0 {
+			report.OrphanedMedia = append(report.OrphanedMedia, name)
+		}
+	}
+	sort.Strings(report.OrphanedMedia)
+	for _, relsPart := range p.names {
+		source, ok := relationshipSource(relsPart)
+		if !ok || (source != "" && !p.has(source)) {
+			continue
+		}
+		rels, err := p.relationships(source)
+		if err != nil {
+			return report, err
+		}
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode == "External" || p.has(resolveTarget(source, rel.Target)) {
+				continue
+			}
+			report.BrokenRelationships = append(report.BrokenRelationships,
+				BrokenRelationship{Part: source, ID: rel.ID, Type: rel.Type, Target: rel.Target})
+		}
+	}
+	return report, nil
+}
+
+// PruneOrphanedMedia removes media of the template which aren't referenced by any relationship
+func (doc *Docx) PruneOrphanedMedia() *Docx {
+	report, err := doc.CheckIntegrity()
+	if err != nil {
+		doc.err = err
+		return doc
+	}
+	for _, name := range report.OrphanedMedia {
+		doc.RemovePart(name)
+	}
+	return doc
+}
@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeImage + `" Target="media/missing.png"/>` +
+			`<Relationship Id="rId3" Type="` + relTypeImage + `" Target="https://example.com/logo.png" TargetMode="External"/>` +
+			`</Relationships>`,
+		"word/media/image1.png": "png",
+		"word/media/unused.png": "png",
+		contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Override PartName="/word/media/unused.png" ContentType="image/png"/></Types>`,
+	}
+	doc := newTestDocx(t, p("Logo"), parts)
+	got, err := doc.CheckIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := IntegrityReport{
+		OrphanedMedia: []string{"word/media/unused.png"},
+		BrokenRelationships: []BrokenRelationship{
+			{Part: documentXML, ID: "rId2", Type: relTypeImage, Target: "media/missing.png"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	files := renderParts(t, doc.PruneOrphanedMedia())
+	if _, ok := files["word/media/unused.png"]; ok {
+		t.Error("Orphaned media wasn't removed")
+	}
+	if _, ok := files["word/media/image1.png"]; !ok {
+		t.Error("Used media was removed")
+	}
+	if strings.Contains(files[contentTypesXML], "unused.png") {
+		t.Errorf("Content type of removed media is kept: %s", files[contentTypesXML])
+	}
+}
@@ -0,0 +1,221 @@
+package docx
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Invoice fills a table of line items and computes totals of an invoice.
+//
+// A table row with placeholders like [item.description] is repeated for every item,
+// a row with [rate.percent] is repeated for every tax rate. Totals are available
+// as [subtotal], [tax] and [total] placeholders, values of the dictionary take precedence
+type Invoice struct {
+	Items []LineItem
+	// Currency is ISO 4217 code like "EUR"
+	Currency string
+	// TaxRates are rates in percent of tax classes of items, the empty class is the default one
+	TaxRates map[string]float64
+	// PricesIncludeTax means that unit prices are gross prices
+	PricesIncludeTax bool
+	// EmptyText replaces line items when there are none, the row is removed if it's empty
+	EmptyText string
+}
+
+// LineItem is a single position of an invoice
+type LineItem struct {
+	Description string
+	Quantity    float64
+	Unit        string
+	UnitPrice   float64
+	// TaxClass is a key of Invoice.TaxRates (like "reduced")
+	TaxClass string
+}
+
+// Invoice fills a document with line items and totals of an invoice
+func (doc *Docx) Invoice(invoice Invoice) *Docx {
+	doc.invoice = &invoice
+	return doc
+}
+
+// invoiceTotals contains computed values of an invoice
+type invoiceTotals struct {
+	items []Dict
+	rates []Dict
+	dict  Dict
+}
+
+// totals computes amounts of items and taxes,
+// taxes are computed from sums of net amounts with the same rate
+func (invoice *Invoice) totals(locale string) invoiceTotals {
+	decimals := 2
+	if c, ok := currencies[strings.ToUpper(invoice.Currency)]; ok {
+		decimals = c.decimals
+	}
+	round := func(n float64) float64 {
+		scale := math.Pow(10, float64(decimals))
+		return math.Round(n*scale) / scale
+	}
+	money := func(n float64) Money {
+		return Money{Amount: n, Currency: invoice.Currency, Locale: locale}
+	}
+	var t invoiceTotals
+	bases := make(map[float64]float64)
+	gross := make(map[float64]float64)
+	for i, item := range invoice.Items {
+		rate := invoice.TaxRates[item.TaxClass]
+		amount := round(item.Quantity * item.UnitPrice)
+		if invoice.PricesIncludeTax {
+			gross[rate] += amount
+		} else {
+			bases[rate] += amount
+		}
+		t.items = append(t.items, Dict{
+			"position":    i + 1,
+			"description": item.Description,
+			"quantity":    item.Quantity,
+			"unit":        item.Unit,
+			"price":       money(item.UnitPrice),
+			"amount":      money(amount),
+			"tax_rate":    rate,
+		})
+	}
+	for rate, amount := range gross {
+		bases[rate] = round(amount / (1 + rate/100))
+	}
+	rates := make([]float64, 0, len(bases))
+	for rate := range bases {
+		rates = append(rates, rate)
+	}
+	sort.Float64s(rates)
+	var subtotal, tax float64
+	for _, rate := range rates {
+		amount := round(bases[rate] * rate / 100)
+		if invoice.PricesIncludeTax {
+			// the tax is what's left from gross amounts, so totals add up
+			amount = round(gross[rate] - bases[rate])
+		}
+		subtotal += bases[rate]
+		tax += amount
+		t.rates = append(t.rates, Dict{"percent": rate, "base": money(bases[rate]), "tax": money(amount)})
+	}
+	t.dict = Dict{
+		"subtotal":   money(subtotal),
+		"tax":        money(tax),
+		"total":      money(round(subtotal + tax)),
+		"item_count": len(invoice.Items),
+	}
+	return t
+}
+
+// fillInvoice repeats rows of line items and tax rates,
+// totals are variables of the global scope (see globalScope)
+func (ctx *renderContext) fillInvoice() {
+	invoice := ctx.doc.invoice
+	if invoice == nil {
+		return
+	}
+	t := invoice.totals(ctx.doc.locale)
+	for _, tr := range ctx.rowsOf("item") {
+		if len(invoice.Items) == 0 && invoice.EmptyText != "" {
+			ctx.markHeaderRows(tr)
+			emptyRow(tr, invoice.EmptyText)
+			continue
+		}
+		ctx.repeatRow(tr, "item", t.items)
+	}
+	for _, tr := range ctx.rowsOf("rate") {
+		ctx.repeatRow(tr, "rate", t.rates)
+	}
+}
+
+// rowsOf returns table rows with placeholders of record fields, like [item.price]
+func (ctx *renderContext) rowsOf(record string) []*node {
+	var rows []*node
+	for _, tr := range ctx.root.find("w:tr") {
+		for _, p := range tr.find("w:p") {
+			found := false
+			for _, ph := range ctx.doc.placeholders(newParagraph(p).text) {
+				name, _ := parseFilters(ph.name)
+				found = found || strings.HasPrefix(name, record+".")
+			}
+			if found {
+				rows = append(rows, tr)
+				break
+			}
+		}
+	}
+	return rows
+}
+
+// repeatRow replaces a table row with its copies which show values of records
+func (ctx *renderContext) repeatRow(tr *node, record string, records []Dict) {
+	ctx.markHeaderRows(tr)
+	clones := make([]*node, 0, len(records))
+	for _, r := range records {
+		clone := tr.clone()
+		removeIDs(clone)
+		ctx.scopes[clone] = ctx.scopeOf(tr).child(Dict{record: r})
+		clones = append(clones, clone)
+	}
+	tr.replace(clones...)
+}
+
+// tcPrOrder is an order of <w:tcPr> child elements required by the schema
+var tcPrOrder = []string{
+	"w:cnfStyle", "w:tcW", "w:gridSpan", "w:hMerge", "w:vMerge", "w:tcBorders", "w:shd",
+	"w:noWrap", "w:tcMar", "w:textDirection", "w:tcFitText", "w:vAlign", "w:hideMark",
+}
+
+// emptyRow merges cells of a row and puts a text in it
+func emptyRow(tr *node, text string) {
+	cells := tr.elements("w:tc")
+	if len(cells) == 0 {
+		return
+	}
+	span, width := 0, 0
+	for _, tc := range cells {
+		n := 1
+		if tcPr := tc.child("w:tcPr"); tcPr != nil {
+			if w := tcPr.child("w:tcW"); w != nil && w.attrValue("w:type") == "dxa" {
+				dxa, _ := strconv.Atoi(w.attrValue("w:w"))
+				width += dxa
+			}
+			if s := tcPr.child("w:gridSpan"); s != nil {
+				if s, _ := strconv.Atoi(s.attrValue("w:val")); s > 1 {
+					n = s
+				}
+			}
+		}
+		span += n
+	}
+	for _, tc := range cells[1:] {
+		tc.remove()
+	}
+	tc := cells[0]
+	tcPr := tc.child("w:tcPr")
+	if tcPr == nil {
+		tcPr = elem("w:tcPr")
+		tc.insert(0, tcPr)
+	}
+	tcPr.removeChild("w:gridSpan")
+	if span > 1 {
+		tcPr.setChild(elem("w:gridSpan", "w:val", strconv.Itoa(span)), tcPrOrder)
+	}
+	if width > 0 {
+		tcPr.setChild(elem("w:tcW", "w:w", strconv.Itoa(width), "w:type", "dxa"), tcPrOrder)
+	}
+	var rPr *node
+	if r := tc.find("w:r"); len(r) > 0 {
+		rPr = r[0].child("w:rPr")
+	}
+	p := elem("w:p")
+	if paragraphs := tc.elements("w:p"); len(paragraphs) > 0 && paragraphs[0].child("w:pPr") != nil {
+		p.append(paragraphs[0].child("w:pPr").clone())
+	}
+	p.append(newRun(rPr, text))
+	clearCell(tc)
+	tc.append(p)
+}
@@ -0,0 +1,58 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInvoice(t *testing.T) {
+	cell := func(text string) string {
+		return `<w:tc><w:tcPr><w:tcW w:w="2000" w:type="dxa"/></w:tcPr>` + p(text) + `</w:tc>`
+	}
+	body := `<w:tbl>` +
+		`<w:tr>` + cell("Item") + cell("Qty") + cell("Amount") + `</w:tr>` +
+		`<w:tr>` + cell("[item.position]. [item.description]") + cell("[item.quantity] [item.unit]") + cell("[item.amount]") + `</w:tr>` +
+		`<w:tr>` + cell("VAT [rate.percent]%") + cell("[rate.base]") + cell("[rate.tax]") + `</w:tr>` +
+		`</w:tbl>` + p("Subtotal [subtotal], VAT [tax], total [total] for [customer]")
+	invoice := Invoice{
+		Items: []LineItem{
+			{Description: "Book", Quantity: 2, UnitPrice: 10.5, TaxClass: "reduced"},
+			{Description: "Pen", Quantity: 3, Unit: "pcs", UnitPrice: 1.99},
+			{Description: "Paper", Quantity: 1, UnitPrice: 5},
+		},
+		Currency: "EUR",
+		TaxRates: map[string]float64{"": 19, "reduced": 7},
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": "ACME"}).Invoice(invoice))
+	for _, want := range []string{
+		p("1. Book"), p("€21.00"),
+		p("2. Pen"), p("3 pcs"), p("€5.97"),
+		p("3. Paper"), p("€5.00"),
+		p("VAT 7%"), p("€1.47"),
+		p("VAT 19%"), p("€10.97"), p("€2.08"),
+		p("Subtotal €31.97, VAT €3.55, total €35.52 for ACME"),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+	if !strings.HasPrefix(got, `<w:tbl><w:tr><w:trPr><w:tblHeader></w:tblHeader></w:trPr>`) || strings.Count(got, "tblHeader") != 2 {
+		t.Errorf("Only the first row should be a header: %s", got)
+	}
+
+	invoice.PricesIncludeTax = true
+	invoice.Items = invoice.Items[:1]
+	got = renderBody(t, newTestDocx(t, body, nil).Invoice(invoice))
+	if !strings.Contains(got, "Subtotal €19.63, VAT €1.37, total €21.00") {
+		t.Errorf("Wrong gross totals: %s", got)
+	}
+
+	invoice.Items = nil
+	invoice.EmptyText = "No items"
+	got = renderBody(t, newTestDocx(t, body, nil).Invoice(invoice))
+	want := `<w:tr><w:tc><w:tcPr><w:tcW w:w="6000" w:type="dxa"></w:tcW><w:gridSpan w:val="3"></w:gridSpan></w:tcPr>` +
+		p("No items") + `</w:tc></w:tr></w:tbl>`
+	if !strings.Contains(got, want) || strings.Contains(got, "VAT 19") {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
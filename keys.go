@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"sort"
+	"strings"
+)
+
+// IgnoreCase matches placeholders with keys of the dictionary regardless of case,
+// so [CUSTOMER_NAME] is replaced with a value of "customer_name". Keys of nested
+// dictionaries are matched too. A key of the same case is used when there are several
+func (doc *Docx) IgnoreCase(enabled bool) *Docx {
+	doc.ignoreCase = enabled
+	return doc
+}
+
+// NormalizeSpace matches placeholders with keys of the dictionary when they differ in whitespace,
+// spaces surrounding names and words are trimmed and repeated spaces (like tabs and non-breaking
+// spaces) are single spaces, so [ customer   name ] is replaced with a value of "customer name"
+func (doc *Docx) NormalizeSpace(enabled bool) *Docx {
+	doc.normalizeSpace = enabled
+	return doc
+}
+
+// normalizeKey returns a key compared by IgnoreCase and NormalizeSpace without delimiters
+func (doc *Docx) normalizeKey(key string) string {
+	if strings.HasPrefix(key, doc.openingBracket) && strings.HasSuffix(key, doc.closingBracket) &&
+		len(key) >= len(doc.openingBracket)+len(doc.closingBracket) {
+		key = key[len(doc.openingBracket) : len(key)-len(doc.closingBracket)]
+	}
+	if doc.normalizeSpace {
+		key = strings.Join(strings.Fields(key), " ")
+	}
+	if doc.ignoreCase {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// normalizedKeys maps normalized keys of a dictionary to its keys, sorted keys go first
+// so upper case keys of the same name win
+func (doc *Docx) normalizedKeys(dict Dict) map[string]string {
+	keys := make([]string, 0, len(dict))
+	for key := range dict {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	normalized := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if n := doc.normalizeKey(key); normalized[n] == "" {
+			normalized[n] = key
+		}
+	}
+	return normalized
+}
+
+// findNormalized returns a value of a key of a scope's dictionary which matches
+// a name when both are normalized
+func (s *scope) findNormalized(name string) (string, interface{}, bool) {
+	if s.keys == nil {
+		s.keys = s.doc.normalizedKeys(s.dict)
+	}
+	key, ok := s.keys[s.doc.normalizeKey(name)]
+	if !ok {
+		return "", nil, false
+	}
+	return key, s.dict[key], true
+}
+
+// field returns a value of a map with string keys, keys are normalized when they don't match exactly
+func (s *scope) field(v interface{}, key string) (interface{}, bool) {
+	if v, ok := field(v, key); ok || (!s.doc.ignoreCase && !s.doc.normalizeSpace) {
+		return v, ok
+	}
+	dict, ok := toDict(v)
+	if !ok {
+		return nil, false
+	}
+	key, ok = s.doc.normalizedKeys(dict)[s.doc.normalizeKey(key)]
+	return dict[key], ok
+}
@@ -0,0 +1,26 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestKeyMatching(t *testing.T) {
+	body := p("[CUSTOMER_NAME], [ customer_name ], [customer   city|bold], [Order.Total], [[x]]")
+	dict := Dict{"customer_name": "Ann", "customer city": "Oslo", "order": Dict{"total": 10}, "[X]": "y"}
+	tests := []struct {
+		ignoreCase, normalizeSpace bool
+		want                       string
+	}{
+		{false, false, "[CUSTOMER_NAME], [ customer_name ], [customer   city|bold], [Order.Total], [[x]]"},
+		{true, false, "Ann, [ customer_name ], [customer   city|bold], 10, [y]"},
+		{false, true, "[CUSTOMER_NAME], Ann, Oslo, [Order.Total], [[x]]"},
+		{true, true, "Ann, Ann, Oslo, 10, [y]"},
+	}
+	for _, test := range tests {
+		doc := newTestDocx(t, body, nil).ReplaceDict(dict).IgnoreCase(test.ignoreCase).NormalizeSpace(test.normalizeSpace)
+		got := bodyText(t, []byte(renderBody(t, doc)))
+		if got != test.want+"|" {
+			t.Errorf("ignore case %v, normalize space %v:\n got: %s\nwant: %s", test.ignoreCase, test.normalizeSpace, got, test.want)
+		}
+	}
+}
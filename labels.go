@@ -0,0 +1,155 @@
+package docx
+
+// Labels lays out records on sheets of labels. The template is a label table
+// (like the one created by Mailings → Labels) and its first cell is the label
+// which gets values of a record, other label cells are filled in rows.
+// The table is extended with rows as long as there are records, cells of the last sheet
+// which aren't needed stay empty. Cells of other widths are gaps between labels
+func (doc *Docx) Labels(records []Dict) *Docx {
+	doc.labels = records
+	return doc
+}
+
+// Envelopes repeats the whole document for every record, each copy starts on a new page.
+// The template is an envelope (like the one created by Mailings → Envelopes) or a letter
+func (doc *Docx) Envelopes(records []Dict) *Docx {
+	doc.envelopes = records
+	return doc
+}
+
+// layoutLabels fills the first table of the document with labels
+func (ctx *renderContext) layoutLabels() {
+	tables := ctx.root.find("w:tbl")
+	if ctx.doc.labels == nil || len(tables) == 0 {
+		return
+	}
+	tbl := tables[0]
+	rows := tbl.elements("w:tr")
+	if len(rows) == 0 {
+		return
+	}
+	// labels are cells as wide as the first one
+	var columns []int
+	cells := rows[0].elements("w:tc")
+	if len(cells) == 0 {
+		return
+	}
+	template := cells[0]
+	for i, tc := range cells {
+		if cellWidth(tc) == cellWidth(template) {
+			columns = append(columns, i)
+		}
+	}
+	template = template.clone()
+	perSheet := len(rows) * len(columns)
+	sheets := (len(ctx.doc.labels) + perSheet - 1) / perSheet
+	if sheets == 0 {
+		sheets = 1
+	}
+	last := rows[len(rows)-1]
+	for n := len(rows); n < sheets*len(rows); n++ {
+		row := rows[n%len(rows)].clone()
+		removeIDs(row)
+		tbl.insert(last.index()+1, row)
+		last = row
+	}
+	for i, row := range tbl.elements("w:tr") {
+		cells := row.elements("w:tc")
+		for j, column := range columns {
+			k := i*len(columns) + j
+			if column >= len(cells) {
+				continue
+			}
+			tc := cells[column]
+			clearCell(tc)
+			if k < len(ctx.doc.labels) {
+				for _, c := range template.children {
+					if !c.is("w:tcPr") {
+						tc.append(c.clone())
+					}
+				}
+				ctx.scopes[tc] = ctx.global.child(ctx.doc.labels[k])
+			}
+			ensureParagraph(tc)
+		}
+	}
+}
+
+// layoutEnvelopes repeats the content of the document body for all records
+func (ctx *renderContext) layoutEnvelopes() {
+	bodies := ctx.root.find("w:body")
+	if ctx.doc.envelopes == nil || len(bodies) == 0 {
+		return
+	}
+	body := bodies[0]
+	var content []*node
+	var sectPr *node
+	for _, c := range body.children {
+		if c.is("w:sectPr") {
+			sectPr = c
+		} else {
+			content = append(content, c)
+		}
+	}
+	body.children = nil
+	for i, record := range ctx.doc.envelopes {
+		s := ctx.global.child(record)
+		for j, c := range content {
+			c = c.clone()
+			if i > 0 {
+				removeIDs(c)
+			}
+			if i > 0 && j == 0 {
+				// the page setup of the envelope applies to all copies,
+				// so a page break is enough to start a new one
+				if c.is("w:p") {
+					setPageBreakBefore(c)
+				} else {
+					body.append(pageBreak())
+				}
+			}
+			ctx.scopes[c] = s
+			body.append(c)
+		}
+	}
+	if sectPr != nil {
+		body.append(sectPr)
+	}
+}
+
+// setPageBreakBefore makes a paragraph start on a new page
+func setPageBreakBefore(p *node) {
+	pPr := p.child("w:pPr")
+	if pPr == nil {
+		pPr = elem("w:pPr")
+		p.insert(0, pPr)
+	}
+	pPr.setChild(elem("w:pageBreakBefore"), pPrOrder)
+}
+
+// pageBreak returns a paragraph with a page break
+func pageBreak() *node {
+	r := elem("w:r")
+	r.append(elem("w:br", "w:type", "page"))
+	p := elem("w:p")
+	p.append(r)
+	return p
+}
+
+// cellWidth returns preferred width of a table cell, like "2880 dxa"
+func cellWidth(tc *node) string {
+	if tcPr := tc.child("w:tcPr"); tcPr != nil && tcPr.child("w:tcW") != nil {
+		w := tcPr.child("w:tcW")
+		return w.attrValue("w:w") + " " + w.attrValue("w:type")
+	}
+	return ""
+}
+
+// clearCell removes content of a table cell, its properties are kept
+func clearCell(tc *node) {
+	for i := len(tc.children) - 1; i >= 0; i-- {
+		if !tc.children[i].is("w:tcPr") {
+			tc.children[i].remove()
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	label := `<w:tc><w:tcPr><w:tcW w:w="3600" w:type="dxa"/></w:tcPr>` + p("[name]") + p("[city]") + `</w:tc>`
+	gap := `<w:tc><w:tcPr><w:tcW w:w="144" w:type="dxa"/></w:tcPr><w:p/></w:tc>`
+	empty := `<w:tc><w:tcPr><w:tcW w:w="3600" w:type="dxa"/></w:tcPr><w:p/></w:tc>`
+	row := `<w:tr><w:trPr><w:trHeight w:val="1440" w:hRule="exact"/></w:trPr>` + label + gap + empty + `</w:tr>`
+	body := `<w:tbl><w:tblGrid/>` + row + strings.Replace(row, label, empty, 1) + `</w:tbl>`
+	var records []Dict
+	for _, name := range []string{"Ann", "Bob", "Cid", "Dan", "Eve"} {
+		records = append(records, Dict{"name": name, "city": "Paris"})
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).Labels(records))
+	if n := strings.Count(got, "<w:tr>"); n != 4 {
+		t.Errorf("Expected 2 sheets of 2 rows, got %d rows: %s", n, got)
+	}
+	cell := func(name string) string {
+		return `<w:tc><w:tcPr><w:tcW w:w="3600" w:type="dxa"></w:tcW></w:tcPr>` + p(name) + p("Paris") + `</w:tc>`
+	}
+	gapOut := `<w:tc><w:tcPr><w:tcW w:w="144" w:type="dxa"></w:tcW></w:tcPr><w:p></w:p></w:tc>`
+	emptyOut := `<w:tc><w:tcPr><w:tcW w:w="3600" w:type="dxa"></w:tcW></w:tcPr><w:p></w:p></w:tc>`
+	for _, want := range []string{
+		cell("Ann") + gapOut + cell("Bob"),
+		cell("Cid") + gapOut + cell("Dan"),
+		cell("Eve") + gapOut + emptyOut,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestEnvelopes(t *testing.T) {
+	body := p("[name]") + p("[city]") + `<w:sectPr><w:pgSz w:w="12240" w:h="5940"/></w:sectPr>`
+	records := []Dict{{"name": "Ann", "city": "Paris"}, {"name": "Bob", "city": "Rome"}}
+	got := renderBody(t, newTestDocx(t, body, nil).Envelopes(records))
+	want := p("Ann") + p("Paris") +
+		`<w:p><w:pPr><w:pageBreakBefore></w:pageBreakBefore></w:pPr><w:r><w:t>Bob</w:t></w:r></w:p>` + p("Rome") +
+		`<w:sectPr><w:pgSz w:w="12240" w:h="5940"></w:pgSz></w:sectPr>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
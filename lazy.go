@@ -0,0 +1,31 @@
+package docx
+
+// LazyValue is a value of Dict computed when its variable is used for the first time, like
+// the current time or a sequence number from a database. fn gets the name of the variable
+// without brackets and returns a value like in Dict, it's called once per rendered document.
+// Functions func(name string) string and func(name string) interface{} can be used as well
+type LazyValue func(name string) interface{}
+
+// evaluate returns a computed value of a lazy value found in the scope
+func (s *scope) evaluate(name string, v interface{}) interface{} {
+	var fn LazyValue
+	switch f := v.(type) {
+	case LazyValue:
+		fn = f
+	case func(string) interface{}:
+		fn = f
+	case func(string) string:
+		fn = func(name string) interface{} { return f(name) }
+	default:
+		return v
+	}
+	if v, ok := s.computed[name]; ok {
+		return v
+	}
+	v = fn(name)
+	if s.computed == nil {
+		s.computed = make(map[string]interface{})
+	}
+	s.computed[name] = v
+	return v
+}
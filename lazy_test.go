@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLazyValues(t *testing.T) {
+	calls := map[string]int{}
+	count := func(name string) {
+		calls[name]++
+	}
+	body := p("No. [number], [number]") + p("[#if total > 5][total|money:EUR][/if]") +
+		p("[customer.name] [empty]")
+	doc := newTestDocx(t, body, map[string]string{
+		"word/header1.xml": `<w:hdr xmlns:w="w">` + p("[number]") + `</w:hdr>`,
+	}).ReplaceDict(Dict{
+		"number": func(name string) string {
+			count(name)
+			return strconv.Itoa(41 + calls[name])
+		},
+		"total": LazyValue(func(name string) interface{} {
+			count(name)
+			return 10
+		}),
+		"customer": func(name string) interface{} {
+			count(name)
+			return Dict{"name": "Bob"}
+		},
+		"empty": func(name string) string {
+			count(name)
+			return ""
+		},
+	})
+	got := renderParts(t, doc)
+	if want := p("No. 42, 42") + p("€10.00"); !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	if !strings.Contains(got["word/header1.xml"], p("42")) {
+		t.Errorf("Unexpected header: %s", got["word/header1.xml"])
+	}
+	for name, n := range map[string]int{"number": 1, "total": 1, "customer": 1, "empty": 1} {
+		if calls[name] != n {
+			t.Errorf("%s computed %d times, want %d", name, calls[name], n)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package docx
+
+import (
+	"strings"
+)
+
+// paragraphBreak temporarily marks a position where a paragraph is split
+const paragraphBreak = "docx:paragraphBreak"
+
+// LineBreaks turns new lines of replaced values into line breaks, an empty line ("\n\n")
+// starts a new paragraph with the same formatting, so multi-line addresses keep their lines
+func (doc *Docx) LineBreaks(enabled bool) *Docx {
+	doc.lineBreaks = enabled
+	return doc
+}
+
+// breakLines replaces new lines in text of a paragraph with line breaks and splits
+// the paragraph at empty lines. Paragraphs are split only at runs which are its children,
+// empty lines in hyperlinks or other containers become two line breaks
+func breakLines(p *node) {
+	split := false
+	for _, t := range p.find("w:t") {
+		if t.ancestor("w:p") != p {
+			continue
+		}
+		text := strings.ReplaceAll(t.text(), "\r\n", "\n")
+		if !strings.Contains(text, "\n") {
+			continue
+		}
+		direct := t.parent.parent == p
+		var nodes []*node
+		for i, block := range strings.Split(text, "\n\n") {
+			if i > 0 {
+				if direct {
+					nodes = append(nodes, elem(paragraphBreak))
+					split = true
+				} else {
+					nodes = append(nodes, elem("w:br"), elem("w:br"))
+				}
+			}
+			for j, line := range strings.Split(block, "\n") {
+				if j > 0 {
+					nodes = append(nodes, elem("w:br"))
+				}
+				if line != "" {
+					lineText := elem("w:t")
+					setRunText(lineText, line)
+					nodes = append(nodes, lineText)
+				}
+			}
+		}
+		t.replace(nodes...)
+	}
+	for split {
+		split = false
+		for _, r := range p.elements("w:r") {
+			for _, c := range r.children {
+				if c.is(paragraphBreak) {
+					p = splitParagraph(p, r, c)
+					split = true
+					break
+				}
+			}
+			if split {
+				break
+			}
+		}
+	}
+}
+
+// splitParagraph moves content following a break in a run into a new paragraph with the same
+// properties and returns it, the section break goes to the new paragraph
+func splitParagraph(p, r, at *node) *node {
+	next := elem("w:p")
+	if pPr := p.child("w:pPr"); pPr != nil {
+		next.append(pPr.clone())
+		pPr.removeChild("w:sectPr")
+	}
+	rest := elem("w:r")
+	if rPr := r.child("w:rPr"); rPr != nil {
+		rest.append(rPr.clone())
+	}
+	i := at.index()
+	moved := append([]*node(nil), r.children[i+1:]...)
+	at.remove()
+	for _, c := range moved {
+		c.remove()
+		rest.append(c)
+	}
+	if len(moved) > 0 {
+		next.append(rest)
+	}
+	for _, c := range append([]*node(nil), p.children[r.index()+1:]...) {
+		c.remove()
+		next.append(c)
+	}
+	if isEmptyRun(r) {
+		r.remove()
+	}
+	p.parent.insert(p.index()+1, next)
+	return next
+}
+
+// isEmptyRun checks if a run has nothing else than formatting
+func isEmptyRun(r *node) bool {
+	for _, c := range r.children {
+		if c.tag != "" && !c.is("w:rPr") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineBreaks(t *testing.T) {
+	pPr := `<w:pPr><w:jc w:val="right"/></w:pPr>`
+	body := `<w:p>` + pPr + `<w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">To: [address]</w:t></w:r><w:r><w:t>!</w:t></w:r></w:p>` +
+		p("[note|italic]")
+	dict := Dict{"address": "ACME Inc.\n1 Main St.\r\nSpringfield\n\nAttn: Bob", "note": "a\nb"}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict).LineBreaks(true))
+	want := `<w:p>` + `<w:pPr><w:jc w:val="right"></w:jc></w:pPr>` +
+		`<w:r><w:rPr><w:b></w:b></w:rPr><w:t>To: ACME Inc.</w:t><w:br></w:br><w:t>1 Main St.</w:t>` +
+		`<w:br></w:br><w:t>Springfield</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:jc w:val="right"></w:jc></w:pPr><w:r><w:rPr><w:b></w:b></w:rPr><w:t>Attn: Bob</w:t></w:r><w:r><w:t>!</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:rPr><w:i></w:i><w:iCs></w:iCs></w:rPr><w:t>a</w:t><w:br></w:br><w:t>b</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+
+	got = renderBody(t, newTestDocx(t, p("[address]"), nil).ReplaceDict(dict))
+	if strings.Contains(got, "<w:br") || strings.Count(got, "<w:p>") != 1 {
+		t.Errorf("Expected new lines without LineBreaks: %s", got)
+	}
+}
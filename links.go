@@ -0,0 +1,304 @@
+package docx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Hyperlink is a link of a document
+type Hyperlink struct {
+	Text string `json:"text"`
+	// Target is an address like "https://example.com", links to bookmarks of the document start with "#"
+	Target string `json:"target"`
+	// Part is the name of the part, like "word/document.xml" or "word/footer1.xml"
+	Part string `json:"part"`
+	// Paragraph is the position of the paragraph with the link among paragraphs of the part starting at 0
+	Paragraph int `json:"paragraph"`
+}
+
+// Hyperlinks returns hyperlinks and HYPERLINK fields of all parts of the template,
+// like the document body, headers, footers or footnotes
+func (doc *Docx) Hyperlinks() ([]Hyperlink, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	var links []Hyperlink
+	for _, name := range p.names {
+		if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") || strings.HasPrefix(name, "word/glossary/") {
+			continue
+		}
+		data, err := p.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Contains(data, []byte("<w:hyperlink")) && !bytes.Contains(data, []byte("HYPERLINK")) {
+			continue
+		}
+		root, err := scan(data)
+		if err != nil {
+			return nil, err
+		}
+		rels, err := p.relationships(name)
+		if err != nil {
+			return nil, err
+		}
+		c := &linkCollector{part: name, rels: rels, paragraph: -1}
+		c.visit(root)
+		links = append(links, c.links...)
+	}
+	return links, nil
+}
+
+// linkCollector finds hyperlinks of a part in document order
+type linkCollector struct {
+	part      string
+	rels      *relationships
+	paragraph int
+	links     []Hyperlink
+	// fields are open complex fields
+	fields []*linkField
+}
+
+// linkField is a complex field, text of its result is collected if it's a HYPERLINK field
+type linkField struct {
+	instr     strings.Builder
+	separated bool
+	text      strings.Builder
+	paragraph int
+}
+
+func (c *linkCollector) visit(n *node) {
+	for _, child := range n.children {
+		switch child.tag {
+		case "w:p":
+			c.paragraph++
+			c.visit(child)
+		case "w:hyperlink":
+			target := child.attrValue("w:anchor")
+			if target != "" {
+				target = "#" + target
+			}
+			if rel, ok := c.rels.target(child.attrValue("r:id")); ok {
+				target = rel.Target + target
+			}
+			c.links = append(c.links, Hyperlink{Text: plainText(child), Target: target, Part: c.part, Paragraph: c.paragraph})
+			c.visit(child)
+		case "w:fldSimple":
+			if target, ok := hyperlinkTarget(child.attrValue("w:instr")); ok {
+				c.links = append(c.links, Hyperlink{Text: plainText(child), Target: target, Part: c.part, Paragraph: c.paragraph})
+			}
+			c.visit(child)
+		case "w:fldChar":
+			c.fieldChar(child.attrValue("w:fldCharType"))
+		case "w:instrText":
+			if len(c.fields) > 0 && !c.fields[len(c.fields)-1].separated {
+				c.fields[len(c.fields)-1].instr.WriteString(child.text())
+			}
+		case "w:t", "w:tab":
+			text := child.text()
+			if child.is("w:tab") {
+				text = "\t"
+			}
+			for _, f := range c.fields {
+				if f.separated {
+					f.text.WriteString(text)
+				}
+			}
+		default:
+			c.visit(child)
+		}
+	}
+}
+
+// fieldChar handles a start, a separator or an end of a complex field
+func (c *linkCollector) fieldChar(typ string) {
+	switch typ {
+	case "begin":
+		c.fields = append(c.fields, &linkField{paragraph: c.paragraph})
+	case "separate":
+		if len(c.fields) > 0 {
+			c.fields[len(c.fields)-1].separated = true
+		}
+	case "end":
+		if len(c.fields) == 0 {
+			return
+		}
+		f := c.fields[len(c.fields)-1]
+		c.fields = c.fields[:len(c.fields)-1]
+		if target, ok := hyperlinkTarget(f.instr.String()); ok {
+			c.links = append(c.links, Hyperlink{Text: f.text.String(), Target: target, Part: c.part, Paragraph: f.paragraph})
+		}
+	}
+}
+
+// plainText returns text of runs of an element
+func plainText(n *node) string {
+	var sb strings.Builder
+	n.walk(func(c *node) bool {
+		switch c.tag {
+		case "w:t":
+			sb.WriteString(c.text())
+		case "w:tab":
+			sb.WriteByte('\t')
+		case "w:instrText", "w:delText":
+			return false
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// hyperlinkTarget returns the target of a field instruction like HYPERLINK "https://example.com" \l "top"
+func hyperlinkTarget(instr string) (string, bool) {
+	args := fieldArguments(instr)
+	if len(args) == 0 || !strings.EqualFold(args[0], "HYPERLINK") {
+		return "", false
+	}
+	address, anchor := "", ""
+	for i := 1; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case `\l`:
+			if i+1 < len(args) {
+				anchor = "#" + args[i+1]
+				i++
+			}
+		case `\o`, `\t`:
+			// tooltips and target frames have arguments
+			i++
+		default:
+			if address == "" && !strings.HasPrefix(args[i], `\`) {
+				address = args[i]
+			}
+		}
+	}
+	return address + anchor, true
+}
+
+// fieldArguments splits a field instruction to arguments, quoted arguments can contain spaces
+func fieldArguments(instr string) []string {
+	var args []string
+	var sb strings.Builder
+	quoted, started := false, false
+	for _, r := range instr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if started {
+				args = append(args, sb.String())
+				sb.Reset()
+				started = false
+			}
+		default:
+			sb.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		args = append(args, sb.String())
+	}
+	return args
+}
+
+// BrokenLink is a hyperlink whose target can't be reached
+type BrokenLink struct {
+	Hyperlink
+	// Status is the HTTP status code, it's 0 if the request failed
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// linkCheckers is the number of concurrent requests of CheckLinks
+const linkCheckers = 8
+
+// CheckLinks sends HEAD requests (or GET requests if servers don't allow HEAD) to http and https
+// targets of hyperlinks and returns links which failed or got an error status, every address is
+// requested once. A client with a 10 second timeout is used if client is nil
+func CheckLinks(ctx context.Context, client *http.Client, links []Hyperlink) []BrokenLink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	var targets []string
+	seen := make(map[string]bool)
+	for _, link := range links {
+		if target := linkAddress(link.Target); !seen[target] && (strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")) {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	results := make(map[string]BrokenLink, len(targets))
+	var mu sync.Mutex
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < linkCheckers && i < len(targets); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range queue {
+				status, err := checkLink(ctx, client, target)
+				if err == nil && status < 400 {
+					continue
+				}
+				result := BrokenLink{Status: status}
+				if err != nil {
+					result.Error = err.Error()
+				}
+				mu.Lock()
+				results[target] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, target := range targets {
+		queue <- target
+	}
+	close(queue)
+	wg.Wait()
+	var broken []BrokenLink
+	for _, link := range links {
+		if result, ok := results[linkAddress(link.Target)]; ok {
+			result.Hyperlink = link
+			broken = append(broken, result)
+		}
+	}
+	return broken
+}
+
+// linkAddress returns a target without its fragment, which isn't sent to servers
+func linkAddress(target string) string {
+	if i := strings.IndexByte(target, '#'); i != -1 {
+		return target[:i]
+	}
+	return target
+}
+
+// checkLink returns the status code of an address
+func checkLink(ctx context.Context, client *http.Client, target string) (int, error) {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, target, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,68 @@
+package docx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHyperlinkList(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">See </w:t></w:r><w:hyperlink r:id="rId1"><w:r><w:t>our site</w:t></w:r></w:hyperlink></w:p>` +
+		`<w:p><w:hyperlink w:anchor="terms"><w:r><w:t>Terms</w:t></w:r></w:hyperlink></w:p>` +
+		`<w:p><w:fldSimple w:instr=" HYPERLINK &quot;https://example.com/a b&quot; \o &quot;Tip&quot; "><w:r><w:t>Simple</w:t></w:r></w:fldSimple>` +
+		`<w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> HYPERLINK "https://example.com/doc" </w:instrText></w:r>` +
+		`<w:r><w:instrText xml:space="preserve">\l "intro"</w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+		`<w:r><w:t>Complex</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com" TargetMode="External"/>` +
+			`</Relationships>`,
+		"word/footer1.xml": `<w:ftr xmlns:w="w"><w:p></w:p><w:p><w:hyperlink w:anchor="top"><w:r><w:t>Top</w:t></w:r></w:hyperlink></w:p></w:ftr>`,
+	}
+	got, err := newTestDocx(t, body, parts).Hyperlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Hyperlink{
+		{Text: "our site", Target: "https://example.com", Part: documentXML, Paragraph: 0},
+		{Text: "Terms", Target: "#terms", Part: documentXML, Paragraph: 1},
+		{Text: "Simple", Target: "https://example.com/a b", Part: documentXML, Paragraph: 2},
+		{Text: "Complex", Target: "https://example.com/doc#intro", Part: documentXML, Paragraph: 2},
+		{Text: "Top", Target: "#top", Part: "word/footer1.xml", Paragraph: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/get":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}
+	}))
+	defer server.Close()
+	links := []Hyperlink{
+		{Text: "ok", Target: server.URL + "/ok#top"},
+		{Text: "missing", Target: server.URL + "/missing"},
+		{Text: "get", Target: server.URL + "/get"},
+		{Text: "bookmark", Target: "#missing"},
+		{Text: "again", Target: server.URL + "/missing#part"},
+	}
+	got := CheckLinks(context.Background(), server.Client(), links)
+	want := []BrokenLink{
+		{Hyperlink: links[1], Status: http.StatusNotFound},
+		{Hyperlink: links[4], Status: http.StatusNotFound},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Checks of templates reported by Lint
+const (
+	CheckMalformed = "malformed"
+	CheckDuplicate = "duplicate"
+	CheckNesting   = "nesting"
+	CheckLocation  = "location"
+)
+
+// Problem is a mistake in a template found by Lint
+type Problem struct {
+	// Part is a name of the part of the archive, like "word/document.xml"
+	Part string `json:"part"`
+	// Check is one of CheckMalformed, CheckDuplicate, CheckNesting and CheckLocation
+	Check       string `json:"check"`
+	Placeholder string `json:"placeholder,omitempty"`
+	Message     string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Part + ": " + p.Check + ": " + p.Message
+}
+
+// Lint checks the template for mistakes which make placeholders stay in generated documents
+// or fail rendering: malformed placeholders (unclosed brackets, unknown filters or blocks,
+// invalid conditions), duplicate names of bookmarks and IDs of content controls, wrongly nested
+// blocks and placeholders in locations where they aren't replaced, like complex field
+// instructions or parts which aren't rendered. Problems are sorted by parts, document.xml goes first
+func (doc *Docx) Lint() ([]Problem, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	if !p.has(documentXML) {
+		return nil, &partError{documentXML}
+	}
+	l := &linter{doc: doc}
+	root, err := p.parsePart(documentXML)
+	if err != nil {
+		return nil, err
+	}
+	// directives of comments become markers, the comments are removed from the comments part
+	ctx := newRenderContext(doc, p, documentXML, root)
+	if err := ctx.commentDirectives(); err != nil {
+		return nil, err
+	}
+	doc.mustacheTags(root)
+	l.lintPart(documentXML, root)
+	rendered := make(map[string]bool)
+	for _, name := range doc.partsToRender(p) {
+		rendered[name] = true
+	}
+	for _, name := range p.names {
+		// building blocks are rendered where they are inserted
+		if name == documentXML || !strings.HasSuffix(name, ".xml") ||
+			strings.HasPrefix(name, "customXml/") || strings.HasPrefix(name, "word/glossary/") {
+			continue
+		}
+		data, err := p.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(string(data), doc.openingBracket) {
+			continue
+		}
+		root, err := scan(data)
+		if err != nil {
+			return nil, err
+		}
+		doc.mustacheTags(root)
+		if rendered[name] {
+			l.lintPart(name, root)
+		} else {
+			l.unrenderedPart(name, root)
+		}
+	}
+	return l.problems, nil
+}
+
+// linter collects problems of a template
+type linter struct {
+	doc      *Docx
+	problems []Problem
+}
+
+func (l *linter) report(part, check, placeholder, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{
+		Part:        part,
+		Check:       check,
+		Placeholder: placeholder,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// bracketed returns text of a placeholder with brackets
+func (l *linter) bracketed(name string) string {
+	return l.doc.openingBracket + name + l.doc.closingBracket
+}
+
+// lintPart checks a rendered part
+func (l *linter) lintPart(part string, root *node) {
+	opening := l.doc.openingBracket
+	var open []marker
+	for _, par := range root.find("w:p") {
+		para := newParagraph(par)
+		found := l.doc.placeholders(para.text)
+		last := 0
+		for _, ph := range found {
+			text := l.bracketed(ph.name)
+			if strings.Contains(para.text[last:ph.start], opening) {
+				l.report(part, CheckMalformed, "", "Unclosed placeholder in %q", para.text[last:ph.start])
+			}
+			last = ph.end
+			if para.crossesField(ph.start, ph.end) {
+				l.report(part, CheckLocation, text, "Placeholder %s crosses a field and is not replaced", text)
+				continue
+			}
+			if m, ok := parseMarker(ph); ok {
+				m.para = par
+				open = l.nest(part, open, m)
+				continue
+			}
+			l.placeholder(part, ph)
+		}
+		if strings.Contains(para.text[last:], opening) {
+			l.report(part, CheckMalformed, "", "Unclosed placeholder in %q", para.text[last:])
+		}
+	}
+	for _, m := range open {
+		text := l.bracketed(m.ph.name)
+		l.report(part, CheckNesting, text, "Unclosed block %s", text)
+	}
+	l.instructions(part, root)
+	l.duplicates(part, root)
+}
+
+// nest checks a block marker against the open blocks and returns blocks open after it
+func (l *linter) nest(part string, open []marker, m marker) []marker {
+	text := l.bracketed(m.ph.name)
+	if m.alternative {
+		if len(open) == 0 {
+			l.report(part, CheckNesting, text, "Unexpected %s outside of a block", text)
+		}
+		return open
+	}
+	if !m.end && m.kind == "each" {
+		if _, _, err := parseEachOptions(m.arg); err != nil {
+			l.report(part, CheckMalformed, text, "Invalid loop %s: %v", text, err)
+		}
+		return append(open, m)
+	}
+	if !m.end {
+		if _, err := parseExpr(m.arg); err != nil {
+			l.report(part, CheckMalformed, text, "Invalid condition of %s: %v", text, err)
+		}
+		return append(open, m)
+	}
+	if len(open) == 0 {
+		l.report(part, CheckNesting, text, "Unexpected end of block %s", text)
+		return open
+	}
+	start := open[len(open)-1]
+	if start.kind != m.kind {
+		l.report(part, CheckNesting, text, "Block %s is closed by %s", l.bracketed(start.ph.name), text)
+	}
+	return open[:len(open)-1]
+}
+
+// placeholder checks a variable with filters
+func (l *linter) placeholder(part string, ph placeholder) {
+	text := l.bracketed(ph.name)
+	name, filters := parseFilters(ph.name)
+	if strings.TrimSpace(name) == "" {
+		l.report(part, CheckMalformed, text, "Empty placeholder %s", text)
+		return
+	}
+	if block := strings.TrimSpace(strings.Trim(name, "-")); strings.HasPrefix(block, "#") || strings.HasPrefix(block, "/") {
+		kind := strings.Fields(block[1:] + " ")[0]
+		// partials are included before blocks are expanded
+		if kind != "include" || !strings.HasPrefix(block, "#") {
+			l.report(part, CheckMalformed, text, "Unknown block %s in %s", kind, text)
+		}
+		return
+	}
+	for _, f := range filters {
+		if _, ok := l.doc.filters[f.name]; ok {
+			continue
+		}
+		if _, ok := builtinFilters[f.name]; !ok {
+			l.report(part, CheckMalformed, text, "Unknown filter %s in %s", f.name, text)
+		}
+	}
+}
+
+// instructions reports placeholders in instructions of fields, which aren't replaced
+func (l *linter) instructions(part string, root *node) {
+	for _, instr := range root.find("w:instrText") {
+		for _, ph := range l.doc.placeholders(instr.text()) {
+			text := l.bracketed(ph.name)
+			l.report(part, CheckLocation, text, "Placeholder %s in a field instruction is not replaced", text)
+		}
+	}
+	if l.doc.simpleFieldInstructions {
+		return
+	}
+	for _, field := range root.find("w:fldSimple") {
+		for _, ph := range l.doc.placeholders(field.attrValue("w:instr")) {
+			text := l.bracketed(ph.name)
+			l.report(part, CheckLocation, text, "Placeholder %s in a field instruction is replaced only with SimpleFieldInstructions", text)
+		}
+	}
+}
+
+// duplicates reports bookmarks with the same name and content controls with the same ID,
+// Word reports such documents as corrupted
+func (l *linter) duplicates(part string, root *node) {
+	bookmarks := make(map[string]int)
+	for _, b := range root.find("w:bookmarkStart") {
+		if name := b.attrValue("w:name"); name != "" && name != "_GoBack" {
+			bookmarks[name]++
+		}
+	}
+	ids := make(map[string]int)
+	for _, sdt := range root.find("w:sdt") {
+		if id := sdtProperty(sdt, "w:id"); id != nil {
+			ids[id.attrValue("w:val")]++
+		}
+	}
+	for _, name := range sortedCounts(bookmarks) {
+		l.report(part, CheckDuplicate, "", "Bookmark %s is used %d times", name, bookmarks[name])
+	}
+	for _, id := range sortedCounts(ids) {
+		l.report(part, CheckDuplicate, "", "ID %s of content controls is used %d times", id, ids[id])
+	}
+}
+
+// sortedCounts returns sorted keys which occur more than once
+func sortedCounts(counts map[string]int) []string {
+	var keys []string
+	for k, n := range counts {
+		if n > 1 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unrenderedPart reports placeholders in a part which isn't rendered
+func (l *linter) unrenderedPart(part string, root *node) {
+	var texts []string
+	if paragraphs := root.find("w:p"); len(paragraphs) > 0 {
+		for _, par := range paragraphs {
+			texts = append(texts, newParagraph(par).text)
+		}
+	} else {
+		root.walk(func(n *node) bool {
+			if n.tag == "" && n.data != nil {
+				texts = append(texts, n.text())
+			}
+			return true
+		})
+	}
+	for _, text := range texts {
+		for _, ph := range l.doc.placeholders(text) {
+			text := l.bracketed(ph.name)
+			l.report(part, CheckLocation, text, "Placeholder %s is not replaced in %s", text, part)
+		}
+	}
+}
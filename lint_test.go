@@ -0,0 +1,48 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	body := p("[name] and [total|money:EUR]") +
+		p("Dear [customer") + p("[a [b]") + p("[ ]") +
+		p("[x|shout] [#loop items] [/loop]") +
+		p("[#if total >]") + p("[/if]") +
+		p("[#if a]") + p("[#include signature]") + p("[#else]") +
+		p("[/if]") + p("[/if]") + p("[#else]") +
+		`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText> HYPERLINK "[url]" </w:instrText></w:r>` +
+		`<w:r><w:fldChar w:fldCharType="separate"/></w:r><w:r><w:t>link</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>` +
+		`<w:p><w:bookmarkStart w:id="1" w:name="total"/><w:bookmarkEnd w:id="1"/>` +
+		`<w:bookmarkStart w:id="2" w:name="total"/><w:bookmarkEnd w:id="2"/></w:p>`
+	parts := map[string]string{
+		"word/header1.xml": `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:p><w:r><w:t>[title|shout]</w:t></w:r></w:p></w:hdr>`,
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" ` +
+			`xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>[title]</dc:title></cp:coreProperties>`,
+	}
+	got, err := newTestDocx(t, body, parts).Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{documentXML, CheckMalformed, "", `Unclosed placeholder in "Dear [customer"`},
+		{documentXML, CheckMalformed, "", `Unclosed placeholder in "[a "`},
+		{documentXML, CheckMalformed, "[ ]", "Empty placeholder [ ]"},
+		{documentXML, CheckMalformed, "[x|shout]", "Unknown filter shout in [x|shout]"},
+		{documentXML, CheckMalformed, "[#loop items]", "Unknown block loop in [#loop items]"},
+		{documentXML, CheckMalformed, "[/loop]", "Unknown block loop in [/loop]"},
+		{documentXML, CheckMalformed, "[#if total >]", "Invalid condition of [#if total >]: Unexpected end of expression"},
+		{documentXML, CheckNesting, "[/if]", "Unexpected end of block [/if]"},
+		{documentXML, CheckNesting, "[#else]", "Unexpected [#else] outside of a block"},
+		{documentXML, CheckLocation, "[url]", "Placeholder [url] in a field instruction is not replaced"},
+		{documentXML, CheckDuplicate, "", "Bookmark total is used 2 times"},
+		{"docProps/core.xml", CheckLocation, "[title]", "Placeholder [title] is not replaced in docProps/core.xml"},
+		// headers are rendered
+		{"word/header1.xml", CheckMalformed, "[title|shout]", "Unknown filter shout in [title|shout]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package docx
+
+import (
+	"fmt"
+)
+
+// loopScopes returns scopes of records of [#each items]...[/each], placeholders inside the block
+// use fields of the record and then other values. Records can be sorted like repeating sections:
+// [#each items|sort:-price]
+func (ctx *renderContext) loopScopes(start marker) ([]*scope, error) {
+	name, options, err := parseEachOptions(start.arg)
+	if err != nil {
+		return nil, err
+	}
+	s := ctx.scopeOf(start.para)
+	var records []Dict
+	if v, ok := s.lookup(name); ok {
+		if records, ok = toRecords(v); !ok {
+			return nil, fmt.Errorf("Invalid value of block %s: %T is not a list of Dicts", start.ph.name, v)
+		}
+	}
+	var scopes []*scope
+	for _, record := range options.groups(records)[0].records {
+		scopes = append(scopes, s.child(record))
+	}
+	return scopes, nil
+}
+
+// parseEachOptions splits an argument of [#each] to the name of a collection and its options,
+// records can only be sorted: a block has no items for headers and footers of groups
+func parseEachOptions(arg string) (string, loopOptions, error) {
+	name, options, err := parseLoopOptions(arg)
+	if err == nil && options.group != "" {
+		err = fmt.Errorf("Records of %s can't be grouped by %s, only repeating sections have headers and footers of groups", name, options.group)
+	}
+	return name, options, err
+}
+
+// repeatBlock repeats content of a block once per scope, a block without scopes is removed.
+// Blocks spanning several paragraphs repeat paragraphs (or table rows) between the markers,
+// a block inside a paragraph repeats its text
+func (ctx *renderContext) repeatBlock(start, end marker, scopes []*scope) error {
+	if len(scopes) == 0 {
+		ctx.keepBlock(start, end, false)
+		return nil
+	}
+	if start.para == end.para {
+		return ctx.repeatText(start, end, scopes)
+	}
+	// text before and after the block isn't repeated
+	if !endsParagraph(end) {
+		splitAt(end.para, end.ph.end)
+	}
+	if !leadsParagraph(start) {
+		next := splitAt(start.para, start.ph.start)
+		start.ph.end -= start.ph.start
+		start.ph.start = 0
+		start.para = next
+	}
+	first, last := blockRange(start.para, end.para)
+	parent, i := first.parent, first.index()
+	nodes := append([]*node(nil), parent.children[i:last.index()+1]...)
+	// copies are put in place of the block, whatever is trimmed around markers
+	anchor := boundary()
+	parent.insert(i, anchor)
+	removeMarker(end)
+	removeMarker(start)
+	var clones []*node
+	for _, scope := range scopes {
+		for _, n := range nodes {
+			if n.parent != parent {
+				// a paragraph of a marker
+				continue
+			}
+			clone := n.clone()
+			removeIDs(clone)
+			ctx.scopes[clone] = scope
+			clones = append(clones, clone)
+		}
+	}
+	for _, n := range nodes {
+		if n.parent == parent {
+			n.remove()
+		}
+	}
+	anchor.replace(clones...)
+	ensureParagraph(parent)
+	return nil
+}
+
+// repeatText repeats runs between markers in a paragraph, copies are rendered with their records
+func (ctx *renderContext) repeatText(start, end marker, scopes []*scope) error {
+	para := newParagraph(start.para)
+	para.erase(end.ph.start, end.ph.end)
+	after := boundary()
+	para.insertRuns(end.ph.start, []*node{after})
+	para = newParagraph(start.para)
+	para.erase(start.ph.start, start.ph.end)
+	before := boundary()
+	para.insertRuns(start.ph.start, []*node{before})
+	parent := before.parent
+	if parent == nil || after.parent != parent {
+		return fmt.Errorf("Block %s has to end in the same element", start.ph.name)
+	}
+	var content []*node
+	if i, j := before.index(), after.index(); i < j {
+		content = append(content, parent.children[i+1:j]...)
+	}
+	for _, scope := range scopes {
+		body := elem("w:body")
+		copy := elem("w:p")
+		for _, n := range content {
+			clone := n.clone()
+			removeIDs(clone)
+			copy.append(clone)
+		}
+		body.append(copy)
+		ctx.scopes[body] = scope
+		if err := ctx.expandBlocksOf(body); err != nil {
+			return err
+		}
+		if err := ctx.replaceParagraph(copy); err != nil {
+			return err
+		}
+		children := copy.children
+		copy.children = nil
+		parent.insert(after.index(), children...)
+	}
+	for _, n := range content {
+		n.remove()
+	}
+	before.remove()
+	after.remove()
+	return nil
+}
+
+// boundary is an empty run marking a position in a paragraph
+func boundary() *node {
+	return elem("w:r")
+}
+
+// splitAt splits a paragraph at a position of its text and returns the new paragraph
+// with the rest of the text
+func splitAt(p *node, pos int) *node {
+	r := boundary()
+	br := elem(paragraphBreak)
+	r.append(br)
+	newParagraph(p).insertRuns(pos, []*node{r})
+	return splitParagraph(p, r, br)
+}
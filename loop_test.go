@@ -0,0 +1,71 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoopBlocks(t *testing.T) {
+	items := []Dict{
+		{"name": "Pen", "price": 2, "tags": []Dict{{"tag": "blue"}, {"tag": "cheap"}}},
+		{"name": "Book", "price": 12},
+	}
+	row := func(cells ...string) string {
+		s := `<w:tr>`
+		for _, cell := range cells {
+			s += `<w:tc>` + p(cell) + `</w:tc>`
+		}
+		return s + `</w:tr>`
+	}
+	tests := []struct {
+		body, want string
+	}{
+		{
+			p("Order [order]:") + p("[#each items]") + p("[name] costs [price] [currency].") + p("[/each]") + p("end"),
+			p("Order 7:") + p("Pen costs 2 EUR.") + p("Book costs 12 EUR.") + p("end"),
+		},
+		{
+			p("Items: [#each items|sort:price][name], [/each]end"),
+			`<w:p><w:r><w:t xml:space="preserve">Items: </w:t></w:r><w:r><w:t xml:space="preserve">Pen, </w:t></w:r>` +
+				`<w:r><w:t xml:space="preserve">Book, </w:t></w:r><w:r><w:t>end</w:t></w:r></w:p>`,
+		},
+		{
+			p("Items: [#each items|sort:-price]") + p("[name][#if price > 10] (expensive)[/if]") + p("[/each] end"),
+			`<w:p><w:r><w:t xml:space="preserve">Items: </w:t></w:r></w:p>` + p("Book (expensive)") + p("Pen") +
+				`<w:p><w:r><w:t xml:space="preserve"> end</w:t></w:r></w:p>`,
+		},
+		{
+			p("[#each items]") + p("[name]: [#each tags][tag] [/each]") + p("[/each]"),
+			`<w:p><w:r><w:t xml:space="preserve">Pen: </w:t></w:r><w:r><w:t xml:space="preserve">blue </w:t></w:r>` +
+				`<w:r><w:t xml:space="preserve">cheap </w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve">Book: </w:t></w:r></w:p>`,
+		},
+		{
+			`<w:tbl>` + row("Item", "Price") + row("[#each items][name]", "[price][/each]") + `</w:tbl>`,
+			`<w:tbl>` + row("Item", "Price") + row("Pen", "2") + row("Book", "12") + `</w:tbl>`,
+		},
+		{
+			p("[#each missing]") + p("[name]") + p("[/each]") + p("end"),
+			p("end"),
+		},
+	}
+	for _, test := range tests {
+		doc := newTestDocx(t, test.body, nil).ReplaceDict(Dict{"order": 7, "currency": "EUR", "items": items})
+		if got := renderBody(t, doc); got != test.want {
+			t.Errorf("%s:\n got: %s\nwant: %s", test.body, got, test.want)
+		}
+	}
+
+	doc := newTestDocx(t, p("[#each items][name][/each]"), nil).ReplaceDict(Dict{"items": "pen"})
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil || !strings.Contains(err.Error(), "not a list of Dicts") {
+		t.Errorf("Expected error of a value which isn't a list, got %v", err)
+	}
+	// groups need headers and footers, which only repeating sections have
+	doc = newTestDocx(t, p("[#each items|group:price][name][/each]"), nil).ReplaceDict(Dict{"items": items})
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil || !strings.Contains(err.Error(), "can't be grouped by price") {
+		t.Errorf("Expected error of grouped records, got %v", err)
+	}
+	if problems, err := newTestDocx(t, p("[#each items|group:price][name][/each]"), nil).Lint(); err != nil || len(problems) != 1 {
+		t.Errorf("Expected a problem of grouped records, got %v, %v", problems, err)
+	}
+}
@@ -0,0 +1,126 @@
+package docx
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// mediaRelTypes are types of relationships referring to images, audio and video
+var mediaRelTypes = map[string]bool{
+	relTypeImage: true,
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships/audio": true,
+	"http://schemas.openxmlformats.org/officeDocument/2006/relationships/video": true,
+	"http://schemas.microsoft.com/office/2007/relationships/media":              true,
+}
+
+// Media is an image or another media file of a document
+type Media struct {
+	// Name is the name of the part, like "word/media/image1.png"
+	Name        string
+	ContentType string
+	Data        []byte
+	// References are relationships of parts referring to the media, they are empty for unused media
+	References []MediaReference
+}
+
+// MediaReference is a relationship referring to a media file
+type MediaReference struct {
+	// Part is the referring part, like "word/document.xml" or "word/header1.xml"
+	Part string
+	// ID is the ID of the relationship used in the part, like "rId5"
+	ID   string
+	Type string
+}
+
+// ForEachMedia calls fn for all images and other media of the template (or parts set by SetPart)
+// in order of their names, it stops on the first error returned by fn
+func (doc *Docx) ForEachMedia(fn func(m Media) error) error {
+	if doc.err != nil {
+		return doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return err
+	}
+	media, err := mediaParts(p)
+	if err != nil {
+		return err
+	}
+	for _, m := range media {
+		if m.Data, err = p.read(m.Name); err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtractMedia writes all images and other media to a directory, keeping their paths
+// in the package, like dir/word/media/image1.png
+func (doc *Docx) ExtractMedia(dir string) error {
+	return doc.ForEachMedia(func(m Media) error {
+		// names can't point outside of the directory
+		name := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+m.Name)))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(name, m.Data, 0644)
+	})
+}
+
+// mediaParts returns media of a package with their references but without their content
+func mediaParts(p *pkg) ([]Media, error) {
+	refs, err := referencedParts(p, "/media/", mediaRelTypes)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := p.types()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Media, 0, len(refs))
+	for name, references := range refs {
+		list = append(list, Media{Name: name, ContentType: ct.contentType(name), References: references})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// referencedParts returns parts in a directory (like "/media/") or targets of relationships
+// of given types together with relationships referring to them
+func referencedParts(p *pkg, dir string, relTypes map[string]bool) (map[string][]MediaReference, error) {
+	refs := make(map[string][]MediaReference)
+	for _, name := range p.names {
+		if strings.Contains(name, dir) {
+			refs[name] = nil
+		}
+	}
+	for _, relsPart := range p.names {
+		source, ok := relationshipSource(relsPart)
+		if !ok {
+			continue
+		}
+		rels, err := p.relationships(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode == "External" {
+				continue
+			}
+			target := resolveTarget(source, rel.Target)
+			if _, ok := refs[target]; !ok && (!relTypes[rel.Type] || !p.has(target)) {
+				continue
+			}
+			refs[target] = append(refs[target], MediaReference{Part: source, ID: rel.ID, Type: rel.Type})
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestForEachMedia(t *testing.T) {
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeImage + `" Target="https://example.com/logo.png" TargetMode="External"/>` +
+			`</Relationships>`,
+		"word/_rels/header1.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="` + relTypeImage + `" Target="/word/media/image1.png"/>` +
+			`</Relationships>`,
+		"word/header1.xml":      `<w:hdr xmlns:w="w"></w:hdr>`,
+		"word/media/image1.png": "png",
+		"word/media/unused.gif": "gif",
+		contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="png" ContentType="image/png"/><Override PartName="/word/media/unused.gif" ContentType="image/gif"/></Types>`,
+	}
+	doc := newTestDocx(t, p("Logo"), parts)
+	var got []Media
+	err := doc.ForEachMedia(func(m Media) error {
+		got = append(got, m)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Media{
+		{Name: "word/media/image1.png", ContentType: "image/png", Data: []byte("png"), References: []MediaReference{
+			{Part: documentXML, ID: "rId1", Type: relTypeImage},
+			{Part: "word/header1.xml", ID: "rId3", Type: relTypeImage},
+		}},
+		{Name: "word/media/unused.gif", ContentType: "image/gif", Data: []byte("gif")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	dir := t.TempDir()
+	if err := doc.ExtractMedia(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "word", "media", "unused.gif"))
+	if err != nil || string(data) != "gif" {
+		t.Errorf("Extracted %q, %v", data, err)
+	}
+}
@@ -0,0 +1,46 @@
+package docx
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	emfContentType = "image/x-emf"
+	wmfContentType = "image/x-wmf"
+	// wmfPlaceableKey starts WMF files with a bounding box
+	wmfPlaceableKey = 0x9AC6CDD7
+)
+
+// metafileType detects Windows metafiles, which http.DetectContentType doesn't recognize
+func metafileType(data []byte) string {
+	switch {
+	case len(data) >= 88 && binary.LittleEndian.Uint32(data) == 1 && string(data[40:44]) == " EMF":
+		return emfContentType
+	case len(data) >= 22 && binary.LittleEndian.Uint32(data) == wmfPlaceableKey:
+		return wmfContentType
+	case len(data) >= 18 && (binary.LittleEndian.Uint16(data) == 1 || binary.LittleEndian.Uint16(data) == 2) &&
+		binary.LittleEndian.Uint16(data[2:]) == 9:
+		return wmfContentType
+	}
+	return ""
+}
+
+// metafileSize returns dimensions of a metafile: EMF files have a frame in hundredths of a millimeter
+// and placeable WMF files have a bounding box in logical units per inch
+func metafileSize(data []byte, contentType string) (Length, Length, error) {
+	var width, height Length
+	if contentType == emfContentType {
+		frame := func(i int) Length { return Length(int32(binary.LittleEndian.Uint32(data[24+4*i:]))) }
+		width, height = (frame(2)-frame(0))*Millimeter/100, (frame(3)-frame(1))*Millimeter/100
+	} else if binary.LittleEndian.Uint32(data) == wmfPlaceableKey {
+		box := func(i int) Length { return Length(int16(binary.LittleEndian.Uint16(data[6+2*i:]))) }
+		if inch := Length(binary.LittleEndian.Uint16(data[14:])); inch > 0 {
+			width, height = (box(2)-box(0))*Inch/inch, (box(3)-box(1))*Inch/inch
+		}
+	}
+	if width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("Unknown size of %s image, set its Width and Height", contentType)
+	}
+	return width, height, nil
+}
@@ -0,0 +1,69 @@
+package docx
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// testEMF returns an EMF header with a frame in hundredths of a millimeter
+func testEMF(width, height int32) []byte {
+	data := make([]byte, 88)
+	binary.LittleEndian.PutUint32(data, 1)
+	binary.LittleEndian.PutUint32(data[4:], 88)
+	binary.LittleEndian.PutUint32(data[32:], uint32(width))
+	binary.LittleEndian.PutUint32(data[36:], uint32(height))
+	copy(data[40:], " EMF")
+	return data
+}
+
+// testWMF returns a WMF header, placeable one with a bounding box in twips
+func testWMF(placeable bool, width, height int16) []byte {
+	header := make([]byte, 18)
+	binary.LittleEndian.PutUint16(header, 1)
+	binary.LittleEndian.PutUint16(header[2:], 9)
+	if !placeable {
+		return header
+	}
+	data := make([]byte, 22, 40)
+	binary.LittleEndian.PutUint32(data, wmfPlaceableKey)
+	binary.LittleEndian.PutUint16(data[10:], uint16(width))
+	binary.LittleEndian.PutUint16(data[12:], uint16(height))
+	binary.LittleEndian.PutUint16(data[14:], 1440)
+	return append(data, header...)
+}
+
+func TestMetafileImages(t *testing.T) {
+	body := p("[emf] [wmf] [plain]")
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{
+		"emf":   Image{Data: testEMF(2000, 1000)},
+		"wmf":   Image{Data: testWMF(true, 1440, 720), Height: Inch},
+		"plain": Image{Data: testWMF(false, 0, 0), Width: Centimeter, Height: Centimeter},
+	})
+	parts := renderParts(t, doc)
+	for _, s := range []string{
+		`<wp:extent cx="720000" cy="360000">`,
+		`<wp:extent cx="1828800" cy="914400">`,
+		`<wp:extent cx="360000" cy="360000">`,
+	} {
+		if !strings.Contains(parts[documentXML], s) {
+			t.Errorf("Can't find %s in %s", s, parts[documentXML])
+		}
+	}
+	for _, s := range []string{
+		`<Default Extension="emf" ContentType="image/x-emf">`,
+		`<Default Extension="wmf" ContentType="image/x-wmf">`,
+	} {
+		if !strings.Contains(parts[contentTypesXML], s) {
+			t.Errorf("Can't find %s in %s", s, parts[contentTypesXML])
+		}
+	}
+	if _, ok := parts["word/media/image1.emf"]; !ok {
+		t.Error("EMF picture wasn't stored")
+	}
+
+	doc = newTestDocx(t, body, nil).ReplaceDict(Dict{"plain": Image{Data: testWMF(false, 0, 0)}})
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for a WMF picture without a size")
+	}
+}
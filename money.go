@@ -0,0 +1,166 @@
+package docx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money formatted according to the conventions of a locale,
+// e.g. "1.234,56 €" for de-DE or "$1,234.56" for en-US
+type Money struct {
+	Amount float64
+	// Currency is ISO 4217 code like "EUR"
+	Currency string
+	// Locale is BCP 47 language tag like "de-DE", en-US is used when it's empty
+	Locale string
+}
+
+// DocxValue allows to use Money in Dict
+func (m Money) DocxValue() (Value, error) {
+	s, err := m.Format()
+	return Text(s), err
+}
+
+// String returns formatted amount or an error description
+func (m Money) String() string {
+	s, err := m.Format()
+	if err != nil {
+		return err.Error()
+	}
+	return s
+}
+
+// Format returns the amount with currency symbol placed and separated as the locale requires
+func (m Money) Format() (string, error) {
+	locale := m.Locale
+	if locale == "" {
+		locale = "en-US"
+	}
+	format, ok := findLocale(locale)
+	if !ok {
+		return "", fmt.Errorf("Unsupported locale: %s", locale)
+	}
+	code := strings.ToUpper(m.Currency)
+	currency, ok := currencies[code]
+	if !ok {
+		currency = currencyFormat{symbol: code, decimals: 2}
+	}
+	if symbol, ok := format.symbols[code]; ok {
+		currency.symbol = symbol
+	}
+	number := format.number(math.Abs(m.Amount), currency.decimals)
+	s := strings.Replace(format.pattern, "#", number, 1)
+	s = strings.Replace(s, "¤", currency.symbol, 1)
+	if m.Amount < 0 && number != format.number(0, currency.decimals) {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+// localeFormat describes how numbers and money are written in a locale
+type localeFormat struct {
+	decimal string
+	group   string
+	// pattern shows position of a number (#) and a currency symbol (¤)
+	pattern string
+	// symbols overrides currency symbols used by the locale
+	symbols map[string]string
+}
+
+// number formats non-negative number with given number of decimal places
+func (f localeFormat) number(n float64, decimals int) string {
+	s := strconv.FormatFloat(n, 'f', decimals, 64)
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	var sb strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(f.group)
+		}
+		sb.WriteRune(digit)
+	}
+	if fraction != "" {
+		sb.WriteString(f.decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
+
+const (
+	nbsp       = " "
+	narrowNbsp = " "
+)
+
+var locales = map[string]localeFormat{
+	"en-US": {decimal: ".", group: ",", pattern: "¤#"},
+	"en-GB": {decimal: ".", group: ",", pattern: "¤#"},
+	"en-CA": {decimal: ".", group: ",", pattern: "¤#", symbols: map[string]string{"CAD": "$", "USD": "US$"}},
+	"en-AU": {decimal: ".", group: ",", pattern: "¤#", symbols: map[string]string{"AUD": "$", "USD": "US$"}},
+	"de-DE": {decimal: ",", group: ".", pattern: "#" + nbsp + "¤"},
+	"de-AT": {decimal: ",", group: nbsp, pattern: "¤" + nbsp + "#"},
+	"de-CH": {decimal: ".", group: "’", pattern: "¤" + nbsp + "#"},
+	"fr-FR": {decimal: ",", group: narrowNbsp, pattern: "#" + nbsp + "¤"},
+	"fr-CH": {decimal: ",", group: narrowNbsp, pattern: "#" + nbsp + "¤"},
+	"it-IT": {decimal: ",", group: ".", pattern: "#" + nbsp + "¤"},
+	"es-ES": {decimal: ",", group: ".", pattern: "#" + nbsp + "¤"},
+	"nl-NL": {decimal: ",", group: ".", pattern: "¤" + nbsp + "#"},
+	"pt-PT": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"pt-BR": {decimal: ",", group: ".", pattern: "¤" + nbsp + "#"},
+	"pl-PL": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"cs-CZ": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"sk-SK": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"sv-SE": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"da-DK": {decimal: ",", group: ".", pattern: "#" + nbsp + "¤"},
+	"nb-NO": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"fi-FI": {decimal: ",", group: nbsp, pattern: "#" + nbsp + "¤"},
+	"ja-JP": {decimal: ".", group: ",", pattern: "¤#", symbols: map[string]string{"JPY": "￥"}},
+}
+
+// languageLocales maps languages to locales used when region isn't given
+var languageLocales = map[string]string{
+	"en": "en-US", "de": "de-DE", "fr": "fr-FR", "it": "it-IT", "es": "es-ES",
+	"nl": "nl-NL", "pt": "pt-PT", "pl": "pl-PL", "cs": "cs-CZ", "sk": "sk-SK",
+	"sv": "sv-SE", "da": "da-DK", "nb": "nb-NO", "no": "nb-NO", "fi": "fi-FI",
+	"ja": "ja-JP",
+}
+
+// findLocale returns format of a locale like "de-DE", "de_DE" or just "de",
+// an unknown region like "de-LU" falls back to the locale of its language
+func findLocale(locale string) (localeFormat, bool) {
+	locale = strings.Replace(locale, "_", "-", -1)
+	for tag, format := range locales {
+		if strings.EqualFold(tag, locale) {
+			return format, true
+		}
+	}
+	language := strings.ToLower(strings.SplitN(locale, "-", 2)[0])
+	if tag, ok := languageLocales[language]; ok {
+		return locales[tag], true
+	}
+	return localeFormat{}, false
+}
+
+type currencyFormat struct {
+	symbol   string
+	decimals int
+}
+
+var currencies = map[string]currencyFormat{
+	"USD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"CHF": {"CHF", 2},
+	"JPY": {"¥", 0},
+	"CAD": {"CA$", 2},
+	"AUD": {"A$", 2},
+	"PLN": {"zł", 2},
+	"CZK": {"Kč", 2},
+	"SEK": {"kr", 2},
+	"NOK": {"kr", 2},
+	"DKK": {"kr.", 2},
+	"BRL": {"R$", 2},
+}
@@ -0,0 +1,39 @@
+package docx
+
+import "testing"
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 1234.56, Currency: "USD"}, "$1,234.56"},
+		{Money{Amount: 1234.56, Currency: "EUR", Locale: "de-DE"}, "1.234,56 €"},
+		{Money{Amount: -1234.5, Currency: "EUR", Locale: "de"}, "-1.234,50 €"},
+		{Money{Amount: 1234567.891, Currency: "EUR", Locale: "fr_FR"}, "1 234 567,89 €"},
+		{Money{Amount: 999.999, Currency: "GBP", Locale: "en-GB"}, "£1,000.00"},
+		{Money{Amount: 1234.5, Currency: "JPY", Locale: "ja-JP"}, "￥1,234"},
+		{Money{Amount: 12, Currency: "XYZ", Locale: "en-US"}, "XYZ12.00"},
+		{Money{Amount: -0.001, Currency: "USD"}, "$0.00"},
+		{Money{Amount: 1234.5, Currency: "EUR", Locale: "en-IE"}, "€1,234.50"},
+		{Money{Amount: 1234.5, Currency: "EUR", Locale: "de-LU"}, "1.234,50 €"},
+		{Money{Amount: 1234.5, Currency: "EUR", Locale: "es-MX"}, "1.234,50 €"},
+		{Money{Amount: 1234.5, Currency: "EUR", Locale: "fr_BE"}, "1 234,50 €"},
+		{Money{Amount: 1234.5, Currency: "EUR", Locale: "DE-at"}, "€ 1 234,50"},
+	}
+	for _, test := range tests {
+		got, err := test.money.Format()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.money, got, test.want)
+		}
+	}
+	for _, locale := range []string{"xx-XX", "xx", "xx-DE"} {
+		if _, err := (Money{Amount: 1, Currency: "EUR", Locale: locale}).Format(); err == nil {
+			t.Errorf("Expected error for unknown locale %s", locale)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Mustache switches to the syntax of Mustache templates used by other tools like docxtemplater:
+// {{name}}, sections {{#items}}...{{/items}} repeated for every item of a list (or kept when
+// the value is true) and inverted sections {{^items}}...{{/items}} kept when the value is false
+// or empty. {{.}} is an item of a list of other values than Dicts, {{! comments}} are removed
+func (doc *Docx) Mustache() *Docx {
+	doc.mustache = true
+	return doc.Delimiters("{{", "}}")
+}
+
+// mustacheTags turns tags of Mustache sections into block markers, like {{#items}} into
+// {{#section items}} and {{/items}} into {{/section}}. Unmatched tags are left as they are,
+// so they are reported like any unexpected end of a block
+func (doc *Docx) mustacheTags(root *node) {
+	if !doc.mustache {
+		return
+	}
+	type section struct{ name, kind string }
+	var open []section
+	for _, p := range root.find("w:p") {
+		para := newParagraph(p)
+		type edit struct {
+			start, end int
+			text       string
+		}
+		var edits []edit
+		for _, ph := range doc.placeholders(para.text) {
+			name := strings.TrimSpace(ph.name)
+			if name == "" {
+				continue
+			}
+			tag := ""
+			switch name[0] {
+			case '#':
+				open = append(open, section{strings.TrimSpace(name[1:]), "section"})
+				tag = "#section " + strings.TrimSpace(name[1:])
+			case '^':
+				open = append(open, section{strings.TrimSpace(name[1:]), "inverted"})
+				tag = "#inverted " + strings.TrimSpace(name[1:])
+			case '/':
+				if n := len(open); n > 0 && open[n-1].name == strings.TrimSpace(name[1:]) {
+					tag = "/" + open[n-1].kind
+					open = open[:n-1]
+				}
+			case '!':
+				edits = append(edits, edit{ph.start, ph.end, ""})
+			case '&':
+				tag = strings.TrimSpace(name[1:])
+			case '{':
+				// {{{name}}} isn't escaped by Mustache, values are always escaped in XML
+				if strings.HasPrefix(para.text[ph.end:], "}") {
+					edits = append(edits, edit{ph.start, ph.end + 1, doc.openingBracket + strings.TrimSpace(name[1:]) + doc.closingBracket})
+				}
+			}
+			if tag != "" {
+				edits = append(edits, edit{ph.start, ph.end, doc.openingBracket + tag + doc.closingBracket})
+			}
+		}
+		for i := len(edits) - 1; i >= 0; i-- {
+			if edits[i].text == "" {
+				para.erase(edits[i].start, edits[i].end)
+			} else {
+				para.replace(edits[i].start, edits[i].end, edits[i].text)
+			}
+		}
+	}
+}
+
+// sectionScopes returns scopes of a Mustache section: a scope of every item of a list,
+// of a Dict, or of the value itself when it's true. Items and values are called "." in the scope
+func (ctx *renderContext) sectionScopes(start marker) []*scope {
+	s := ctx.scopeOf(start.para)
+	v, _ := s.lookup(start.arg)
+	if !truthy(v) {
+		return nil
+	}
+	if dict, ok := toDict(v); ok {
+		return []*scope{s.child(dict)}
+	}
+	var items []interface{}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, rv.Index(i).Interface())
+		}
+	} else {
+		items = []interface{}{v}
+	}
+	scopes := make([]*scope, len(items))
+	for i, item := range items {
+		dict, ok := toDict(item)
+		if !ok {
+			dict = Dict{".": item}
+		}
+		scopes[i] = s.child(dict)
+	}
+	return scopes
+}
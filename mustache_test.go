@@ -0,0 +1,40 @@
+package docx
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMustache(t *testing.T) {
+	body := p("Dear {{name}},{{! greeting }}") +
+		p("{{#items}}") + p("{{title}}: {{{price}}}") + p("{{/items}}") +
+		p("{{^items}}No items.{{/items}}") +
+		p("Tags: {{#tags}}{{.}} {{/tags}}") +
+		p("{{#vip}}VIP {{&level}}{{/vip}}{{#address}} at {{city}}{{/address}}")
+	tests := []struct {
+		dict Dict
+		want string
+	}{
+		{
+			Dict{"name": "Ann", "items": []Dict{{"title": "Pen", "price": 2}, {"title": "Book", "price": 10}},
+				"tags": []string{"a", "b"}, "vip": true, "level": "gold", "address": Dict{"city": "Oslo"}},
+			p("Dear Ann,") + p("Pen: 2") + p("Book: 10") +
+				`<w:p><w:r><w:t xml:space="preserve">Tags: </w:t></w:r><w:r><w:t xml:space="preserve">a </w:t></w:r><w:r><w:t xml:space="preserve">b </w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>VIP gold</w:t></w:r><w:r><w:t xml:space="preserve"> at Oslo</w:t></w:r></w:p>`,
+		},
+		{
+			Dict{"name": "Bob"},
+			p("Dear Bob,") + p("No items.") + `<w:p><w:r><w:t xml:space="preserve">Tags: </w:t></w:r></w:p>`,
+		},
+	}
+	for _, test := range tests {
+		got := renderBody(t, newTestDocx(t, body, nil).Mustache().ReplaceDict(test.dict))
+		if got != test.want {
+			t.Errorf("%v:\n got: %s\nwant: %s", test.dict, got, test.want)
+		}
+	}
+
+	if _, err := newTestDocx(t, p("{{#items}}")+p("{{/other}}"), nil).Mustache().WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of a section closed by another name")
+	}
+}
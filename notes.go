@@ -0,0 +1,119 @@
+package docx
+
+import (
+	"strconv"
+)
+
+const (
+	relTypeFootnotes = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes"
+	relTypeEndnotes  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/endnotes"
+)
+
+// noteKind describes footnotes or endnotes
+type noteKind struct {
+	reference, note, relType string
+}
+
+var noteKinds = []noteKind{
+	{"w:footnoteReference", "w:footnote", relTypeFootnotes},
+	{"w:endnoteReference", "w:endnote", relTypeEndnotes},
+}
+
+// renumberNotes gives footnotes and endnotes IDs in order of their references. Notes referenced
+// more than once (like those of repeated content) are copied, notes of building blocks are copied
+// from the glossary and notes which aren't referenced anymore are removed
+func (ctx *renderContext) renumberNotes() error {
+	for _, kind := range noteKinds {
+		if err := ctx.renumber(kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctx *renderContext) renumber(kind noteKind) error {
+	name, ok := ctx.pkg.relatedPart(ctx.part, kind.relType)
+	if !ok || !ctx.pkg.has(name) {
+		return nil
+	}
+	root, ok, err := ctx.parseAuxiliaryPart(name)
+	if !ok {
+		return err
+	}
+	el := root.documentElement()
+	if el == nil {
+		return nil
+	}
+	// separators keep their IDs, other notes are numbered after them
+	next := 1
+	notes := make(map[string]*node)
+	var normal []*node
+	for _, n := range el.elements(kind.note) {
+		if typ := n.attrValue("w:type"); typ != "" && typ != "normal" {
+			if id, err := strconv.Atoi(n.attrValue("w:id")); err == nil && id >= next {
+				next = id + 1
+			}
+			continue
+		}
+		notes[n.attrValue("w:id")] = n
+		normal = append(normal, n)
+	}
+	changed := false
+	used := make(map[*node]bool)
+	var ordered []*node
+	for _, ref := range ctx.root.find(kind.reference) {
+		note, err := ctx.referencedNote(ref, kind, notes)
+		if err != nil {
+			return err
+		}
+		if note == nil {
+			continue
+		}
+		if used[note] {
+			note = note.clone()
+			changed = true
+		}
+		used[note] = true
+		id := strconv.Itoa(next)
+		next++
+		if ref.attrValue("w:id") != id || note.attrValue("w:id") != id {
+			changed = true
+		}
+		ref.setAttr("w:id", id)
+		note.setAttr("w:id", id)
+		ordered = append(ordered, note)
+	}
+	if len(ordered) != len(normal) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	for _, n := range normal {
+		n.remove()
+	}
+	el.append(ordered...)
+	return ctx.pkg.setPart(name, root)
+}
+
+// referencedNote returns a note of a reference, notes of building blocks are copied from the glossary
+func (ctx *renderContext) referencedNote(ref *node, kind noteKind, notes map[string]*node) (*node, error) {
+	source, ok := ctx.noteSources[ref]
+	if !ok {
+		return notes[ref.attrValue("w:id")], nil
+	}
+	root, ok := ctx.noteParts[source]
+	if !ok {
+		var err error
+		if root, err = ctx.pkg.parsePart(source); err != nil {
+			return nil, err
+		}
+		ctx.noteParts[source] = root
+	}
+	for _, n := range root.find(kind.note) {
+		if n.attrValue("w:id") == ref.attrValue("w:id") {
+			return n.clone(), nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenumberNotes(t *testing.T) {
+	note := func(id, text string) string {
+		return `<w:footnote w:id="` + id + `"><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:footnote>`
+	}
+	ref := func(id string) string {
+		return `<w:r><w:footnoteReference w:id="` + id + `"/></w:r>`
+	}
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeFootnotes + `" Target="footnotes.xml"/></Relationships>`,
+		"word/footnotes.xml": `<w:footnotes xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` +
+			`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:continuationSeparator/></w:r></w:p></w:footnote>` +
+			note("1", "Removed") + note("2", "Item") + note("3", "Last") + `</w:footnotes>`,
+	}
+	item := sdt(`<w15:repeatingSectionItem/>`, `<w:p><w:r><w:t>[name]</w:t></w:r>`+ref("2")+`</w:p>`)
+	body := p("[#if false]") + `<w:p>` + ref("1") + `</w:p>` + p("[/if]") +
+		sdt(`<w:tag w:val="items"/><w15:repeatingSection/>`, item) +
+		`<w:p>` + ref("3") + `</w:p>`
+	doc := newTestDocx(t, body, parts).ReplaceDict(Dict{"items": []Dict{{"name": "A"}, {"name": "B"}}})
+	got := renderParts(t, doc)
+	last := -1
+	for _, id := range []string{"1", "2", "3"} {
+		i := strings.Index(got[documentXML], `<w:footnoteReference w:id="`+id+`">`)
+		if i < last || strings.Count(got[documentXML], `w:id="`+id+`"`) != 1 {
+			t.Errorf("Unexpected reference %s in %s", id, got[documentXML])
+		}
+		last = i
+	}
+	want := `<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:continuationSeparator></w:continuationSeparator></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="1"><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="2"><w:p><w:r><w:t>Item</w:t></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="3"><w:p><w:r><w:t>Last</w:t></w:r></w:p></w:footnote></w:footnotes>`
+	if !strings.Contains(got["word/footnotes.xml"], want) {
+		t.Errorf("got: %s\nwant: %s", got["word/footnotes.xml"], want)
+	}
+}
+
+func TestRenderNotes(t *testing.T) {
+	parts := map[string]string{
+		"word/footnotes.xml": `<w:footnotes xmlns:w="w"><w:footnote w:id="1">` + p("See [statute_ref].") + `</w:footnote></w:footnotes>`,
+		"word/endnotes.xml":  `<w:endnotes xmlns:w="w"><w:endnote w:id="1">` + p("[case_ref|bold]") + `</w:endnote></w:endnotes>`,
+	}
+	doc := newTestDocx(t, p("Text"), parts).ReplaceDict(Dict{"statute_ref": "§ 12 BGB", "case_ref": "Doe v. Roe"})
+	got := renderParts(t, doc)
+	for name, want := range map[string]string{"word/footnotes.xml": "See § 12 BGB.", "word/endnotes.xml": "Doe v. Roe"} {
+		if !strings.Contains(got[name], want) {
+			t.Errorf("Expected %q in %s: %s", want, name, got[name])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package docx
+
+import (
+	"strings"
+)
+
+// LimitOccurrences replaces only the first n placeholders of a variable, later ones are left
+// as they are. All placeholders are replaced by default, the body goes first and then other parts
+// like headers and footers. Placeholders with filters count too: [total] and [total|money:EUR],
+// placeholders left without a value don't. Names are compared like keys of the dictionary
+func (doc *Docx) LimitOccurrences(name string, n int) *Docx {
+	if doc.limits == nil {
+		doc.limits = make(map[string]int)
+	}
+	name = strings.TrimPrefix(strings.TrimSuffix(name, doc.closingBracket), doc.openingBracket)
+	doc.limits[name] = n
+	return doc
+}
+
+// limitOf returns the limit of occurrences of a variable of a placeholder and its name
+// compared like keys of the dictionary, so [Name] and [name] share a limit with IgnoreCase
+func (ctx *renderContext) limitOf(placeholder string) (string, int, bool) {
+	name, _ := parseFilters(placeholder)
+	name = ctx.doc.normalizeKey(name)
+	for key, limit := range ctx.doc.limits {
+		if ctx.doc.normalizeKey(key) == name {
+			return name, limit, true
+		}
+	}
+	return name, 0, false
+}
+
+// limitReached checks if a placeholder of a variable is over its limit of occurrences
+func (ctx *renderContext) limitReached(placeholder string) bool {
+	name, limit, ok := ctx.limitOf(placeholder)
+	return ok && ctx.pkg.occurrences[name] >= limit
+}
+
+// countOccurrence counts a replaced placeholder of a variable with a limit,
+// placeholders without a value don't use the limit up
+func (ctx *renderContext) countOccurrence(placeholder string) {
+	name, _, ok := ctx.limitOf(placeholder)
+	if !ok {
+		return
+	}
+	if ctx.pkg.occurrences == nil {
+		ctx.pkg.occurrences = make(map[string]int)
+	}
+	ctx.pkg.occurrences[name]++
+}
@@ -0,0 +1,56 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLimitOccurrences(t *testing.T) {
+	body := p("[name] and [name|bold], [name] again") + p("[company] [company]")
+	header := `<w:hdr xmlns:w="w">` + p("[name] [company]") + `</w:hdr>`
+	tests := []struct {
+		limits            map[string]int
+		document, header1 map[string]int
+	}{
+		{nil, map[string]int{"Ann": 3, "ACME": 2}, map[string]int{"Ann": 1, "ACME": 1}},
+		{
+			map[string]int{"[name]": 2, "company": 1},
+			map[string]int{"Ann": 2, "[name]": 1, "ACME": 1, "[company]": 1},
+			map[string]int{"[name]": 1, "[company]": 1},
+		},
+	}
+	for _, test := range tests {
+		doc := newTestDocx(t, body, map[string]string{"word/header1.xml": header}).
+			ReplaceDict(Dict{"name": "Ann", "company": "ACME"})
+		for name, n := range test.limits {
+			doc.LimitOccurrences(name, n)
+		}
+		got := renderParts(t, doc)
+		for part, counts := range map[string]map[string]int{documentXML: test.document, "word/header1.xml": test.header1} {
+			for text, n := range counts {
+				if strings.Count(got[part], text) != n {
+					t.Errorf("%v: expected %d of %s in %s", test.limits, n, text, got[part])
+				}
+			}
+		}
+	}
+}
+
+func TestLimitResolvedOccurrences(t *testing.T) {
+	// placeholders without a value don't use the limit up
+	calls := 0
+	doc := newTestDocx(t, p("[seq] [seq] [seq]"), nil).LimitOccurrences("seq", 1).
+		ReplaceFunc(func(placeholder string) (string, bool) {
+			calls++
+			return "first", calls > 1
+		})
+	if got, want := renderBody(t, doc), p("[seq] first [seq]"); got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+	// names are compared like keys of the dictionary
+	doc = newTestDocx(t, p("[Name] [name] [ NAME ]"), nil).ReplaceDict(Dict{"name": "Ann"}).
+		IgnoreCase(true).NormalizeSpace(true).LimitOccurrences("[NAME]", 1)
+	if got, want := renderBody(t, doc), p("Ann [name] [ NAME ]"); got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,436 @@
+package docx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"strings"
+)
+
+const relTypeFontTable = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable"
+
+// OptimizeReport describes how much a document was shrunk by Optimize
+type OptimizeReport struct {
+	// Before and After are sizes of the written document in bytes
+	Before int64        `json:"before"`
+	After  int64        `json:"after"`
+	Passes []PassReport `json:"passes"`
+}
+
+// PassReport is a result of a single pass of Optimize
+type PassReport struct {
+	Name string `json:"name"`
+	// Changed is the number of removed or changed items, like fonts, images or styles
+	Changed int   `json:"changed"`
+	Saved   int64 `json:"saved"`
+}
+
+// optimizePass changes parts of a document and returns the number of changed items
+type optimizePass struct {
+	name string
+	run  func(doc *Docx, p *pkg) (int, error)
+}
+
+var optimizePasses = []optimizePass{
+	{"fonts", stripFonts},
+	{"images", recompressImages},
+	{"media", deduplicateMedia},
+	{"styles", pruneStyles},
+	{"rsids", removeRsids},
+}
+
+// JPEGQuality lets Optimize recompress JPEG images with a quality from 1 to 100. Recompression
+// loses details, so JPEG images are kept by default (quality 0) and images with metadata like
+// EXIF orientation or color profiles are always kept
+func (doc *Docx) JPEGQuality(quality int) *Docx {
+	if quality < 0 || quality > 100 {
+		doc.err = fmt.Errorf("JPEG quality %d isn't between 1 and 100", quality)
+		return doc
+	}
+	doc.jpegQuality = quality
+	return doc
+}
+
+// Optimize shrinks the template by removing embedded fonts, recompressing images (without loss
+// unless JPEGQuality is set), removing duplicate media, removing unused custom styles and removing
+// revision IDs (rsids) Word uses to merge documents. Changed parts override parts of the template, which can be saved with
+// WriteTemplate. It returns sizes of the document before and after each pass
+func (doc *Docx) Optimize() (OptimizeReport, error) {
+	var report OptimizeReport
+	size, err := doc.templateSize()
+	if err != nil {
+		return report, err
+	}
+	report.Before = size
+	for _, pass := range optimizePasses {
+		p := newPkg(doc.zipReader)
+		err := doc.setParts(p)
+		changed := 0
+		if err == nil {
+			changed, err = pass.run(doc, p)
+		}
+		p.close()
+		if err != nil {
+			return report, err
+		}
+		after, err := doc.templateSize()
+		if err != nil {
+			return report, err
+		}
+		report.Passes = append(report.Passes, PassReport{Name: pass.name, Changed: changed, Saved: size - after})
+		size = after
+	}
+	report.After = size
+	return report, nil
+}
+
+// templateSize returns the size of the template written by WriteTemplate
+func (doc *Docx) templateSize() (int64, error) {
+	return doc.WriteTemplate(ioutil.Discard)
+}
+
+// setRelationships stores changed relationships of a part
+func (doc *Docx) setRelationships(part string, rels *relationships) error {
+	data, err := marshalPart(rels)
+	if err != nil {
+		return err
+	}
+	doc.SetPart(relsName(part), data)
+	return nil
+}
+
+// stripFonts removes fonts embedded in the font table and turns off embedding of fonts on save
+func stripFonts(doc *Docx, p *pkg) (int, error) {
+	name, ok := p.relatedPart(documentXML, relTypeFontTable)
+	if !ok || !p.has(name) {
+		return 0, nil
+	}
+	root, err := p.parsePart(name)
+	if err != nil {
+		return 0, err
+	}
+	rels, err := p.relationships(name)
+	if err != nil {
+		return 0, err
+	}
+	removed := make(map[string]bool)
+	for _, tag := range []string{"w:embedRegular", "w:embedBold", "w:embedItalic", "w:embedBoldItalic"} {
+		for _, n := range root.find(tag) {
+			removed[n.attrValue("r:id")] = true
+			n.remove()
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	kept := rels.Relationships[:0]
+	for _, rel := range rels.Relationships {
+		if !removed[rel.ID] {
+			kept = append(kept, rel)
+			continue
+		}
+		if rel.TargetMode != "External" {
+			doc.RemovePart(resolveTarget(name, rel.Target))
+		}
+	}
+	rels.Relationships = kept
+	if err := doc.setRelationships(name, rels); err != nil {
+		return 0, err
+	}
+	data, err := root.bytes()
+	if err != nil {
+		return 0, err
+	}
+	doc.SetPart(name, data)
+	if settings, ok := p.relatedPart(documentXML, relTypeSettings); ok && p.has(settings) {
+		root, err := p.parsePart(settings)
+		if err != nil {
+			return 0, err
+		}
+		if el := root.documentElement(); el != nil {
+			for _, tag := range []string{"w:embedTrueTypeFonts", "w:embedSystemFonts", "w:saveSubsetFonts"} {
+				el.removeChild(tag)
+			}
+		}
+		data, err := root.bytes()
+		if err != nil {
+			return 0, err
+		}
+		doc.SetPart(settings, data)
+	}
+	return len(removed), nil
+}
+
+// recompressImages encodes PNG images (and JPEG images with JPEGQuality) again
+// and keeps them if they get smaller
+func recompressImages(doc *Docx, p *pkg) (int, error) {
+	media, err := mediaParts(p)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	for _, m := range media {
+		if m.ContentType != "image/jpeg" && m.ContentType != "image/png" {
+			continue
+		}
+		data, err := p.read(m.Name)
+		if err != nil {
+			return 0, err
+		}
+		if smaller, ok := recompress(data, m.ContentType, doc.jpegQuality); ok {
+			doc.SetPart(m.Name, smaller)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// recompress returns an encoded image if it's smaller than the original. Images which can't be
+// decoded and images with metadata, which encoders don't write, are left unchanged. PNG images
+// are encoded without loss, JPEG images only with a quality
+func recompress(data []byte, contentType string, jpegQuality int) ([]byte, bool) {
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		if jpegQuality == 0 || jpegHasMetadata(data) {
+			return nil, false
+		}
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil || jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}) != nil {
+			return nil, false
+		}
+	case "image/png":
+		if pngHasMetadata(data) {
+			return nil, false
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		enc := png.Encoder{CompressionLevel: png.BestCompression}
+		if err != nil || enc.Encode(&buf, img) != nil {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	if buf.Len() >= len(data) {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// jpegHasMetadata checks if a JPEG image has segments besides JFIF before image data,
+// like EXIF (with orientation of photos), XMP, ICC color profiles or comments.
+// Malformed images count as images with metadata
+func jpegHasMetadata(data []byte) bool {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return true
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return true
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xDA: // start of scan
+			return false
+		case marker == 0xFE || (marker >= 0xE1 && marker <= 0xEF): // comment, APP1-APP15
+			return true
+		}
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+	}
+	return true
+}
+
+// pngKnownChunks are chunks of PNG images which png.Encode writes again
+var pngKnownChunks = map[string]bool{"IHDR": true, "PLTE": true, "tRNS": true, "IDAT": true, "IEND": true}
+
+// pngHasMetadata checks if a PNG image has chunks which would be lost by encoding it again,
+// like text, gamma, physical size or color profiles. Malformed images count as images with metadata
+func pngHasMetadata(data []byte) bool {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return true
+	}
+	for i := len(signature); i+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		if length < 0 || length > len(data) {
+			return true
+		}
+		chunk := string(data[i+4 : i+8])
+		if !pngKnownChunks[chunk] {
+			return true
+		}
+		if chunk == "IEND" {
+			return false
+		}
+		i += 12 + length
+	}
+	return true
+}
+
+// deduplicateMedia makes relationships to media with the same content refer to a single part
+// and removes the other copies
+func deduplicateMedia(doc *Docx, p *pkg) (int, error) {
+	media, err := mediaParts(p)
+	if err != nil {
+		return 0, err
+	}
+	first := make(map[[sha256.Size]byte]string)
+	changedRels := make(map[string]*relationships)
+	removed := 0
+	for _, m := range media {
+		data, err := p.read(m.Name)
+		if err != nil {
+			return 0, err
+		}
+		sum := sha256.Sum256(data)
+		kept, ok := first[sum]
+		if !ok {
+			first[sum] = m.Name
+			continue
+		}
+		for _, ref := range m.References {
+			rels, err := p.relationships(ref.Part)
+			if err != nil {
+				return 0, err
+			}
+			for i, rel := range rels.Relationships {
+				if rel.ID == ref.ID {
+					rels.Relationships[i].Target = relTarget(ref.Part, kept)
+				}
+			}
+			changedRels[ref.Part] = rels
+		}
+		doc.RemovePart(m.Name)
+		removed++
+	}
+	for part, rels := range changedRels {
+		if err := doc.setRelationships(part, rels); err != nil {
+			return 0, err
+		}
+	}
+	return removed, nil
+}
+
+// styleReferences are elements referring to styles by their IDs
+var styleReferences = map[string]bool{
+	"w:pStyle": true, "w:rStyle": true, "w:tblStyle": true, "w:numStyleLink": true, "w:styleLink": true,
+	"w:basedOn": true, "w:next": true, "w:link": true,
+}
+
+// pruneStyles removes custom styles which aren't used by any part nor by used styles,
+// built-in styles are kept because Word relies on them
+func pruneStyles(doc *Docx, p *pkg) (int, error) {
+	name, ok := p.relatedPart(documentXML, relTypeStyles)
+	if !ok || !p.has(name) {
+		return 0, nil
+	}
+	styles, err := p.parsePart(name)
+	if err != nil {
+		return 0, err
+	}
+	used := make(map[string]bool)
+	for _, part := range p.names {
+		if part == name || !strings.HasPrefix(part, "word/") || !strings.HasSuffix(part, ".xml") {
+			continue
+		}
+		root, err := p.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		root.walk(func(n *node) bool {
+			if styleReferences[n.tag] {
+				used[n.attrValue("w:val")] = true
+			}
+			return true
+		})
+	}
+	// styles used by kept styles are kept too
+	byID := make(map[string]*node)
+	var queue []string
+	for _, s := range styles.find("w:style") {
+		id := s.attrValue("w:styleId")
+		byID[id] = s
+		if s.attrValue("w:customStyle") != "1" || s.attrValue("w:default") == "1" {
+			used[id] = true
+		}
+	}
+	for id := range used {
+		queue = append(queue, id)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		s, ok := byID[id]
+		if !ok {
+			continue
+		}
+		for _, c := range s.children {
+			if ref := c.attrValue("w:val"); styleReferences[c.tag] && !used[ref] {
+				used[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+	removed := 0
+	for id, s := range byID {
+		if !used[id] {
+			s.remove()
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	data, err := styles.bytes()
+	if err != nil {
+		return 0, err
+	}
+	doc.SetPart(name, data)
+	return removed, nil
+}
+
+// removeRsids removes revision save IDs, which are only used by Word to compare and merge documents
+func removeRsids(doc *Docx, p *pkg) (int, error) {
+	removed := 0
+	for _, name := range p.names {
+		if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		data, err := p.read(name)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.Contains(data, []byte("w:rsid")) {
+			continue
+		}
+		root, err := scan(data)
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range root.find("w:rsids") {
+			n.remove()
+			removed++
+		}
+		root.walk(func(n *node) bool {
+			attrs := n.attr[:0]
+			for _, a := range n.attr {
+				if strings.HasPrefix(a.Name.Local, "w:rsid") {
+					removed++
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			n.attr = attrs
+			return true
+		})
+		if data, err = root.bytes(); err != nil {
+			return 0, err
+		}
+		doc.SetPart(name, data)
+	}
+	return removed, nil
+}
@@ -0,0 +1,153 @@
+package docx
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestOptimize(t *testing.T) {
+	var img bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := enc.Encode(&img, image.NewGray(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatal(err)
+	}
+	rels := func(rels ...string) string {
+		return `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + strings.Join(rels, "") + `</Relationships>`
+	}
+	rel := func(id, typ, target string) string {
+		return `<Relationship Id="` + id + `" Type="` + typ + `" Target="` + target + `"/>`
+	}
+	body := `<w:p w:rsidR="00AB12CD" w:rsidRDefault="00AB12CD"><w:pPr><w:pStyle w:val="Used"/></w:pPr>` +
+		`<w:r w:rsidRPr="00AB12CD"><w:t>Logo</w:t></w:r></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": rels(rel("rId1", relTypeImage, "media/image1.png"), rel("rId2", relTypeImage, "media/image2.png"),
+			rel("rId3", relTypeFontTable, "fontTable.xml"), rel("rId4", relTypeSettings, "settings.xml"), rel("rId5", relTypeStyles, "styles.xml")),
+		"word/media/image1.png": img.String(),
+		"word/media/image2.png": img.String(),
+		"word/fontTable.xml": `<w:fonts xmlns:w="w" xmlns:r="r"><w:font w:name="Brand">` +
+			`<w:embedRegular r:id="rId1" w:fontKey="{00000000-0000-0000-0000-000000000000}"/></w:font></w:fonts>`,
+		"word/_rels/fontTable.xml.rels": rels(rel("rId1", "http://schemas.openxmlformats.org/officeDocument/2006/relationships/font", "fonts/font1.odttf")),
+		"word/fonts/font1.odttf":        strings.Repeat("font", 1000),
+		"word/settings.xml": `<w:settings xmlns:w="w"><w:embedTrueTypeFonts/><w:saveSubsetFonts/>` +
+			`<w:rsids><w:rsidRoot w:val="00AB12CD"/><w:rsid w:val="00AB12CD"/></w:rsids></w:settings>`,
+		"word/styles.xml": `<w:styles xmlns:w="w">` +
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/></w:style>` +
+			`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Base"><w:name w:val="Base"/></w:style>` +
+			`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Used"><w:name w:val="Used"/><w:basedOn w:val="Base"/></w:style>` +
+			`<w:style w:type="paragraph" w:customStyle="1" w:styleId="Unused"><w:name w:val="Unused"/></w:style>` +
+			`</w:styles>`,
+		contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="png" ContentType="image/png"/><Default Extension="odttf" ContentType="application/vnd.openxmlformats-officedocument.obfuscatedFont"/></Types>`,
+	}
+	doc := newTestDocx(t, body, parts)
+	report, err := doc.Optimize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(map[string]int)
+	for _, pass := range report.Passes {
+		changed[pass.Name] = pass.Changed
+	}
+	if want := map[string]int{"fonts": 1, "images": 2, "media": 1, "styles": 1, "rsids": 4}; !equalCounts(changed, want) {
+		t.Errorf("Changed %v, want %v", changed, want)
+	}
+	if report.After >= report.Before {
+		t.Errorf("Document wasn't shrunk: %+v", report)
+	}
+	got := renderParts(t, doc)
+	for _, name := range []string{"word/fonts/font1.odttf", "word/media/image2.png"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("%s wasn't removed", name)
+		}
+	}
+	if !strings.Contains(got["word/_rels/document.xml.rels"], `Id="rId2" Type="`+relTypeImage+`" Target="media/image1.png"`) {
+		t.Errorf("Duplicate image isn't replaced: %s", got["word/_rels/document.xml.rels"])
+	}
+	if strings.Contains(got[documentXML], "rsid") || strings.Contains(got["word/settings.xml"], "rsid") ||
+		strings.Contains(got["word/settings.xml"], "Fonts") || strings.Contains(got["word/fontTable.xml"], "embed") {
+		t.Errorf("Parts weren't cleaned: %s\n%s\n%s", got[documentXML], got["word/settings.xml"], got["word/fontTable.xml"])
+	}
+	if s := got["word/styles.xml"]; strings.Contains(s, `"Unused"`) || !strings.Contains(s, `"Base"`) || !strings.Contains(s, `"Heading1"`) {
+		t.Errorf("Styles weren't pruned: %s", s)
+	}
+}
+
+func equalCounts(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecompressImages(t *testing.T) {
+	// noise doesn't compress well at high quality
+	photo := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for i := range photo.Pix {
+		photo.Pix[i] = byte(i * 7919 % 251)
+	}
+	var plain bytes.Buffer
+	if err := jpeg.Encode(&plain, photo, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	// EXIF with orientation 6 (rotated by 90°) after the start of image
+	exif := []byte("\xff\xe1\x00\x22Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	oriented := append(append(append([]byte(nil), plain.Bytes()[:2]...), exif...), plain.Bytes()[2:]...)
+	var png1 bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := enc.Encode(&png1, image.NewGray(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatal(err)
+	}
+	// a text chunk after the header
+	text := []byte("\x00\x00\x00\x07tEXtAuthor\x00\x00\x00\x00\x00")
+	described := append(append(append([]byte(nil), png1.Bytes()[:33]...), text...), png1.Bytes()[33:]...)
+
+	tests := []struct {
+		data        []byte
+		contentType string
+		quality     int
+		changed     bool
+	}{
+		{plain.Bytes(), "image/jpeg", 0, false},
+		{plain.Bytes(), "image/jpeg", 50, true},
+		{oriented, "image/jpeg", 50, false},
+		{png1.Bytes(), "image/png", 0, true},
+		{described, "image/png", 0, false},
+	}
+	for i, test := range tests {
+		got, ok := recompress(test.data, test.contentType, test.quality)
+		if ok != test.changed || (ok && len(got) >= len(test.data)) {
+			t.Errorf("%d: %s with quality %d changed %v, want %v", i, test.contentType, test.quality, ok, test.changed)
+		}
+	}
+
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/photo.jpg"/></Relationships>`,
+		"word/media/photo.jpg": string(oriented),
+		contentTypesXML: `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="jpg" ContentType="image/jpeg"/></Types>`,
+	}
+	doc := newTestDocx(t, p("Photo"), parts).JPEGQuality(50)
+	if _, err := doc.Optimize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderParts(t, doc)["word/media/photo.jpg"]; got != string(oriented) {
+		t.Error("Expected the oriented photo to be kept")
+	}
+	if _, err := newTestDocx(t, p("Photo"), nil).JPEGQuality(101).Optimize(); err == nil {
+		t.Error("Expected error of an invalid quality")
+	}
+}
@@ -0,0 +1,153 @@
+package docx
+
+import (
+	"strconv"
+	"strings"
+)
+
+const relTypeStyles = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+
+// Heading is a heading of a document with headings of lower levels following it
+type Heading struct {
+	// Level is 1 for the top level headings (like Heading 1) up to 9
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// Paragraph is the position of the heading among paragraphs of the document starting at 0
+	Paragraph int `json:"paragraph"`
+	// Bookmark is the name of a bookmark placed in the heading (like one of a table of contents)
+	Bookmark string    `json:"bookmark,omitempty"`
+	Children []Heading `json:"children,omitempty"`
+}
+
+// Outline returns the hierarchy of headings of the document body. Headings are paragraphs having
+// an outline level set directly or by their style, like built-in styles Heading 1 to Heading 9
+func (doc *Docx) Outline() ([]Heading, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	root, err := p.parsePart(documentXML)
+	if err != nil {
+		return nil, err
+	}
+	styles, err := styleLevels(p)
+	if err != nil {
+		return nil, err
+	}
+	var top []Heading
+	// path are the last headings of each level, children are appended to them
+	var path []*Heading
+	for i, par := range root.find("w:p") {
+		level := paragraphLevel(par, styles)
+		if level == 0 {
+			continue
+		}
+		h := Heading{Level: level, Text: strings.TrimSpace(newParagraph(par).text), Paragraph: i}
+		for _, b := range par.find("w:bookmarkStart") {
+			if name := b.attrValue("w:name"); name != "_GoBack" {
+				h.Bookmark = name
+				break
+			}
+		}
+		for len(path) > 0 && path[len(path)-1].Level >= level {
+			path = path[:len(path)-1]
+		}
+		if len(path) == 0 {
+			top = append(top, h)
+			path = append(path, &top[len(top)-1])
+			continue
+		}
+		parent := path[len(path)-1]
+		parent.Children = append(parent.Children, h)
+		path = append(path, &parent.Children[len(parent.Children)-1])
+	}
+	return top, nil
+}
+
+// paragraphLevel returns the outline level of a paragraph from 1 to 9, or 0 for body text
+func paragraphLevel(p *node, styles map[string]int) int {
+	pPr := p.child("w:pPr")
+	if pPr == nil {
+		return styles[""]
+	}
+	if lvl := pPr.child("w:outlineLvl"); lvl != nil {
+		return outlineLevel(lvl)
+	}
+	style := ""
+	if s := pPr.child("w:pStyle"); s != nil {
+		style = s.attrValue("w:val")
+	}
+	return styles[style]
+}
+
+// outlineLevel converts a zero based <w:outlineLvl> to a level, level 9 is body text
+func outlineLevel(n *node) int {
+	lvl, err := strconv.Atoi(n.attrValue("w:val"))
+	if err != nil || lvl < 0 || lvl > 8 {
+		return 0
+	}
+	return lvl + 1
+}
+
+// styleLevels returns outline levels of paragraph styles, including inherited ones.
+// The level of the default style is stored with an empty ID
+func styleLevels(p *pkg) (map[string]int, error) {
+	levels := make(map[string]int)
+	name, ok := p.relatedPart(documentXML, relTypeStyles)
+	if !ok || !p.has(name) {
+		return levels, nil
+	}
+	root, err := p.parsePart(name)
+	if err != nil {
+		return nil, err
+	}
+	basedOn := make(map[string]string)
+	own := make(map[string]int)
+	for _, s := range root.find("w:style") {
+		if s.attrValue("w:type") != "paragraph" {
+			continue
+		}
+		id := s.attrValue("w:styleId")
+		if based := s.child("w:basedOn"); based != nil {
+			basedOn[id] = based.attrValue("w:val")
+		}
+		own[id] = -1
+		if pPr := s.child("w:pPr"); pPr != nil {
+			if lvl := pPr.child("w:outlineLvl"); lvl != nil {
+				own[id] = outlineLevel(lvl)
+			}
+		}
+		if own[id] == -1 && s.child("w:name") != nil {
+			// built-in heading styles may rely on their names
+			name := strings.ToLower(s.child("w:name").attrValue("w:val"))
+			if n, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil && strings.HasPrefix(name, "heading ") && n >= 1 && n <= 9 {
+				own[id] = n
+			}
+		}
+		if s.attrValue("w:default") == "1" {
+			basedOn[""] = id
+			own[""] = -1
+		}
+	}
+	for id := range own {
+		level := 0
+		// inheritance is followed a limited number of times, so cycles don't hang
+		for i, style := 0, id; i < 10; i++ {
+			if l, ok := own[style]; ok && l != -1 {
+				level = l
+				break
+			}
+			next, ok := basedOn[style]
+			if !ok {
+				break
+			}
+			style = next
+		}
+		levels[id] = level
+	}
+	return levels, nil
+}
@@ -0,0 +1,46 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOutline(t *testing.T) {
+	heading := func(style, text string) string {
+		return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	body := heading("Title", "Contract") + heading("Heading1", "Parties") + p("Text") +
+		heading("Heading2", "Buyer") + heading("Appendix", "Seller") +
+		`<w:p><w:pPr><w:outlineLvl w:val="0"/></w:pPr><w:bookmarkStart w:id="1" w:name="_Toc1"/><w:r><w:t>Terms</w:t></w:r><w:bookmarkEnd w:id="1"/></w:p>` +
+		heading("Heading3", "Payment")
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeStyles + `" Target="styles.xml"/>` +
+			`</Relationships>`,
+		"word/styles.xml": `<w:styles xmlns:w="w">` +
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Appendix"><w:name w:val="Appendix"/><w:basedOn w:val="Heading2"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:pPr><w:outlineLvl w:val="2"/></w:pPr></w:style>` +
+			`</w:styles>`,
+	}
+	got, err := newTestDocx(t, body, parts).Outline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Heading{
+		{Level: 1, Text: "Parties", Paragraph: 1, Children: []Heading{
+			{Level: 2, Text: "Buyer", Paragraph: 3},
+			{Level: 2, Text: "Seller", Paragraph: 4},
+		}},
+		{Level: 1, Text: "Terms", Paragraph: 5, Bookmark: "_Toc1", Children: []Heading{
+			{Level: 3, Text: "Payment", Paragraph: 6},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,538 @@
+package docx
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypesXML = "[Content_Types].xml"
+
+	relTypeImage              = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	relTypeGlossary           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/glossaryDocument"
+	relTypeHeader             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	relTypeFooter             = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	relTypeSettings           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings"
+	relTypeComments           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments"
+	relTypeCommentsExtended   = "http://schemas.microsoft.com/office/2011/relationships/commentsExtended"
+	relTypeCommentsIds        = "http://schemas.microsoft.com/office/2016/09/relationships/commentsIds"
+	relTypeCommentsExtensible = "http://schemas.microsoft.com/office/2018/08/relationships/commentsExtensible"
+)
+
+// pkg gives access to parts of a DOCX (zip) archive while it's being rendered
+// Parts are read on demand and can be overridden or added before the archive is written
+type pkg struct {
+	files map[string]*zip.File
+	// names of all parts in the order they will be written
+	names []string
+	// parts which content differs from the original archive
+	parts map[string][]byte
+	// spilled are names of temporary files with parts which didn't fit in memory
+	spilled map[string]string
+	// dryRun collects substitutions of placeholders
+	dryRun        bool
+	substitutions []Substitution
+	// unresolved are placeholders without values found in strict mode
+	unresolved []string
+	// computed are results of lazy values of the dictionary shared by all rendered parts
+	computed map[string]interface{}
+	// occurrences are numbers of replaced placeholders of variables with a limit
+	occurrences map[string]int
+	// modified is the time of all entries of reproducible output, nil keeps times of the template
+	modified *time.Time
+	// ctx stops rendering and writing when it's done, it's nil when nothing is rendered
+	ctx context.Context
+	// limit is the maximum size of parts kept in memory, 0 is unlimited
+	limit, size int64
+	flushPolicy FlushPolicy
+	// parsed relationships and content types which are serialized on write
+	rels         map[string]*relationships
+	contentTypes *contentTypes
+}
+
+func newPkg(r *zip.Reader) *pkg {
+	p := &pkg{
+		files:   make(map[string]*zip.File),
+		parts:   make(map[string][]byte),
+		spilled: make(map[string]string),
+		rels:    make(map[string]*relationships),
+	}
+	for _, f := range r.File {
+		if _, ok := p.files[f.Name]; ok {
+			continue
+		}
+		p.files[f.Name] = f
+		p.names = append(p.names, f.Name)
+	}
+	return p
+}
+
+// has checks if a part exists in the archive
+func (p *pkg) has(name string) bool {
+	if _, ok := p.parts[name]; ok {
+		return true
+	}
+	if _, ok := p.spilled[name]; ok {
+		return true
+	}
+	_, ok := p.files[name]
+	return ok
+}
+
+// read returns content of a part
+func (p *pkg) read(name string) ([]byte, error) {
+	if data, ok := p.parts[name]; ok {
+		return data, nil
+	}
+	if file, ok := p.spilled[name]; ok {
+		return ioutil.ReadFile(file)
+	}
+	f, ok := p.files[name]
+	if !ok {
+		return nil, &partError{name}
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// set overrides content of a part or adds a new one,
+// parts over the memory limit are stored in temporary files
+func (p *pkg) set(name string, data []byte) error {
+	if !p.has(name) {
+		p.names = append(p.names, name)
+	}
+	p.size -= int64(len(p.parts[name]))
+	delete(p.parts, name)
+	if p.limit > 0 && p.size+int64(len(data)) > p.limit {
+		file, ok := p.spilled[name]
+		if !ok {
+			f, err := ioutil.TempFile("", "docx-part-")
+			if err != nil {
+				return err
+			}
+			file = f.Name()
+			p.spilled[name] = file
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+		return ioutil.WriteFile(file, data, 0600)
+	}
+	if file, ok := p.spilled[name]; ok {
+		delete(p.spilled, name)
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+	p.size += int64(len(data))
+	p.parts[name] = data
+	return nil
+}
+
+// remove deletes a part from the archive
+func (p *pkg) remove(name string) {
+	for i, n := range p.names {
+		if n == name {
+			p.names = append(p.names[:i], p.names[i+1:]...)
+			break
+		}
+	}
+	p.size -= int64(len(p.parts[name]))
+	delete(p.parts, name)
+	delete(p.files, name)
+	if file, ok := p.spilled[name]; ok {
+		os.Remove(file)
+		delete(p.spilled, name)
+	}
+}
+
+// close removes temporary files of parts
+func (p *pkg) close() error {
+	var err error
+	for name, file := range p.spilled {
+		if e := os.Remove(file); e != nil && err == nil {
+			err = e
+		}
+		delete(p.spilled, name)
+	}
+	return err
+}
+
+// parsePart reads XML part into a tree of nodes
+func (p *pkg) parsePart(name string) (*node, error) {
+	data, err := p.read(name)
+	if err != nil {
+		return nil, err
+	}
+	return scan(data)
+}
+
+// setPart stores a tree of nodes as XML part
+func (p *pkg) setPart(name string, root *node) error {
+	data, err := root.bytes()
+	if err != nil {
+		return err
+	}
+	return p.set(name, data)
+}
+
+// relatedPart returns name of the first part of given relationship type
+// which is referenced by the source part
+func (p *pkg) relatedPart(source, relType string) (string, bool) {
+	rels, err := p.relationships(source)
+	if err != nil {
+		return "", false
+	}
+	for _, rel := range rels.Relationships {
+		if rel.Type == relType && rel.TargetMode != "External" {
+			return resolveTarget(source, rel.Target), true
+		}
+	}
+	return "", false
+}
+
+// resolveTarget returns part name of a relationship target
+func resolveTarget(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(source), target)
+}
+
+// uniqueName returns a part name based on a pattern like "word/media/image%d.png"
+// which doesn't collide with any existing part
+func (p *pkg) uniqueName(prefix, ext string) string {
+	for i := 1; ; i++ {
+		name := prefix + strconv.Itoa(i) + ext
+		if !p.has(name) {
+			return name
+		}
+	}
+}
+
+// writeTo stores all parts in a new zip archive
+func (p *pkg) writeTo(w io.Writer) (int64, error) {
+	if err := p.flush(); err != nil {
+		return 0, err
+	}
+	out := &flushWriter{w: w, every: p.flushPolicy.Bytes}
+	cw := &countingWriter{w: out}
+	zipOut := zip.NewWriter(cw)
+	for _, name := range p.names {
+		if err := p.cancelled(); err != nil {
+			return cw.n, err
+		}
+		if err := p.writePart(zipOut, name); err != nil {
+			return cw.n, err
+		}
+		if !p.flushPolicy.Parts {
+			continue
+		}
+		if err := zipOut.Flush(); err != nil {
+			return cw.n, err
+		}
+		if err := out.flush(); err != nil {
+			return cw.n, err
+		}
+	}
+	if err := zipOut.Close(); err != nil {
+		return cw.n, err
+	}
+	err := out.flush()
+	return cw.n, err
+}
+
+// cancelled returns an error of the context when it's done
+func (p *pkg) cancelled() error {
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+// writePart stores a part in a zip archive
+func (p *pkg) writePart(zipOut *zip.Writer, name string) error {
+	data, ok := p.parts[name]
+	file, spilled := p.spilled[name]
+	if !ok && !spilled {
+		// untouched parts (like images) are copied still compressed
+		return p.copyEntry(zipOut, p.files[name])
+	}
+	fw, err := p.createEntry(zipOut, name)
+	if err != nil {
+		return err
+	}
+	if spilled {
+		return copyFile(fw, file)
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// copyFile writes content of a file
+func copyFile(w io.Writer, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// flush serializes parsed relationships and content types back to parts
+func (p *pkg) flush() error {
+	// new parts of relationships are written in the same order every time
+	names := make([]string, 0, len(p.rels))
+	for name := range p.rels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rels := p.rels[name]
+		if !rels.modified {
+			continue
+		}
+		data, err := marshalPart(rels)
+		if err != nil {
+			return err
+		}
+		if err := p.set(name, data); err != nil {
+			return err
+		}
+	}
+	if p.contentTypes != nil && p.contentTypes.modified {
+		data, err := marshalPart(p.contentTypes)
+		if err != nil {
+			return err
+		}
+		if err := p.set(contentTypesXML, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partError is returned when a required part can't be found in the archive
+type partError struct {
+	name string
+}
+
+func (e *partError) Error() string {
+	return "Invalid DOCX document: " + e.name + " not found in the archive"
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	return n, err
+}
+
+func marshalPart(v interface{}) ([]byte, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// relationships is a content of .rels part
+type relationships struct {
+	XMLName       xml.Name       `xml:"http://schemas.openxmlformats.org/package/2006/relationships Relationships"`
+	Relationships []relationship `xml:"Relationship"`
+	modified      bool
+}
+
+type relationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr,omitempty"`
+}
+
+// relsName returns name of a part which stores relationships of a given part
+func relsName(part string) string {
+	dir, file := path.Split(part)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// relationshipSource returns the part whose relationships are stored in a .rels part,
+// relationships of the package have an empty source
+func relationshipSource(rels string) (string, bool) {
+	dir, file := path.Split(rels)
+	if !strings.HasSuffix(dir, "_rels/") || !strings.HasSuffix(file, ".rels") {
+		return "", false
+	}
+	return strings.TrimSuffix(dir, "_rels/") + strings.TrimSuffix(file, ".rels"), true
+}
+
+// relationships returns parsed relationships of a part
+func (p *pkg) relationships(part string) (*relationships, error) {
+	name := relsName(part)
+	if rels, ok := p.rels[name]; ok {
+		return rels, nil
+	}
+	rels := new(relationships)
+	if p.has(name) {
+		data, err := p.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := xml.Unmarshal(data, rels); err != nil {
+			return nil, err
+		}
+	}
+	p.rels[name] = rels
+	return rels, nil
+}
+
+// target returns a relationship target with given ID
+func (rels *relationships) target(id string) (relationship, bool) {
+	for _, rel := range rels.Relationships {
+		if rel.ID == id {
+			return rel, true
+		}
+	}
+	return relationship{}, false
+}
+
+// add creates new relationship and returns its ID
+func (rels *relationships) add(relType, target string) string {
+	return rels.addRelationship(relationship{Type: relType, Target: target})
+}
+
+// addRelationship stores a copy of a relationship with new ID and returns the ID
+func (rels *relationships) addRelationship(rel relationship) string {
+	ids := make(map[string]bool, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		ids[rel.ID] = true
+	}
+	id := ""
+	for i := len(rels.Relationships) + 1; ; i++ {
+		id = "rId" + strconv.Itoa(i)
+		if !ids[id] {
+			break
+		}
+	}
+	rel.ID = id
+	rels.Relationships = append(rels.Relationships, rel)
+	rels.modified = true
+	return id
+}
+
+// relTarget returns path of target part relative to the source part
+func relTarget(source, target string) string {
+	dir := path.Dir(source) + "/"
+	if strings.HasPrefix(target, dir) {
+		return strings.TrimPrefix(target, dir)
+	}
+	return "/" + target
+}
+
+// contentTypes is a content of [Content_Types].xml part
+type contentTypes struct {
+	XMLName   xml.Name     `xml:"http://schemas.openxmlformats.org/package/2006/content-types Types"`
+	Defaults  []ctDefault  `xml:"Default"`
+	Overrides []ctOverride `xml:"Override"`
+	modified  bool
+}
+
+type ctDefault struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+type ctOverride struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// types returns parsed [Content_Types].xml
+func (p *pkg) types() (*contentTypes, error) {
+	if p.contentTypes != nil {
+		return p.contentTypes, nil
+	}
+	ct := new(contentTypes)
+	data, err := p.read(contentTypesXML)
+	if err != nil {
+		return nil, err
+	}
+	if err := xml.Unmarshal(data, ct); err != nil {
+		return nil, err
+	}
+	p.contentTypes = ct
+	return ct, nil
+}
+
+// addDefault registers content type for files with given extension
+// unless the extension is already known
+func (ct *contentTypes) addDefault(ext, contentType string) {
+	for _, d := range ct.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return
+		}
+	}
+	ct.Defaults = append(ct.Defaults, ctDefault{Extension: ext, ContentType: contentType})
+	ct.modified = true
+}
+
+// addOverride registers content type of a part
+func (ct *contentTypes) addOverride(part, contentType string) {
+	part = "/" + strings.TrimPrefix(part, "/")
+	for i, o := range ct.Overrides {
+		if strings.EqualFold(o.PartName, part) {
+			ct.Overrides[i].ContentType = contentType
+			ct.modified = true
+			return
+		}
+	}
+	ct.Overrides = append(ct.Overrides, ctOverride{PartName: part, ContentType: contentType})
+	ct.modified = true
+}
+
+// contentType returns content type of a part
+func (ct *contentTypes) contentType(part string) string {
+	name := "/" + strings.TrimPrefix(part, "/")
+	for _, o := range ct.Overrides {
+		if strings.EqualFold(o.PartName, name) {
+			return o.ContentType
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(part), ".")
+	for _, d := range ct.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return d.ContentType
+		}
+	}
+	return ""
+}
+
+// removeOverride removes content type of a part
+func (ct *contentTypes) removeOverride(part string) {
+	part = "/" + strings.TrimPrefix(part, "/")
+	for i, o := range ct.Overrides {
+		if strings.EqualFold(o.PartName, part) {
+			ct.Overrides = append(ct.Overrides[:i], ct.Overrides[i+1:]...)
+			ct.modified = true
+			return
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyUntouchedParts(t *testing.T) {
+	modified := time.Date(2020, 4, 29, 9, 41, 0, 0, time.UTC)
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	files := map[string]string{documentXML: fmt.Sprintf(testDocumentXML, `<w:p><w:r><w:t>[a]</w:t></w:r></w:p>`)}
+	for name, content := range testParts {
+		files[name] = content
+	}
+	files["word/media/image1.png"] = "not really an image"
+	for name, content := range files {
+		method := zip.Deflate
+		if name == "word/media/image1.png" {
+			method = zip.Store
+		}
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out := new(bytes.Buffer)
+	doc := New(bytes.NewReader(buf.Bytes()), int64(buf.Len())).ReplaceDict(Dict{"a": "b"})
+	if _, err := doc.WriteTo(out); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range r.File {
+		if f.Name != "word/media/image1.png" {
+			continue
+		}
+		if f.Method != zip.Store || !f.Modified.Equal(modified) {
+			t.Errorf("Part is not copied as it was: method %d, modified %s", f.Method, f.Modified)
+		}
+		return
+	}
+	t.Error("Image not found in the archive")
+}
+
+func TestMemoryLimit(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	body := `<w:p><w:r><w:t>[a]</w:t></w:r></w:p><w:p><w:r><w:t>[logo]</w:t></w:r></w:p>`
+	dict := Dict{"a": "b", "logo": Image{Data: testPNG(t, 20, 10).Bytes()}}
+	want := renderParts(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	got := renderParts(t, newTestDocx(t, body, nil).ReplaceDict(dict).MemoryLimit(1))
+	if len(got) != len(want) {
+		t.Errorf("got %d parts, want %d", len(got), len(want))
+	}
+	for name, content := range want {
+		if got[name] != content {
+			t.Errorf("%s differs: %s\nwant: %s", name, got[name], content)
+		}
+	}
+	files, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("%d temporary files left", len(files))
+	}
+}
+
+func TestSkipPartsWithoutPlaceholders(t *testing.T) {
+	body := `<w:p><w:r><w:t>No variables</w:t><w:br/></w:r></w:p>`
+	got := renderParts(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"a": "b"}))
+	if want := fmt.Sprintf(testDocumentXML, body); got[documentXML] != want {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+}
+
+func TestConcurrentRenders(t *testing.T) {
+	body := `<w:p><w:r><w:t>[a]</w:t></w:r></w:p>`
+	docs := make([]*Docx, 8)
+	for i := range docs {
+		docs[i] = newTestDocx(t, body, nil).ReplaceDict(Dict{"a": i})
+	}
+	errs := make(chan error, len(docs))
+	outputs := make([]*bytes.Buffer, len(docs))
+	for i, doc := range docs {
+		outputs[i] = new(bytes.Buffer)
+		go func(doc *Docx, out *bytes.Buffer) {
+			_, err := doc.WriteTo(out)
+			errs <- err
+		}(doc, outputs[i])
+	}
+	for range docs {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, out := range outputs {
+		r, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range r.File {
+			if f.Name != documentXML {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf("<w:t>%d</w:t>", i); !bytes.Contains(content, []byte(want)) {
+				t.Errorf("%s not found in %s", want, content)
+			}
+		}
+	}
+}
+
+func TestParts(t *testing.T) {
+	doc := newTestDocx(t, `<w:p><w:r><w:t>[a]</w:t></w:r></w:p>`, nil).ReplaceDict(Dict{"a": "b"})
+	if _, err := doc.Part("word/settings.xml"); err == nil {
+		t.Error("Expected an error of a missing part")
+	}
+	settings := `<w:settings xmlns:w="w"><w:updateFields w:val="true"/></w:settings>`
+	doc.SetPart("word/settings.xml", []byte(settings)).
+		SetPart(documentXML, []byte(fmt.Sprintf(testDocumentXML, `<w:p><w:r><w:t>[a][a]</w:t></w:r></w:p>`)))
+	data, err := doc.Part("word/settings.xml")
+	if err != nil || string(data) != settings {
+		t.Errorf("got: %s, %v\nwant: %s", data, err, settings)
+	}
+	got := renderParts(t, doc)
+	if got["word/settings.xml"] != settings {
+		t.Errorf("got: %s\nwant: %s", got["word/settings.xml"], settings)
+	}
+	if !strings.Contains(got[documentXML], `<w:t>bb</w:t>`) {
+		t.Errorf("Placeholders weren't replaced in %s", got[documentXML])
+	}
+}
+
+func TestAddPart(t *testing.T) {
+	doc := newTestDocx(t, `<w:p></w:p>`, nil)
+	item := `<?xml version="1.0" encoding="UTF-8"?><order><id>42</id></order>`
+	id, err := doc.AddPart("customXml/item1.xml", "application/xml", []byte(item), documentXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	footnotes := `<w:footnotes xmlns:w="w"></w:footnotes>`
+	id2, err := doc.AddPart("word/footnotes.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml",
+		[]byte(footnotes), documentXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "rId1" || id2 != "rId2" {
+		t.Errorf("Unexpected IDs %s, %s", id, id2)
+	}
+	if _, err := doc.AddPart("word/footnotes.xml", "application/xml", nil, documentXML); err == nil {
+		t.Error("Expected an error of an existing part")
+	}
+	if _, err := doc.AddPart("word/data.bin", "application/octet-stream", nil, documentXML); err == nil {
+		t.Error("Expected an error of an unknown content type")
+	}
+	got := renderParts(t, doc)
+	if got["customXml/item1.xml"] != item || got["word/footnotes.xml"] != footnotes {
+		t.Errorf("Parts weren't added: %v", got)
+	}
+	for _, want := range []string{
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml" Target="/customXml/item1.xml">`,
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes" Target="footnotes.xml">`,
+	} {
+		if !strings.Contains(got["word/_rels/document.xml.rels"], want) {
+			t.Errorf("%s not found in %s", want, got["word/_rels/document.xml.rels"])
+		}
+	}
+	if want := `<Override PartName="/word/footnotes.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml">`; !strings.Contains(got[contentTypesXML], want) {
+		t.Errorf("%s not found in %s", want, got[contentTypesXML])
+	}
+}
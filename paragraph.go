@@ -0,0 +1,144 @@
+package docx
+
+import (
+	"strconv"
+)
+
+// Paragraph is a value which formats the paragraph where it's placed,
+// e.g. a warning in a bordered and shaded call-out box
+type Paragraph struct {
+	// Content is a value like in Dict
+	Content interface{}
+	// Style is an ID of a paragraph style, like "Quote"
+	Style string
+	Align Alignment
+	// Border is drawn around the paragraph, following paragraphs with the same border
+	// are put in the same box
+	Border *Border
+	// Shading is a background color like "FFF2CC"
+	Shading string
+	// Tabs replace tab stops of the paragraph, tabs are written as "\t" in the content
+	Tabs []TabStop
+}
+
+// TabStop is a position where text continues after a tab
+type TabStop struct {
+	// Position from the left margin (or right one for right-to-left paragraphs)
+	Position Length
+	// Align of text at the tab stop, TabLeft by default
+	Align TabAlign
+	// Leader fills the space before the tab stop, like dots in "Total ........ 10"
+	Leader TabLeader
+}
+
+// TabAlign is an alignment of text at a tab stop
+type TabAlign string
+
+// Alignments of tab stops
+const (
+	TabLeft    TabAlign = "left"
+	TabCenter  TabAlign = "center"
+	TabRight   TabAlign = "right"
+	TabDecimal TabAlign = "decimal"
+)
+
+// TabLeader is a character repeated before a tab stop
+type TabLeader string
+
+// Leaders of tab stops
+const (
+	LeaderNone       TabLeader = "none"
+	LeaderDot        TabLeader = "dot"
+	LeaderHyphen     TabLeader = "hyphen"
+	LeaderUnderscore TabLeader = "underscore"
+	LeaderMiddleDot  TabLeader = "middleDot"
+)
+
+// Border is a line drawn around a paragraph
+type Border struct {
+	// Style is a line style like "single", "double", "dashed" or "thick", "single" by default
+	Style string
+	// Width of the line in points, 0.5 by default
+	Width float64
+	// Color is a hex RGB value like "FF0000", automatic by default
+	Color string
+	// Space between the line and text in points
+	Space int
+}
+
+func (p Paragraph) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	value, err := toValue(p.Content)
+	if err != nil {
+		return nil, err
+	}
+	return value.runs(ctx, rPr)
+}
+
+// paragraphFormat is implemented by values which change formatting of their paragraph
+type paragraphFormat interface {
+	format(pPr *node)
+}
+
+func (p Paragraph) format(pPr *node) {
+	if p.Style != "" {
+		pPr.setChild(elem("w:pStyle", "w:val", p.Style), pPrOrder)
+	}
+	if p.Align != "" {
+		pPr.setChild(elem("w:jc", "w:val", string(p.Align)), pPrOrder)
+	}
+	if b := p.Border; b != nil {
+		style, width, color := b.Style, b.Width, b.Color
+		if style == "" {
+			style = "single"
+		}
+		if width <= 0 {
+			width = 0.5
+		}
+		if color == "" {
+			color = "auto"
+		}
+		pBdr := elem("w:pBdr")
+		for _, side := range []string{"w:top", "w:left", "w:bottom", "w:right"} {
+			// width is set in eighths of a point
+			pBdr.append(elem(side, "w:val", style, "w:sz", strconv.Itoa(int(width*8+0.5)),
+				"w:space", strconv.Itoa(b.Space), "w:color", color))
+		}
+		pPr.setChild(pBdr, pPrOrder)
+	}
+	if p.Shading != "" {
+		pPr.setChild(elem("w:shd", "w:val", "clear", "w:color", "auto", "w:fill", p.Shading), pPrOrder)
+	}
+	if len(p.Tabs) > 0 {
+		tabs := elem("w:tabs")
+		for _, tab := range p.Tabs {
+			align := tab.Align
+			if align == "" {
+				align = TabLeft
+			}
+			t := elem("w:tab", "w:val", string(align))
+			if tab.Leader != "" {
+				t.setAttr("w:leader", string(tab.Leader))
+			}
+			t.setAttr("w:pos", strconv.Itoa(int(tab.Position/Twip)))
+			tabs.append(t)
+		}
+		pPr.setChild(tabs, pPrOrder)
+	}
+}
+
+// formatParagraph applies formatting of a value to a paragraph
+func formatParagraph(p *node, value Value) {
+	f, ok := value.(paragraphFormat)
+	if !ok {
+		return
+	}
+	if pPr := p.child("w:pPr"); pPr != nil {
+		f.format(pPr)
+		return
+	}
+	pPr := elem("w:pPr")
+	f.format(pPr)
+	if len(pPr.children) > 0 {
+		p.insert(0, pPr)
+	}
+}
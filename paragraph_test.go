@@ -0,0 +1,46 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestParagraphFormat(t *testing.T) {
+	body := `<w:p><w:pPr><w:jc w:val="left"/></w:pPr><w:r><w:t>[warning]</w:t></w:r></w:p>` + p("[note]")
+	dict := Dict{
+		"warning": Paragraph{
+			Content: RichText{{Text: "Warning: ", Bold: true}, {Text: "hot surface"}},
+			Style:   "Quote",
+			Align:   AlignCenter,
+			Border:  &Border{Width: 1.5, Color: "C00000", Space: 4},
+			Shading: "FFF2CC",
+		},
+		"note": Paragraph{Content: "Note"},
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	border := func(side string) string {
+		return `<w:` + side + ` w:val="single" w:sz="12" w:space="4" w:color="C00000"></w:` + side + `>`
+	}
+	want := `<w:p><w:pPr><w:pStyle w:val="Quote"></w:pStyle><w:pBdr>` +
+		border("top") + border("left") + border("bottom") + border("right") + `</w:pBdr>` +
+		`<w:shd w:val="clear" w:color="auto" w:fill="FFF2CC"></w:shd><w:jc w:val="center"></w:jc></w:pPr>` +
+		`<w:r><w:rPr><w:b></w:b><w:bCs></w:bCs></w:rPr><w:t xml:space="preserve">Warning: </w:t></w:r>` +
+		`<w:r><w:rPr></w:rPr><w:t>hot surface</w:t></w:r></w:p>` +
+		p("Note")
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	dict := Dict{"total": Paragraph{
+		Content: "Total\t€10.00",
+		Tabs:    []TabStop{{Position: 8 * Centimeter, Align: TabRight, Leader: LeaderDot}, {Position: Inch}},
+	}}
+	got := renderBody(t, newTestDocx(t, p("[total]"), nil).ReplaceDict(dict))
+	want := `<w:p><w:pPr><w:tabs><w:tab w:val="right" w:leader="dot" w:pos="4535"></w:tab>` +
+		`<w:tab w:val="left" w:pos="1440"></w:tab></w:tabs></w:pPr>` +
+		`<w:r><w:t>Total</w:t><w:tab></w:tab><w:t>€10.00</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegisterPartial registers a named fragment, like a standard clause or a signature block, which
+// templates can include with [#include name]. The fragment is a DOCX file inserted like a
+// SubDocument, its styles and relationships are imported and it can include other fragments.
+// A fragment with a single paragraph can be also included inside text
+func (doc *Docx) RegisterPartial(name string, data []byte) *Docx {
+	if doc.partials == nil {
+		doc.partials = make(map[string]SubDocument)
+	}
+	doc.partials[strings.ToLower(strings.TrimSpace(name))] = SubDocument(data)
+	return doc
+}
+
+// includes returns [#include name] markers of a part in document order
+func (doc *Docx) includes(root *node) []marker {
+	var found []marker
+	for _, p := range root.find("w:p") {
+		para := newParagraph(p)
+		for _, ph := range doc.placeholders(para.text) {
+			m, _ := parseMarker(ph)
+			if m.kind != "include" || m.end || para.crossesField(ph.start, ph.end) {
+				continue
+			}
+			m.para = p
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// includePartials replaces include markers with registered fragments before other content
+// is rendered, so fragments can contain placeholders, blocks and repeated content
+func (ctx *renderContext) includePartials() error {
+	for depth := 0; ; depth++ {
+		includes := ctx.doc.includes(ctx.root)
+		if len(includes) == 0 {
+			return nil
+		}
+		if depth >= maxDepth {
+			return fmt.Errorf("Partials are nested too deeply")
+		}
+		// markers are replaced from the end, so positions of preceding markers stay valid
+		for i := len(includes) - 1; i >= 0; i-- {
+			if err := ctx.include(includes[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// include inserts a fragment in place of its marker, it replaces the whole paragraph when
+// there's nothing else
+func (ctx *renderContext) include(m marker) error {
+	fragment, ok := ctx.doc.partials[strings.ToLower(m.arg)]
+	if !ok {
+		return fmt.Errorf("Partial %s isn't registered", m.arg)
+	}
+	para := newParagraph(m.para)
+	if isMarkerOnly(m) {
+		blocks, err := fragment.blocks(ctx)
+		if err != nil {
+			return fmt.Errorf("Partial %s: %v", m.arg, err)
+		}
+		para.erase(m.ph.start, m.ph.end)
+		insertBlocks(m.para, blocks)
+		return nil
+	}
+	runs, err := fragment.runs(ctx, para.runProperties(m.ph.start))
+	if err != nil {
+		return fmt.Errorf("Partial %s: %v", m.arg, err)
+	}
+	para.replace(m.ph.start, m.ph.end, "")
+	para.insertRuns(m.ph.start, runs)
+	return nil
+}
@@ -0,0 +1,61 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPartials(t *testing.T) {
+	fragment := func(body string, parts map[string]string) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		if _, err := newTestDocx(t, body, parts).WriteTemplate(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	styles := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeStyles + `" Target="styles.xml"/></Relationships>`,
+		"word/styles.xml": `<w:styles xmlns:w="w">` +
+			`<w:style w:type="paragraph" w:styleId="Normal"><w:name w:val="Normal"/><w:rPr><w:sz w:val="40"/></w:rPr></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Clause"><w:name w:val="Clause"/><w:basedOn w:val="Normal"/>` +
+			`<w:next w:val="ClauseText"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="ClauseText"><w:name w:val="Clause Text"/></w:style>` +
+			`<w:style w:type="character" w:styleId="Unused"><w:name w:val="Unused"/></w:style>` +
+			`</w:styles>`,
+	}
+	nda := `<w:p><w:pPr><w:pStyle w:val="Clause"/></w:pPr><w:r><w:t>[party] keeps secrets.</w:t></w:r></w:p>` +
+		p("[#if signed]") + p("[#include signature]") + p("[/if]")
+	doc := newTestDocx(t, p("[#include NDA]")+p("Signed: [#include signature]."), map[string]string{
+		"word/_rels/document.xml.rels": styles["word/_rels/document.xml.rels"],
+		"word/styles.xml":              `<w:styles xmlns:w="w"><w:style w:type="paragraph" w:styleId="Normal"><w:name w:val="Normal"/></w:style></w:styles>`,
+	}).
+		RegisterPartial("nda", fragment(nda, styles)).
+		RegisterPartial("signature", fragment(p("[name], CEO"), nil)).
+		ReplaceDict(Dict{"party": "Bob", "name": "Alice", "signed": true})
+	got := renderParts(t, doc)
+	want := `<w:p><w:pPr><w:pStyle w:val="Clause"></w:pStyle></w:pPr><w:r><w:t>Bob keeps secrets.</w:t></w:r></w:p>` +
+		p("Alice, CEO") +
+		`<w:p><w:r><w:t xml:space="preserve">Signed: </w:t></w:r><w:r><w:t>Alice, CEO</w:t></w:r><w:r><w:t>.</w:t></w:r></w:p>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	// missing styles are copied, styles of the template are kept
+	for style, count := range map[string]int{`w:styleId="Normal"`: 1, `w:styleId="Clause"`: 1, `w:styleId="ClauseText"`: 1, `w:styleId="Unused"`: 0, `w:sz`: 0} {
+		if strings.Count(got["word/styles.xml"], style) != count {
+			t.Errorf("Expected %d of %s in %s", count, style, got["word/styles.xml"])
+		}
+	}
+
+	doc = newTestDocx(t, p("[#include missing]"), nil)
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of an unregistered partial")
+	}
+	doc = newTestDocx(t, p("[#include loop]"), nil).RegisterPartial("loop", fragment(p("[#include loop]"), nil))
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of a partial including itself")
+	}
+}
@@ -0,0 +1,163 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Part returns content of a part of the template, like "word/settings.xml",
+// or the content set by SetPart
+func (doc *Docx) Part(name string) ([]byte, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	if data, ok := doc.parts[name]; ok {
+		return data, nil
+	}
+	if doc.removed[name] {
+		return nil, &partError{name}
+	}
+	return newPkg(doc.zipReader).read(name)
+}
+
+// SetPart overrides content of a part of the template or adds a new part.
+// Parts are set before the document is rendered, so placeholders in document.xml set
+// this way are replaced too
+func (doc *Docx) SetPart(name string, data []byte) *Docx {
+	if doc.parts == nil {
+		doc.parts = make(map[string][]byte)
+	}
+	doc.parts[name] = data
+	delete(doc.removed, name)
+	return doc
+}
+
+// RemovePart removes a part of the template together with its content type,
+// relationships referring to it aren't changed
+func (doc *Docx) RemovePart(name string) *Docx {
+	if doc.removed == nil {
+		doc.removed = make(map[string]bool)
+	}
+	doc.removed[name] = true
+	delete(doc.parts, name)
+	return doc
+}
+
+// relTypes are types of relationships to parts with given content types
+var relTypes = map[string]string{
+	contentTypeHeader: relTypeHeader,
+	contentTypeFooter: relTypeFooter,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml": relTypeFootnotes,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.endnotes+xml":  relTypeEndnotes,
+	contentTypeComments:  relTypeComments,
+	contentTypeNumbering: relTypeNumbering,
+	contentTypeStyles:    relTypeStyles,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml": relTypeSettings,
+	contentTypeCustomProperties: relTypeCustomProperties,
+	"application/vnd.openxmlformats-officedocument.extended-properties+xml": relTypeExtendedProperties,
+	"application/vnd.openxmlformats-package.core-properties+xml":            "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties",
+	"application/xml": "http://schemas.openxmlformats.org/officeDocument/2006/relationships/customXml",
+}
+
+// addedPart is a part added by AddPart
+type addedPart struct {
+	name, contentType string
+	data              []byte
+	rel               relationship
+	source            string
+}
+
+// AddPart adds a new part to the archive, registers its content type and a relationship
+// from the part relatedFrom (like "word/document.xml", or "" for the package itself).
+// The type of the relationship follows from the content type, images are supported
+// as well. It returns ID of the relationship, which can be used to refer to the part
+func (doc *Docx) AddPart(name, contentType string, data []byte, relatedFrom string) (string, error) {
+	if doc.err != nil {
+		return "", doc.err
+	}
+	relType, ok := relTypes[contentType]
+	if !ok && strings.HasPrefix(contentType, "image/") {
+		relType, ok = relTypeImage, true
+	}
+	if !ok {
+		return "", fmt.Errorf("Unknown relationship type of content type %s", contentType)
+	}
+	p := newPkg(doc.zipReader)
+	if _, exists := doc.parts[name]; exists || p.has(name) {
+		return "", fmt.Errorf("Part %s already exists", name)
+	}
+	rels, err := p.relationships(relatedFrom)
+	if err != nil {
+		return "", err
+	}
+	// IDs of relationships added to the same part don't collide
+	ids := make(map[string]bool)
+	for _, rel := range rels.Relationships {
+		ids[rel.ID] = true
+	}
+	for _, added := range doc.added {
+		if added.source == relatedFrom {
+			ids[added.rel.ID] = true
+		}
+	}
+	id := ""
+	for i := len(ids) + 1; ; i++ {
+		if id = "rId" + strconv.Itoa(i); !ids[id] {
+			break
+		}
+	}
+	doc.added = append(doc.added, addedPart{
+		name:        name,
+		contentType: contentType,
+		data:        data,
+		rel:         relationship{ID: id, Type: relType, Target: relTarget(relatedFrom, name)},
+		source:      relatedFrom,
+	})
+	doc.SetPart(name, data)
+	return id, nil
+}
+
+// setParts puts parts set by SetPart and AddPart in the archive
+func (doc *Docx) setParts(p *pkg) error {
+	names := make([]string, 0, len(doc.parts))
+	for name := range doc.parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := p.set(name, doc.parts[name]); err != nil {
+			return err
+		}
+	}
+	for _, added := range doc.added {
+		ct, err := p.types()
+		if err != nil {
+			return err
+		}
+		ct.addOverride(added.name, added.contentType)
+		rels, err := p.relationships(added.source)
+		if err != nil {
+			return err
+		}
+		// relationships set by SetPart can contain the added one already
+		if _, ok := rels.target(added.rel.ID); ok {
+			continue
+		}
+		rels.Relationships = append(rels.Relationships, added.rel)
+		rels.modified = true
+	}
+	if len(doc.removed) == 0 {
+		return nil
+	}
+	ct, err := p.types()
+	if err != nil {
+		return err
+	}
+	for name := range doc.removed {
+		p.remove(name)
+		ct.removeOverride(name)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package docx
+
+import (
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// SetPicture puts an image into picture content controls with given tag (or title).
+// The placeholder graphic is replaced while the frame of the control keeps its size,
+// the image is fitted into the frame without changing its aspect ratio, so Width and Height
+// of the image are ignored
+func (doc *Docx) SetPicture(tag string, img Image) *Docx {
+	if doc.pictures == nil {
+		doc.pictures = make(map[string]Image)
+	}
+	doc.pictures[tag] = img
+	return doc
+}
+
+// setPictures replaces images of picture content controls of a part
+func (ctx *renderContext) setPictures() error {
+	if len(ctx.doc.pictures) == 0 {
+		return nil
+	}
+	for _, sdt := range ctx.root.find("w:sdt") {
+		img, ok := ctx.doc.pictures[sdtName(sdt)]
+		if !ok || sdtProperty(sdt, "w:picture") == nil {
+			continue
+		}
+		if err := ctx.setPicture(sdt, img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPicture points blips of a picture content control to the image
+func (ctx *renderContext) setPicture(sdt *node, img Image) error {
+	content := sdt.child("w:sdtContent")
+	if content == nil {
+		return nil
+	}
+	blips := content.find("a:blip")
+	if len(blips) == 0 {
+		return nil
+	}
+	for _, blip := range blips {
+		if err := img.setBlip(ctx, blip); err != nil {
+			return err
+		}
+		drawing := blip.ancestor("w:drawing")
+		if fill := blip.parent; fill != nil && fill.is("pic:blipFill") {
+			fitPicture(fill, img, extentOf(drawing))
+		}
+		if drawing != nil && img.Description != "" {
+			for _, docPr := range drawing.find("wp:docPr") {
+				docPr.setAttr("descr", img.Description)
+			}
+		}
+	}
+	if sdtPr := sdt.child("w:sdtPr"); sdtPr != nil {
+		sdtPr.removeChild("w:showingPlcHdr")
+	}
+	return nil
+}
+
+// extentOf returns the wp:extent element of an inline or floating drawing
+func extentOf(drawing *node) *node {
+	if drawing == nil {
+		return nil
+	}
+	for _, c := range drawing.children {
+		if c.is("wp:inline") || c.is("wp:anchor") {
+			return c.child("wp:extent")
+		}
+	}
+	return nil
+}
+
+// fitPicture stretches the image into the frame with margins which keep its aspect ratio,
+// crop of the placeholder graphic is replaced with the crop of the image
+func fitPicture(fill *node, img Image, extent *node) {
+	img.setCrop(fill)
+	fill.removeChild("a:tile")
+	stretch := fill.child("a:stretch")
+	if stretch == nil {
+		stretch = elem("a:stretch")
+		fill.append(stretch)
+	}
+	stretch.children = nil
+	rect := elem("a:fillRect")
+	stretch.append(rect)
+	if extent == nil {
+		return
+	}
+	cx, err1 := strconv.ParseFloat(extent.attrValue("cx"), 64)
+	cy, err2 := strconv.ParseFloat(extent.attrValue("cy"), 64)
+	width, height, err := img.croppedSize()
+	if err1 != nil || err2 != nil || err != nil || cx <= 0 || cy <= 0 {
+		return
+	}
+	// margins are in thousandths of a percent of the frame
+	w, h := float64(width), float64(height)
+	if ratio := h * cx / (w * cy); ratio < 1 {
+		margin := strconv.Itoa(int((1 - ratio) * 50000))
+		rect.setAttr("t", margin)
+		rect.setAttr("b", margin)
+	} else if ratio > 1 {
+		margin := strconv.Itoa(int((1 - 1/ratio) * 50000))
+		rect.setAttr("l", margin)
+		rect.setAttr("r", margin)
+	}
+}
+
+// ReplaceImage puts a PNG, JPEG or other picture read from r in place of a placeholder with
+// given name (like "[photo]") and of existing pictures with the name or the title of alternative
+// text. opts are options of the image like Width and Height, its Data is read from r. Existing
+// pictures get the size of opts, or keep their frames and the image is fitted into them
+func (doc *Docx) ReplaceImage(name string, r io.Reader, opts Image) *Docx {
+	if doc.err != nil {
+		return doc
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		doc.err = err
+		return doc
+	}
+	opts.Data = data
+	if doc.values == nil {
+		doc.values = make(Dict)
+	}
+	doc.values[name] = opts
+	if doc.images == nil {
+		doc.images = make(map[string]Image)
+	}
+	name = strings.TrimPrefix(strings.TrimSuffix(name, doc.closingBracket), doc.openingBracket)
+	doc.images[name] = opts
+	return doc
+}
+
+// replaceImages replaces pictures of a part which have names or titles of images
+func (ctx *renderContext) replaceImages() error {
+	if len(ctx.doc.images) == 0 {
+		return nil
+	}
+	for _, docPr := range ctx.root.find("wp:docPr") {
+		img, ok := ctx.doc.images[docPr.attrValue("name")]
+		if !ok {
+			img, ok = ctx.doc.images[docPr.attrValue("title")]
+		}
+		drawing := docPr.ancestor("w:drawing")
+		if !ok || drawing == nil {
+			continue
+		}
+		for _, blip := range drawing.find("a:blip") {
+			if err := img.setBlip(ctx, blip); err != nil {
+				return err
+			}
+			fill := blip.parent
+			if fill == nil || !fill.is("pic:blipFill") {
+				continue
+			}
+			if img.Width == 0 && img.Height == 0 {
+				fitPicture(fill, img, extentOf(drawing))
+				continue
+			}
+			if err := resizePicture(drawing, fill, img); err != nil {
+				return err
+			}
+		}
+		if img.Description != "" {
+			docPr.setAttr("descr", img.Description)
+		}
+	}
+	return nil
+}
+
+// resizePicture sets the size of the image on the frame of a picture
+func resizePicture(drawing, fill *node, img Image) error {
+	width, height, err := img.size()
+	if err != nil {
+		return err
+	}
+	cx, cy := strconv.FormatInt(int64(width), 10), strconv.FormatInt(int64(height), 10)
+	if extent := extentOf(drawing); extent != nil {
+		extent.setAttr("cx", cx)
+		extent.setAttr("cy", cy)
+	}
+	if pic := fill.parent; pic != nil {
+		for _, ext := range pic.find("a:ext") {
+			if ext.parent.is("a:xfrm") {
+				ext.setAttr("cx", cx)
+				ext.setAttr("cy", cy)
+			}
+		}
+	}
+	img.setCrop(fill)
+	fill.removeChild("a:tile")
+	fill.removeChild("a:stretch")
+	stretch := elem("a:stretch")
+	stretch.append(elem("a:fillRect"))
+	fill.append(stretch)
+	return nil
+}
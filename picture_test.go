@@ -0,0 +1,76 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetPicture(t *testing.T) {
+	drawing := `<w:r><w:drawing><wp:inline><wp:extent cx="1000" cy="1000"/><wp:docPr id="1" name="Picture 1"/>` +
+		`<a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="rId9"/><a:srcRect l="10"/>` +
+		`<a:stretch><a:fillRect/></a:stretch></pic:blipFill><pic:spPr><a:xfrm><a:ext cx="1000" cy="1000"/></a:xfrm>` +
+		`</pic:spPr></pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`
+	body := `<w:p>` + sdt(`<w:tag w:val="photo"/><w:showingPlcHdr/><w:picture/>`, drawing) + `</w:p>` +
+		`<w:p>` + sdt(`<w:tag w:val="other"/><w:picture/>`, drawing) + `</w:p>`
+	doc := newTestDocx(t, body, nil).SetPicture("photo", Image{Data: testPNG(t, 20, 10).Bytes(), Description: "Portrait"})
+	parts := renderParts(t, doc)
+	got := parts[documentXML]
+	photo := got[strings.Index(got, `"photo"`):strings.Index(got, `"other"`)]
+	for _, want := range []string{
+		`<wp:extent cx="1000" cy="1000">`,
+		`<wp:docPr id="1" name="Picture 1" descr="Portrait">`,
+		`<pic:blipFill><a:blip r:embed="rId1"></a:blip><a:stretch><a:fillRect t="25000" b="25000"></a:fillRect></a:stretch></pic:blipFill>`,
+		`<a:ext cx="1000" cy="1000">`,
+	} {
+		if !strings.Contains(photo, want) {
+			t.Errorf("got: %s\nwant: %s", photo, want)
+		}
+	}
+	if strings.Contains(photo, "showingPlcHdr") {
+		t.Errorf("Placeholder flag wasn't removed: %s", photo)
+	}
+	if !strings.Contains(got[strings.Index(got, `"other"`):], `r:embed="rId9"`) {
+		t.Errorf("Other picture was changed: %s", got)
+	}
+	if _, ok := parts["word/media/image1.png"]; !ok {
+		t.Error("Image wasn't stored")
+	}
+}
+
+func TestReplaceImage(t *testing.T) {
+	drawing := func(name string) string {
+		return `<w:r><w:drawing><wp:inline><wp:extent cx="1000" cy="1000"/><wp:docPr id="1" name="` + name + `"/>` +
+			`<a:graphic><a:graphicData><pic:pic><pic:blipFill><a:blip r:embed="rId9"/>` +
+			`<a:stretch><a:fillRect/></a:stretch></pic:blipFill><pic:spPr><a:xfrm><a:ext cx="1000" cy="1000"/></a:xfrm>` +
+			`</pic:spPr></pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`
+	}
+	body := p("[photo]") + `<w:p>` + drawing("photo") + `</w:p>` + `<w:p>` + drawing("logo") + `</w:p>`
+	doc := newTestDocx(t, body, nil).
+		ReplaceImage("[photo]", testPNG(t, 20, 10), Image{Width: 2 * Centimeter}).
+		ReplaceImage("logo", testPNG(t, 20, 10), Image{})
+	parts := renderParts(t, doc)
+	got := parts[documentXML]
+	for _, want := range []string{
+		// the placeholder and the named picture get the size of the image
+		`<wp:extent cx="720000" cy="360000"></wp:extent>`,
+		`<wp:extent cx="720000" cy="360000"></wp:extent><wp:docPr id="1" name="photo"></wp:docPr>`,
+		`<a:ext cx="720000" cy="360000"></a:ext>`,
+		// the logo keeps its frame
+		`<a:stretch><a:fillRect t="25000" b="25000"></a:fillRect></a:stretch>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+	if strings.Contains(got, "[photo]") || strings.Contains(got, `r:embed="rId9"`) {
+		t.Errorf("Images weren't replaced: %s", got)
+	}
+	for _, name := range []string{"word/media/image1.png", "word/media/image2.png", "word/media/image3.png"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Image %s wasn't stored", name)
+		}
+	}
+	if !strings.Contains(parts[contentTypesXML], `Extension="png"`) {
+		t.Errorf("Content type wasn't added: %s", parts[contentTypesXML])
+	}
+}
@@ -0,0 +1,214 @@
+package docx
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PIIPattern is a kind of personal data found by ScanPII, like e-mail addresses
+type PIIPattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// DefaultPIIPatterns are used when no patterns are given: e-mail addresses, phone numbers,
+// IBANs and US social security numbers. National IDs of other countries can be added
+var DefaultPIIPatterns = []PIIPattern{
+	{"email", regexp.MustCompile(`[\pL\pN._%+-]+@[\pL\pN.-]+\.\pL{2,}`)},
+	{"iban", regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`)},
+	{"ssn", regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	{"phone", regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?|\b\d{2,4}[ .-])\d{3,4}[ .-]\d{3,4}\b`)},
+}
+
+// PIIMatch is a potential personal data found in a document
+type PIIMatch struct {
+	Pattern string `json:"pattern"`
+	Text    string `json:"text"`
+	// Part is the name of the part, like "word/document.xml", "word/comments.xml" or "docProps/core.xml"
+	Part string `json:"part"`
+	// Paragraph is the position of the paragraph among paragraphs of the part starting at 0,
+	// it's -1 for matches in metadata and attributes
+	Paragraph int `json:"paragraph"`
+	// Property is an element of metadata (like "dc:creator") or an attribute (like "w:author")
+	// where the match was found
+	Property string `json:"property,omitempty"`
+}
+
+// piiAuthor is the pattern of names of people who wrote or changed the document
+const piiAuthor = "author"
+
+// piiAttributes are attributes with names of people, like authors of comments and revisions
+var piiAttributes = []string{"w:author", "w:initials"}
+
+// piiProperties are document properties with names of people
+var piiProperties = map[string]bool{"dc:creator": true, "cp:lastModifiedBy": true, "Manager": true}
+
+// ScanPII reports potential personal data in all parts of the template: the body, headers,
+// footers, notes, comments and document properties. Names of authors of the document,
+// comments and revisions are reported with "author" pattern.
+// DefaultPIIPatterns are used if no patterns are given
+func (doc *Docx) ScanPII(patterns ...PIIPattern) ([]PIIMatch, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	var matches []PIIMatch
+	err := doc.forEachTextPart(func(name string, root *node) (bool, error) {
+		matches = append(matches, piiScanner{part: name, patterns: piiPatterns(patterns)}.scan(root)...)
+		return false, nil
+	})
+	return matches, err
+}
+
+// RedactPII replaces potential personal data found like by ScanPII with a replacement,
+// like "[redacted]". Redacted parts override parts of the template, which can be saved with WriteTemplate
+func (doc *Docx) RedactPII(replacement string, patterns ...PIIPattern) *Docx {
+	if doc.err != nil {
+		return doc
+	}
+	doc.err = doc.forEachTextPart(func(name string, root *node) (bool, error) {
+		s := piiScanner{part: name, patterns: piiPatterns(patterns), redact: true, replacement: replacement}
+		return len(s.scan(root)) > 0, nil
+	})
+	return doc
+}
+
+func piiPatterns(patterns []PIIPattern) []PIIPattern {
+	if len(patterns) == 0 {
+		return DefaultPIIPatterns
+	}
+	return patterns
+}
+
+// forEachTextPart calls fn for all XML parts, parts which fn changes override parts of the template
+func (doc *Docx) forEachTextPart(fn func(name string, root *node) (bool, error)) error {
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return err
+	}
+	for _, name := range p.names {
+		if !strings.HasSuffix(name, ".xml") || name == contentTypesXML {
+			continue
+		}
+		root, err := p.parsePart(name)
+		if err != nil {
+			return err
+		}
+		changed, err := fn(name, root)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		data, err := root.bytes()
+		if err != nil {
+			return err
+		}
+		doc.SetPart(name, data)
+	}
+	return nil
+}
+
+// piiScanner finds matches of patterns in text of a part and optionally replaces them
+type piiScanner struct {
+	part        string
+	patterns    []PIIPattern
+	redact      bool
+	replacement string
+}
+
+// piiSpan is a match in a text
+type piiSpan struct {
+	pattern    string
+	start, end int
+}
+
+// spans returns matches of all patterns in a text ordered by position, overlapping matches
+// of later patterns are dropped
+func (s piiScanner) spans(text string) []piiSpan {
+	var spans []piiSpan
+	for _, pattern := range s.patterns {
+	matches:
+		for _, m := range pattern.Regexp.FindAllStringIndex(text, -1) {
+			for _, span := range spans {
+				if m[0] < span.end && span.start < m[1] {
+					continue matches
+				}
+			}
+			spans = append(spans, piiSpan{pattern.Name, m[0], m[1]})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans
+}
+
+func (s piiScanner) scan(root *node) []PIIMatch {
+	var matches []PIIMatch
+	if strings.HasPrefix(s.part, "word/") {
+		for i, p := range root.find("w:p") {
+			para := newParagraph(p)
+			spans := s.spans(para.text)
+			for _, span := range spans {
+				matches = append(matches, PIIMatch{Pattern: span.pattern, Text: para.text[span.start:span.end], Part: s.part, Paragraph: i})
+			}
+			if s.redact {
+				for j := len(spans) - 1; j >= 0; j-- {
+					para.replace(spans[j].start, spans[j].end, s.replacement)
+				}
+			}
+		}
+	}
+	root.walk(func(n *node) bool {
+		for _, name := range piiAttributes {
+			if n.hasAttr(name) {
+				if redacted, ok := s.author(n.attrValue(name), name, &matches); ok {
+					n.setAttr(name, redacted)
+				}
+			}
+		}
+		// metadata are texts of elements without child elements
+		if n.tag == "" || len(n.children) == 0 || strings.HasPrefix(s.part, "word/") {
+			return true
+		}
+		for _, c := range n.children {
+			if c.tag != "" {
+				return true
+			}
+		}
+		scan := s.scanText
+		if piiProperties[n.tag] {
+			scan = s.author
+		}
+		if redacted, ok := scan(n.text(), n.tag, &matches); ok {
+			n.setText(redacted)
+		}
+		return true
+	})
+	return matches
+}
+
+// scanText adds matches of a metadata text or an attribute, ok is true when the text was redacted
+func (s piiScanner) scanText(text, property string, matches *[]PIIMatch) (redacted string, ok bool) {
+	spans := s.spans(text)
+	for _, span := range spans {
+		*matches = append(*matches, PIIMatch{Pattern: span.pattern, Text: text[span.start:span.end], Part: s.part, Paragraph: -1, Property: property})
+	}
+	if !s.redact || len(spans) == 0 {
+		return text, false
+	}
+	for i := len(spans) - 1; i >= 0; i-- {
+		text = text[:spans[i].start] + s.replacement + text[spans[i].end:]
+	}
+	return text, true
+}
+
+// author adds a match of a name of a person, the whole text is redacted
+func (s piiScanner) author(name, property string, matches *[]PIIMatch) (string, bool) {
+	if strings.TrimSpace(name) == "" {
+		return name, false
+	}
+	*matches = append(*matches, PIIMatch{Pattern: piiAuthor, Text: name, Part: s.part, Paragraph: -1, Property: property})
+	return s.replacement, s.redact
+}
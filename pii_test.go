@@ -0,0 +1,73 @@
+package docx
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScanPII(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">Write to jane.doe@</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>example.com</w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve"> or call +1 555 123 4567.</w:t></w:r></w:p>` +
+		p("Invoice 2024-001 of 1 500 EUR") +
+		`<w:p><w:ins w:id="1" w:author="John Smith"><w:r><w:t>SSN 123-45-6789, IBAN DE89 3704 0044 0532 0130 00</w:t></w:r></w:ins></w:p>`
+	parts := map[string]string{
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="cp" xmlns:dc="dc"><dc:title>Contact bob@example.org</dc:title>` +
+			`<dc:creator>Jane Doe</dc:creator><cp:lastModifiedBy></cp:lastModifiedBy></cp:coreProperties>`,
+		"word/header1.xml": `<w:hdr xmlns:w="w">` + p("Fax (030) 1234 5678") + `</w:hdr>`,
+	}
+	matches, err := newTestDocx(t, body, parts).ScanPII()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[PIIMatch]bool{
+		{Pattern: "email", Text: "jane.doe@example.com", Part: documentXML, Paragraph: 0}:                           true,
+		{Pattern: "phone", Text: "+1 555 123 4567", Part: documentXML, Paragraph: 0}:                                true,
+		{Pattern: "ssn", Text: "123-45-6789", Part: documentXML, Paragraph: 2}:                                      true,
+		{Pattern: "iban", Text: "DE89 3704 0044 0532 0130 00", Part: documentXML, Paragraph: 2}:                     true,
+		{Pattern: "author", Text: "John Smith", Part: documentXML, Paragraph: -1, Property: "w:author"}:             true,
+		{Pattern: "email", Text: "bob@example.org", Part: "docProps/core.xml", Paragraph: -1, Property: "dc:title"}: true,
+		{Pattern: "author", Text: "Jane Doe", Part: "docProps/core.xml", Paragraph: -1, Property: "dc:creator"}:     true,
+		{Pattern: "phone", Text: "(030) 1234 5678", Part: "word/header1.xml", Paragraph: 0}:                         true,
+	}
+	for _, m := range matches {
+		if !want[m] {
+			t.Errorf("Unexpected match %+v", m)
+		}
+		delete(want, m)
+	}
+	for m := range want {
+		t.Errorf("Missing match %+v", m)
+	}
+
+	patterns := []PIIPattern{{"invoice", regexp.MustCompile(`\d{4}-\d{3}\b`)}}
+	if matches, _ := newTestDocx(t, body, nil).ScanPII(patterns...); len(matches) != 2 || matches[0].Text != "2024-001" {
+		t.Errorf("Unexpected matches of a custom pattern: %+v", matches)
+	}
+
+	doc := newTestDocx(t, body, parts).RedactPII("[redacted]")
+	var buf bytes.Buffer
+	if _, err := doc.WriteTemplate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	redacted, err := New(bytes.NewReader(buf.Bytes()), int64(buf.Len())).ScanPII()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// names of authors are replaced too, but they're still authors
+	for _, m := range redacted {
+		if m.Text != "[redacted]" {
+			t.Errorf("Data wasn't redacted: %+v", m)
+		}
+	}
+	got := renderParts(t, New(bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+	for part, want := range map[string]string{
+		documentXML:         `<w:t xml:space="preserve">Write to [redacted]</w:t></w:r><w:r><w:t xml:space="preserve"> or call [redacted].</w:t>`,
+		"docProps/core.xml": `<dc:title>Contact [redacted]</dc:title><dc:creator>[redacted]</dc:creator>`,
+	} {
+		if !strings.Contains(got[part], want) {
+			t.Errorf("got: %s\nwant: %s", got[part], want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package docx
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// Job is a document rendered by a Pipeline
+type Job struct {
+	// Template is rendered with all its options, one template can be used by many jobs
+	Template *Docx
+	// Dict replaces the dictionary of the template unless it's nil
+	Dict        Dict
+	Destination io.Writer
+}
+
+// JobResult is a result of a job, Err is nil if the document was written
+type JobResult struct {
+	Job      Job
+	Written  int64
+	Warnings []error
+	Err      error
+}
+
+// Pipeline renders jobs with a bounded number of workers
+type Pipeline struct {
+	workers int
+}
+
+// NewPipeline creates a pipeline rendering at most given number of documents at once,
+// the number of CPUs is used if workers isn't positive
+func NewPipeline(workers int) *Pipeline {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Pipeline{workers: workers}
+}
+
+// Run renders all jobs and returns their results in the same order,
+// a failed job doesn't stop other ones
+func (p *Pipeline) Run(jobs []Job) []JobResult {
+	results := make([]JobResult, len(jobs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers && i < len(jobs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = runJob(jobs[i])
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// runJob renders a copy of the template, so jobs sharing it don't affect each other
+func runJob(job Job) JobResult {
+	if job.Template == nil || job.Destination == nil {
+		return JobResult{Job: job, Err: errors.New("Job needs a template and a destination")}
+	}
+	doc := job.Template.withDict(job.Dict)
+	n, err := doc.WriteTo(job.Destination)
+	return JobResult{Job: job, Written: n, Warnings: doc.warnings, Err: err}
+}
@@ -0,0 +1,42 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	template := newTestDocx(t, p("[a] [b]"), nil).ReplaceDict(Dict{"b": "default"})
+	jobs := make([]Job, 20)
+	for i := range jobs {
+		jobs[i] = Job{Template: template, Dict: Dict{"a": i}, Destination: new(bytes.Buffer)}
+	}
+	jobs[3].Dict = nil
+	jobs = append(jobs, Job{Template: New(bytes.NewReader(nil), 0), Destination: new(bytes.Buffer)})
+	results := NewPipeline(4).Run(jobs)
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results of %d jobs", len(results), len(jobs))
+	}
+	for i, r := range results[:20] {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		out := r.Job.Destination.(*bytes.Buffer)
+		if r.Written != int64(out.Len()) {
+			t.Errorf("job %d: written %d of %d bytes", i, r.Written, out.Len())
+		}
+		body := renderBody(t, New(bytes.NewReader(out.Bytes()), int64(out.Len())))
+		want := fmt.Sprintf("<w:t>%d [b]</w:t>", i)
+		if i == 3 {
+			want = "<w:t>[a] default</w:t>"
+		}
+		if !strings.Contains(body, want) {
+			t.Errorf("job %d: got %s, want %s", i, body, want)
+		}
+	}
+	if results[20].Err == nil {
+		t.Error("Expected an error of an invalid template")
+	}
+}
@@ -0,0 +1,96 @@
+package docx
+
+import (
+	"strings"
+)
+
+// Placeholders returns names of variables used by placeholders of the template in document order,
+// like "customer.name" of [customer.name|upper], each name is listed once. Variables of conditions
+// of blocks are included, filters aren't a part of names. The body goes first, followed by other
+// parts like headers, footers and notes. Building blocks aren't included
+func (doc *Docx) Placeholders() ([]string, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	if !p.has(documentXML) {
+		return nil, &partError{documentXML}
+	}
+	root, err := p.parsePart(documentXML)
+	if err != nil {
+		return nil, err
+	}
+	// directives of comments become markers, the comments are removed from the comments part
+	ctx := newRenderContext(doc, p, documentXML, root)
+	if err := ctx.commentDirectives(); err != nil {
+		return nil, err
+	}
+	doc.mustacheTags(root)
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		// {{.}} is an item of a Mustache section
+		if name != "" && name != "." && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	doc.variables(root, add)
+	for _, name := range p.names {
+		if name == documentXML || !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") ||
+			strings.HasPrefix(name, "word/glossary/") {
+			continue
+		}
+		data, err := p.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(string(data), doc.openingBracket) {
+			continue
+		}
+		root, err := scan(data)
+		if err != nil {
+			return nil, err
+		}
+		doc.mustacheTags(root)
+		doc.variables(root, add)
+	}
+	return names, nil
+}
+
+// variables calls fn for variables of placeholders of a part
+func (doc *Docx) variables(root *node, fn func(name string)) {
+	for _, par := range root.find("w:p") {
+		para := newParagraph(par)
+		for _, ph := range doc.placeholders(para.text) {
+			if para.crossesField(ph.start, ph.end) {
+				continue
+			}
+			if m, ok := parseMarker(ph); m.kind != "" {
+				// conditions and collections of blocks are variables, names of partials aren't
+				if ok && m.kind == "each" && !m.end {
+					if name, _, err := parseLoopOptions(m.arg); err == nil {
+						fn(name)
+					}
+				} else if ok && !m.end {
+					if e, err := parseExpr(m.arg); err == nil {
+						exprVariables(e, "", func(name, _ string) { fn(name) })
+					}
+				}
+				continue
+			}
+			name, _ := parseFilters(ph.name)
+			if _, path, ok := parseAggregate(name); ok {
+				fn(path)
+			} else if e, err := parseExpr(name); err == nil {
+				exprVariables(e, "", func(name, _ string) { fn(name) })
+			} else {
+				fn(name)
+			}
+		}
+	}
+}
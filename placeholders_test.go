@@ -0,0 +1,22 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	body := p("Dear [customer.name|upper],") + p("[#if total > limit]") + p("[total|money:EUR] of [SUM items.price]") +
+		p("[/if]") + p("[#include signature]") + p("[customer.name] on [date]")
+	doc := newTestDocx(t, body, map[string]string{
+		"word/header1.xml": `<w:hdr xmlns:w="w">` + p("[company]") + p("[date]") + `</w:hdr>`,
+	})
+	got, err := doc.Placeholders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"customer.name", "total", "limit", "items.price", "date", "company"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
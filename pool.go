@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+)
+
+// Pools of scratch resources reused by renders, so servers rendering many documents
+// at once don't allocate them over and over
+
+var writerPool = sync.Pool{
+	New: func() interface{} { return bufio.NewWriterSize(nil, 32<<10) },
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+var scannerPool = sync.Pool{
+	New: func() interface{} { return &scanner{names: make(map[string]string)} },
+}
+
+// maxPooledBuffer is a limit of buffers put back to the pool,
+// so a single huge document doesn't keep its memory forever
+const maxPooledBuffer = 4 << 20
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+func getScanner(data []byte) *scanner {
+	s := scannerPool.Get().(*scanner)
+	s.data, s.pos, s.line = data, 0, 1
+	return s
+}
+
+func putScanner(s *scanner) {
+	// names are kept, they are the same in all documents
+	if len(s.names) > 4096 {
+		s.names = make(map[string]string)
+	}
+	s.data, s.nodes = nil, nil
+	scannerPool.Put(s)
+}
@@ -0,0 +1,86 @@
+package docx
+
+import (
+	"regexp"
+)
+
+// regexpReplacement is a regular expression replaced by ReplaceRegexp
+type regexpReplacement struct {
+	re   *regexp.Regexp
+	repl string
+	// fn computes replacements of ReplaceRegexpFunc from submatches
+	fn func(submatches []string) string
+}
+
+// ReplaceRegexp replaces matches of a regular expression in text of paragraphs of the body,
+// headers, footers and notes after placeholders are replaced. Text split into several runs is matched as a whole and
+// the replacement keeps formatting of the run where the match starts. repl can refer to
+// submatches like $1 or ${name}, as in Regexp.Expand. Matches which cross bounds of fields are skipped
+func (doc *Docx) ReplaceRegexp(re *regexp.Regexp, repl string) *Docx {
+	doc.regexps = append(doc.regexps, regexpReplacement{re: re, repl: repl})
+	return doc
+}
+
+// ReplaceRegexpFunc replaces matches of a regular expression with results of a function
+// called with the match and its submatches, like ReplaceRegexp. It's called in document order,
+// e.g. to number references like [ORDER-17] by the first appearance
+func (doc *Docx) ReplaceRegexpFunc(re *regexp.Regexp, fn func(submatches []string) string) *Docx {
+	doc.regexps = append(doc.regexps, regexpReplacement{re: re, fn: fn})
+	return doc
+}
+
+// replacement returns text which replaces a match
+func (r regexpReplacement) replacement(text string, m []int) string {
+	if r.fn == nil {
+		return string(r.re.ExpandString(nil, r.repl, text, m))
+	}
+	submatches := make([]string, len(m)/2)
+	for i := range submatches {
+		if m[2*i] >= 0 {
+			submatches[i] = text[m[2*i]:m[2*i+1]]
+		}
+	}
+	return r.fn(submatches)
+}
+
+// replaceRegexps replaces regular expressions in all paragraphs of a part
+func (ctx *renderContext) replaceRegexps() {
+	if len(ctx.doc.regexps) == 0 {
+		return
+	}
+	for _, p := range ctx.root.find("w:p") {
+		for _, r := range ctx.doc.regexps {
+			para := newParagraph(p)
+			var matches [][]int
+			var replacements []string
+			for _, m := range r.re.FindAllStringSubmatchIndex(para.text, -1) {
+				if !para.crossesField(m[0], m[1]) {
+					matches = append(matches, m)
+					replacements = append(replacements, r.replacement(para.text, m))
+				}
+			}
+			// replace from the end, so positions of preceding matches stay valid
+			for i := len(matches) - 1; i >= 0; i-- {
+				if m := matches[i]; m[0] == m[1] {
+					para.insertText(m[0], replacements[i])
+				} else {
+					para.replace(m[0], m[1], replacements[i])
+				}
+			}
+		}
+	}
+}
+
+// insertText puts text at a position, into the run which contains it
+func (para *paragraph) insertText(pos int, s string) {
+	seg := para.segmentAt(pos)
+	if seg == nil || s == "" {
+		return
+	}
+	text := seg.t.text()
+	offset := pos - seg.start
+	if offset > len(text) {
+		offset = len(text)
+	}
+	setRunText(seg.t, text[:offset]+s+text[offset:])
+}
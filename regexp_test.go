@@ -0,0 +1,51 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReplaceRegexp(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">Call 555-</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>1234</w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve"> or 555-9876</w:t></w:r></w:p>` +
+		p("Dear [name]  ,") +
+		`<w:p><w:fldSimple w:instr="PAGE"><w:r><w:t>555-</w:t></w:r></w:fldSimple><w:r><w:t>0000</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).
+		ReplaceDict(Dict{"name": "John"}).
+		ReplaceRegexp(regexp.MustCompile(`(\d{3})-(\d{4})`), "($1) $2").
+		ReplaceRegexp(regexp.MustCompile(`\s+,`), ",").
+		ReplaceRegexp(regexp.MustCompile(`^Dear`), "☞ $0").
+		ReplaceRegexp(regexp.MustCompile(`$`), ".")
+	got := renderBody(t, doc)
+	want := `<w:p><w:r><w:t xml:space="preserve">Call (555) 1234</w:t></w:r>` +
+		`<w:r><w:t xml:space="preserve"> or (555) 9876.</w:t></w:r></w:p>` +
+		p("☞ Dear John,.") +
+		`<w:p><w:fldSimple w:instr="PAGE"><w:r><w:t>555-</w:t></w:r></w:fldSimple><w:r><w:t>0000.</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestReplaceRegexpFunc(t *testing.T) {
+	body := p("See [ORDER-17], [ORDER-3] and [ORDER-17].")
+	header := `<w:hdr xmlns:w="w">` + p("Orders [ORDER-3]") + `</w:hdr>`
+	numbers := make(map[string]int)
+	doc := newTestDocx(t, body, map[string]string{"word/header1.xml": header}).
+		ReplaceRegexpFunc(regexp.MustCompile(`\[ORDER-(\d+)\]`), func(m []string) string {
+			if _, ok := numbers[m[1]]; !ok {
+				numbers[m[1]] = len(numbers) + 1
+			}
+			return fmt.Sprintf("order %d", numbers[m[1]])
+		})
+	got := renderParts(t, doc)
+	for part, want := range map[string]string{
+		documentXML:        p("See order 1, order 2 and order 1."),
+		"word/header1.xml": p("Orders order 2"),
+	} {
+		if !strings.Contains(got[part], want) {
+			t.Errorf("%s:\n got: %s\nwant: %s", part, got[part], want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// RenamePlaceholders changes names of variables in all parts of the template, like headers,
+// footers or footnotes, e.g. to move templates to a new naming convention. Variables are renamed
+// in placeholders, expressions and conditions ([total*2], [#if total > 0]) keeping their filters,
+// a name with a dot renames nested values too, so "customer" renames [customer.name] to [client.name].
+// Tags of repeating sections and text content controls are renamed as well.
+// Renamed parts override parts of the template, which can be saved with WriteTemplate
+func (doc *Docx) RenamePlaceholders(names map[string]string) *Docx {
+	if doc.err != nil || len(names) == 0 {
+		return doc
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if doc.err = doc.setParts(p); doc.err != nil {
+		return doc
+	}
+	r := &renamer{doc: doc, names: names}
+	for _, name := range p.names {
+		if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		var data []byte
+		if data, doc.err = r.renamePart(p, name); doc.err != nil {
+			return doc
+		}
+		if data != nil {
+			doc.SetPart(name, data)
+		}
+	}
+	return doc
+}
+
+// renamer renames variables of a template
+type renamer struct {
+	doc   *Docx
+	names map[string]string
+}
+
+// renamePart returns content of a part with renamed variables, or nil if nothing changed
+func (r *renamer) renamePart(p *pkg, name string) ([]byte, error) {
+	data, err := p.read(name)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(data, []byte(r.doc.openingBracket)) && !bytes.Contains(data, []byte("<w:sdt>")) {
+		return nil, nil
+	}
+	root, err := scan(data)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, par := range root.find("w:p") {
+		para := newParagraph(par)
+		found := r.doc.placeholders(para.text)
+		for i := len(found) - 1; i >= 0; i-- {
+			ph := found[i]
+			if para.crossesField(ph.start, ph.end) {
+				continue
+			}
+			if renamed := r.renameVariables(ph.name); renamed != ph.name {
+				para.replace(ph.start, ph.end, r.doc.openingBracket+renamed+r.doc.closingBracket)
+				changed = true
+			}
+		}
+	}
+	for _, sdt := range root.find("w:sdt") {
+		for _, tag := range []string{"w:tag", "w:alias"} {
+			el := sdtProperty(sdt, tag)
+			if el == nil {
+				continue
+			}
+			if renamed := r.renameVariable(el.attrValue("w:val")); renamed != el.attrValue("w:val") {
+				el.setAttr("w:val", renamed)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	return root.bytes()
+}
+
+// renameVariables renames variables in a text between brackets, filters and ends of blocks are kept
+func (r *renamer) renameVariables(name string) string {
+	if m, ok := parseMarker(placeholder{name: name}); ok {
+		if m.arg == "" {
+			return name
+		}
+		i := strings.LastIndex(name, m.arg)
+		return name[:i] + r.renameExpr(m.arg) + name[i+len(m.arg):]
+	}
+	expression, filters := name, ""
+	if i := strings.IndexByte(name, '|'); i != -1 {
+		expression, filters = name[:i], name[i:]
+	}
+	// texts which aren't expressions, like "Project Year", are renamed as a whole
+	if trimmed := strings.TrimSpace(expression); r.names[trimmed] != "" {
+		return strings.Replace(expression, trimmed, r.names[trimmed], 1) + filters
+	}
+	return r.renameExpr(expression) + filters
+}
+
+// renameExpr renames identifiers of an expression, strings are kept as they are
+func (r *renamer) renameExpr(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		j := i + 1
+		switch {
+		case quotes[c] != 0:
+			for j < len(runes) && runes[j] != quotes[c] {
+				j++
+			}
+			if j < len(runes) {
+				j++
+			}
+			sb.WriteString(string(runes[i:j]))
+		case isIdentRune(c):
+			for j < len(runes) && (isIdentRune(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			sb.WriteString(r.renameVariable(string(runes[i:j])))
+		case unicode.IsDigit(c):
+			// numbers are copied with their digits
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			sb.WriteString(string(runes[i:j]))
+		default:
+			sb.WriteRune(c)
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// renameVariable returns a new name of a variable, like "client.name" for "customer.name"
+// when "customer" is renamed to "client"
+func (r *renamer) renameVariable(name string) string {
+	if renamed, ok := r.names[name]; ok {
+		return renamed
+	}
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] != '.' {
+			continue
+		}
+		if renamed, ok := r.names[name[:i]]; ok {
+			return renamed + name[i:]
+		}
+	}
+	return name
+}
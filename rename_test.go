@@ -0,0 +1,58 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenamePlaceholders(t *testing.T) {
+	names := map[string]string{"customer": "client", "total": "amount", "Project Year": "year"}
+	tests := []struct{ name, want string }{
+		{"customer.name", "client.name"},
+		{"customer_id", "customer_id"},
+		{"total*2|money:EUR", "amount*2|money:EUR"},
+		{"total|default:total", "amount|default:total"},
+		{"#if total > 0 and customer == 'total'", "#if amount > 0 and client == 'total'"},
+		{"-#if total-", "-#if amount-"},
+		{"/if", "/if"},
+		{" Project Year ", " year "},
+	}
+	r := &renamer{doc: &Docx{}, names: names}
+	for _, tt := range tests {
+		if got := r.renameVariables(tt.name); got != tt.want {
+			t.Errorf("renameVariables(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	body := `<w:p><w:r><w:t>Dear [cus</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>tomer.name], [total]</w:t></w:r></w:p>` +
+		`<w:sdt><w:sdtPr><w:tag w:val="customer.name"/></w:sdtPr><w:sdtContent><w:r><w:t>Name</w:t></w:r></w:sdtContent></w:sdt>`
+	parts := map[string]string{
+		"word/header1.xml": `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:p><w:r><w:t>[Project Year]</w:t></w:r></w:p></w:hdr>`,
+	}
+	doc := newTestDocx(t, body, parts).RenamePlaceholders(names)
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTemplate(buf); err != nil {
+		t.Fatal(err)
+	}
+	got := renderParts(t, New(bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+	for part, want := range map[string]string{
+		documentXML:        `<w:t>Dear [client.name]</w:t></w:r><w:r><w:rPr><w:b></w:b></w:rPr><w:t>, [amount]</w:t>`,
+		"word/header1.xml": `<w:t>[year]</w:t>`,
+	} {
+		if !strings.Contains(got[part], want) {
+			t.Errorf("got: %s\nwant: %s", got[part], want)
+		}
+	}
+	if want := `<w:tag w:val="client.name">`; !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+
+	// the dictionary uses new names
+	body += p("[#if customer.name]") + p("[Project Year]") + p("[/if]")
+	doc = newTestDocx(t, body, nil).RenamePlaceholders(names).ReplaceDict(Dict{"client": Dict{"name": "Ann"}, "amount": 5, "year": 2024})
+	if body := renderBody(t, doc); !strings.Contains(body, "<w:t>Dear Ann</w:t>") || !strings.Contains(body, "<w:t>, 5</w:t>") || !strings.Contains(body, "2024") {
+		t.Errorf("got: %s", body)
+	}
+}
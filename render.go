@@ -0,0 +1,741 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// renderContext is a state shared by all values rendered into a single part
+type renderContext struct {
+	doc  *Docx
+	pkg  *pkg
+	part string
+	root *node
+	// last used ID of drawing objects (wp:docPr)
+	docPrID int
+	// scopes of variables of repeated content, others use global scope
+	scopes map[*node]*scope
+	global *scope
+	// building blocks of the template, loaded when they are used
+	glossary *glossary
+	// depth of values nested in inserted content, like placeholders in building blocks
+	depth int
+	// noteSources are parts with notes of references copied from building blocks,
+	// noteParts are those parts parsed
+	noteSources map[*node]string
+	noteParts   map[string]*node
+	// comments added to replaced values
+	reviewComments *reviewComments
+	// repeatedTables are tables whose header rows are marked
+	repeatedTables map[*node]bool
+	// names and the last ID of bookmarks, loaded when sub-documents are inserted
+	bookmarks  map[string]bool
+	bookmarkID int
+}
+
+// maxDepth is a limit of nested values, it stops building blocks which include themselves
+const maxDepth = 16
+
+// globalScope returns the scope of the dictionary, values added by helpers (like invoice totals)
+// are used when they aren't in the dictionary
+func (doc *Docx) globalScope(computed map[string]interface{}) *scope {
+	defaults := make(Dict, len(doc.values))
+	for k, v := range doc.values {
+		defaults[k] = v
+	}
+	if doc.invoice != nil {
+		for k, v := range doc.invoice.totals(doc.locale).dict {
+			defaults[k] = v
+		}
+	}
+	return &scope{doc: doc, dict: doc.dict, parent: &scope{doc: doc, dict: defaults}, computed: computed}
+}
+
+func newRenderContext(doc *Docx, p *pkg, part string, root *node) *renderContext {
+	ctx := &renderContext{
+		doc:    doc,
+		pkg:    p,
+		part:   part,
+		root:   root,
+		scopes: make(map[*node]*scope),
+
+		noteSources: make(map[*node]string),
+		noteParts:   make(map[string]*node),
+
+		repeatedTables: make(map[*node]bool),
+	}
+	// lazy values are computed once for all parts
+	if p.computed == nil {
+		p.computed = make(map[string]interface{})
+	}
+	ctx.global = doc.globalScope(p.computed)
+	root.walk(func(n *node) bool {
+		if n.is("wp:docPr") {
+			if id, err := strconv.Atoi(n.attrValue("id")); err == nil && id > ctx.docPrID {
+				ctx.docPrID = id
+			}
+		}
+		return true
+	})
+	return ctx
+}
+
+// scopeOf returns variables visible in a node
+func (ctx *renderContext) scopeOf(n *node) *scope {
+	for ; n != nil; n = n.parent {
+		if s, ok := ctx.scopes[n]; ok {
+			return s
+		}
+	}
+	return ctx.global
+}
+
+// nextDocPrID returns unique ID for a new drawing object
+func (ctx *renderContext) nextDocPrID() int {
+	ctx.docPrID++
+	return ctx.docPrID
+}
+
+// addMedia stores a file in word/media directory and returns ID
+// of a relationship which points to it from the rendered part
+func (ctx *renderContext) addMedia(data []byte, ext, contentType string) (string, error) {
+	name := ctx.pkg.uniqueName("word/media/image", "."+ext)
+	if err := ctx.pkg.set(name, data); err != nil {
+		return "", err
+	}
+	ct, err := ctx.pkg.types()
+	if err != nil {
+		return "", err
+	}
+	ct.addDefault(ext, contentType)
+	rels, err := ctx.pkg.relationships(ctx.part)
+	if err != nil {
+		return "", err
+	}
+	return rels.add(relTypeImage, relTarget(ctx.part, name)), nil
+}
+
+// declareNS adds namespace declaration to the root element of a part if it's missing
+func (ctx *renderContext) declareNS(prefix, uri string) {
+	el := ctx.root.documentElement()
+	if el != nil && !el.hasAttr("xmlns:"+prefix) {
+		el.setAttr("xmlns:"+prefix, uri)
+	}
+}
+
+// defaultRenderedParts are parts rendered besides document.xml unless RenderParts is used
+var defaultRenderedParts = []string{"word/header*.xml", "word/footer*.xml", "word/footnotes.xml", "word/endnotes.xml"}
+
+// RenderParts sets parts whose placeholders are replaced besides word/document.xml, patterns
+// use syntax of path.Match, like "word/header*.xml". Headers, footers and notes are rendered
+// by default, RenderParts() without patterns renders only the document
+func (doc *Docx) RenderParts(patterns ...string) *Docx {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			doc.err = fmt.Errorf("Invalid pattern of parts %s: %v", pattern, err)
+			return doc
+		}
+	}
+	doc.renderedParts = append([]string{}, patterns...)
+	return doc
+}
+
+// partsToRender returns XML parts matching patterns of rendered parts in order of patterns,
+// the document isn't included
+func (doc *Docx) partsToRender(p *pkg) []string {
+	patterns := doc.renderedParts
+	if patterns == nil {
+		patterns = defaultRenderedParts
+	}
+	names := append([]string(nil), p.names...)
+	sort.Strings(names)
+	var parts []string
+	seen := map[string]bool{documentXML: true}
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok && !seen[name] && strings.HasSuffix(name, ".xml") {
+				seen[name] = true
+				parts = append(parts, name)
+			}
+		}
+	}
+	return parts
+}
+
+// renderPart replaces placeholders in a given part of the archive
+func (doc *Docx) renderPart(p *pkg, name string) error {
+	if err := p.cancelled(); err != nil {
+		return err
+	}
+	data, err := p.read(name)
+	if err != nil {
+		return err
+	}
+	if !doc.needsRendering(p, name, data) {
+		// the part is copied as it is
+		return nil
+	}
+	root, err := scan(data)
+	if err != nil {
+		return err
+	}
+	if doc.goTemplate != nil {
+		if root, err = doc.goTemplate.execute(name, root, doc.strict); err != nil {
+			return err
+		}
+	}
+	ctx := newRenderContext(doc, p, name, root)
+	if err := ctx.commentDirectives(); err != nil {
+		return err
+	}
+	doc.mustacheTags(root)
+	if err := ctx.includePartials(); err != nil {
+		return err
+	}
+	if name == documentXML {
+		ctx.layoutEnvelopes()
+		ctx.layoutLabels()
+	}
+	ctx.fillInvoice()
+	if err := ctx.repeatingSections(); err != nil {
+		return err
+	}
+	if err := ctx.repeatRows(); err != nil {
+		return err
+	}
+	if err := ctx.setPictures(); err != nil {
+		return err
+	}
+	if err := ctx.replaceImages(); err != nil {
+		return err
+	}
+	if err := ctx.setDates(); err != nil {
+		return err
+	}
+	if err := ctx.expandBlocks(); err != nil {
+		return err
+	}
+	if err := ctx.replaceSimpleFields(); err != nil {
+		return err
+	}
+	if err := ctx.updatePropertyFields(); err != nil {
+		return err
+	}
+	if err := ctx.replaceHyperlinks(); err != nil {
+		return err
+	}
+	for _, para := range root.find("w:p") {
+		if err := p.cancelled(); err != nil {
+			return err
+		}
+		if err := ctx.replaceParagraph(para); err != nil {
+			return err
+		}
+	}
+	if err := ctx.replaceBookmarks(); err != nil {
+		return err
+	}
+	ctx.replaceRegexps()
+	if err := ctx.saveReviewComments(); err != nil {
+		return err
+	}
+	if name == documentXML {
+		if err := ctx.insertCoverPage(); err != nil {
+			return err
+		}
+		if err := ctx.setHeaders(); err != nil {
+			return err
+		}
+		if err := ctx.renumberNotes(); err != nil {
+			return err
+		}
+		ctx.setLineNumbers()
+		if err := ctx.setCustomProperties(); err != nil {
+			return err
+		}
+		audit, err := doc.auditProperties()
+		if err != nil {
+			return err
+		}
+		if err := ctx.addCustomProperties(append(doc.templateProperties(), audit...)); err != nil {
+			return err
+		}
+		if err := ctx.updateStatistics(); err != nil {
+			return err
+		}
+	}
+	return p.setPart(name, root)
+}
+
+// parseAuxiliaryPart parses a part related to the rendered one, ok is false
+// when the part is malformed and it's left unchanged in resilient mode
+func (ctx *renderContext) parseAuxiliaryPart(name string) (root *node, ok bool, err error) {
+	root, err = ctx.pkg.parsePart(name)
+	if err == nil {
+		return root, true, nil
+	}
+	if _, malformed := err.(*xml.SyntaxError); !malformed || !ctx.doc.resilient {
+		return nil, false, err
+	}
+	ctx.doc.warn(fmt.Errorf("Invalid DOCX document: %s is left unchanged: %v", name, err))
+	return nil, false, nil
+}
+
+// changedContent are pieces of XML which are changed even when there are no placeholders
+var changedContent = [][]byte{
+	[]byte("<w:sdt>"), []byte("<w:hyperlink "), []byte("DOCPROPERTY"), []byte("<w:commentRangeStart "),
+}
+
+// needsRendering is a quick check if anything can change in a part,
+// parts without placeholders aren't parsed at all
+func (doc *Docx) needsRendering(p *pkg, name string, data []byte) bool {
+	if bytes.Contains(data, []byte(doc.openingBracket)) {
+		return true
+	}
+	for _, c := range changedContent {
+		if bytes.Contains(data, c) {
+			return true
+		}
+	}
+	if len(doc.bookmarks) > 0 && bytes.Contains(data, []byte("<w:bookmarkStart ")) {
+		return true
+	}
+	if len(doc.images) > 0 && bytes.Contains(data, []byte("<wp:docPr ")) {
+		return true
+	}
+	if doc.goTemplate != nil && bytes.Contains(data, []byte(actionDelimiters[0])) {
+		return true
+	}
+	if len(doc.regexps) > 0 && bytes.Contains(data, []byte("<w:t")) {
+		return true
+	}
+	if name != documentXML {
+		return false
+	}
+	if doc.coverPage != nil || doc.labels != nil || doc.envelopes != nil || doc.invoice != nil ||
+		len(doc.headers) > 0 || doc.titlePage != nil || doc.evenAndOdd != nil || doc.lineNumbers != nil ||
+		doc.templateInfo != nil || doc.audit != nil {
+		return true
+	}
+	_, ok := p.relatedPart("", relTypeCustomProperties)
+	return ok && len(doc.dict) > 0
+}
+
+// replaceParagraph replaces all placeholders found in paragraph text
+func (ctx *renderContext) replaceParagraph(p *node) error {
+	para := newParagraph(p)
+	found := ctx.doc.placeholders(para.text)
+	if para.spansRuns(found) {
+		mergeRuns(p)
+		para = newParagraph(p)
+	}
+	first := len(ctx.pkg.substitutions)
+	// values are resolved in document order, so values computed by ReplaceFunc follow it
+	values := make([]Value, len(found))
+	for i, ph := range found {
+		if para.crossesField(ph.start, ph.end) || ctx.limitReached(ph.name) {
+			continue
+		}
+		v, ok, err := ctx.scopeOf(p).resolve(ph.name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			ctx.recordUnresolved(ph)
+			continue
+		}
+		if v, err = ctx.scopeOf(p).formatValue(ph.name, v); err != nil {
+			return err
+		}
+		if values[i], err = toValue(ctx.doc.checkboxValue(v)); err != nil {
+			return err
+		}
+		ctx.countOccurrence(ph.name)
+	}
+	// replace from the end, so positions of preceding placeholders stay valid
+	for i := len(found) - 1; i >= 0; i-- {
+		ph, value := found[i], values[i]
+		if para.crossesField(ph.start, ph.end) {
+			continue
+		}
+		if value == nil {
+			ctx.recordSubstitution(first, ph, nil, false)
+			continue
+		}
+		ctx.recordSubstitution(first, ph, value, true)
+		if text, ok := value.(Text); ok && !ctx.doc.reviewComments {
+			para.replace(ph.start, ph.end, string(text))
+			continue
+		}
+		if block, ok := value.(blockValue); ok && len(found) == 1 && isMarkerOnly(marker{para: p, ph: ph}) {
+			blocks, err := block.blocks(ctx)
+			if err != nil {
+				return err
+			}
+			para.erase(ph.start, ph.end)
+			insertBlocks(p, blocks)
+			if err := ctx.renderBlocks(blocks); err != nil {
+				return err
+			}
+			continue
+		}
+		runs, err := value.runs(ctx, para.runProperties(ph.start))
+		if err != nil {
+			return err
+		}
+		if ctx.doc.reviewComments {
+			if runs, err = ctx.reviewComment(runs, ph); err != nil {
+				return err
+			}
+		}
+		para.replace(ph.start, ph.end, "")
+		para.insertRuns(ph.start, runs)
+		formatParagraph(p, value)
+	}
+	if ctx.doc.invalidCharacters != ReplaceInvalid && len(found) > 0 {
+		if err := ctx.sanitizeText(p); err != nil {
+			return err
+		}
+	}
+	if ctx.doc.tabs && len(found) > 0 {
+		expandTabs(p)
+	}
+	if ctx.doc.lineBreaks && len(found) > 0 && p.parent != nil {
+		breakLines(p)
+	}
+	return nil
+}
+
+// insertBlocks puts paragraphs or tables in place of an empty paragraph,
+// a paragraph which ends a section is kept
+func insertBlocks(p *node, blocks []*node) {
+	parent := p.parent
+	if hasSectPr(p) {
+		parent.insert(p.index(), blocks...)
+	} else {
+		p.replace(blocks...)
+	}
+	ensureParagraph(parent)
+}
+
+// renderBlocks replaces placeholders in inserted content, like paragraphs of a building block
+func (ctx *renderContext) renderBlocks(blocks []*node) error {
+	if ctx.depth >= maxDepth {
+		return fmt.Errorf("Values are nested too deeply")
+	}
+	ctx.depth++
+	defer func() { ctx.depth-- }()
+	for _, b := range blocks {
+		paragraphs := b.find("w:p")
+		if b.is("w:p") {
+			paragraphs = append([]*node{b}, paragraphs...)
+		}
+		for _, para := range paragraphs {
+			if err := ctx.replaceParagraph(para); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// placeholder is a variable found in paragraph text
+type placeholder struct {
+	// position of a placeholder (including brackets) in paragraph text
+	start, end int
+	// name is a text between brackets
+	name string
+}
+
+// placeholders finds all bracketed variables in a text
+func (doc *Docx) placeholders(text string) []placeholder {
+	opening, closing := doc.openingBracket, doc.closingBracket
+	var found []placeholder
+	for i := 0; i < len(text); {
+		start := strings.Index(text[i:], opening)
+		if start == -1 {
+			break
+		}
+		start += i
+		end := strings.Index(text[start+len(opening):], closing)
+		if end == -1 {
+			break
+		}
+		end += start + len(opening)
+		// use the innermost opening bracket, i.e. "[a [b]" contains only "[b]"
+		if inner := strings.LastIndex(text[start+len(opening):end], opening); inner != -1 {
+			start += len(opening) + inner
+		}
+		found = append(found, placeholder{
+			start: start,
+			end:   end + len(closing),
+			name:  text[start+len(opening) : end],
+		})
+		i = end + len(closing)
+	}
+	return found
+}
+
+// paragraph gives access to text of <w:p> element which is usually split into runs
+type paragraph struct {
+	p        *node
+	text     string
+	segments []segment
+	// fields are positions in text where fields start, end or where their results start
+	fields []int
+}
+
+// segment is a piece of paragraph text stored in a single <w:t> element
+type segment struct {
+	t     *node
+	start int
+	end   int
+}
+
+func newParagraph(p *node) *paragraph {
+	para := &paragraph{p: p}
+	var sb strings.Builder
+	// instruction is true for each open field which instruction wasn't finished yet,
+	// text of nested fields in instructions is a part of the instruction too
+	var instruction []bool
+	var visit func(n *node) bool
+	visit = func(n *node) bool {
+		// nested paragraphs (e.g. in text boxes) are processed separately
+		if n.is("w:p") {
+			return false
+		}
+		if n.is("w:fldSimple") {
+			// result of a simple field can contain placeholders, but they can't cross its bounds
+			para.fields = append(para.fields, sb.Len())
+			n.walk(visit)
+			para.fields = append(para.fields, sb.Len())
+			return false
+		}
+		if n.is("w:fldChar") {
+			switch n.attrValue("w:fldCharType") {
+			case "begin":
+				instruction = append(instruction, true)
+			case "separate":
+				if len(instruction) > 0 {
+					instruction[len(instruction)-1] = false
+				}
+			case "end":
+				if len(instruction) > 0 {
+					instruction = instruction[:len(instruction)-1]
+				}
+			}
+			para.fields = append(para.fields, sb.Len())
+			return false
+		}
+		if n.is("w:t") {
+			for _, instr := range instruction {
+				if instr {
+					return false
+				}
+			}
+			text := n.text()
+			start := sb.Len()
+			sb.WriteString(text)
+			para.segments = append(para.segments, segment{t: n, start: start, end: sb.Len()})
+			return false
+		}
+		return true
+	}
+	p.walk(visit)
+	para.text = sb.String()
+	return para
+}
+
+// crossesField checks if a part of text starts and ends in different parts of fields,
+// such text can't be replaced without breaking the field
+func (para *paragraph) crossesField(start, end int) bool {
+	for _, pos := range para.fields {
+		if start < pos && pos < end {
+			return true
+		}
+	}
+	return false
+}
+
+// replace changes text between start and end positions,
+// new text is put into the run where the replaced text starts, so it keeps its formatting
+func (para *paragraph) replace(start, end int, s string) {
+	first := true
+	for _, seg := range para.segments {
+		if seg.end <= start || seg.start >= end {
+			continue
+		}
+		text := seg.t.text()
+		from, to := start-seg.start, end-seg.start
+		if from < 0 {
+			from = 0
+		}
+		if to > len(text) {
+			to = len(text)
+		}
+		if from > to {
+			from = to
+		}
+		if first {
+			setRunText(seg.t, text[:from]+s+text[to:])
+			first = false
+			continue
+		}
+		setRunText(seg.t, text[:from]+text[to:])
+		if from == 0 && to == len(text) {
+			removeText(seg.t)
+		}
+	}
+}
+
+// erase removes text between positions together with runs lying entirely in between
+// (e.g. tabs or pictures)
+func (para *paragraph) erase(start, end int) {
+	para.eraseRuns(start, end, false)
+}
+
+// truncate removes text and all runs after given position
+func (para *paragraph) truncate(start int) {
+	para.eraseRuns(start, len(para.text), true)
+}
+
+func (para *paragraph) eraseRuns(start, end int, tail bool) {
+	if start >= end {
+		return
+	}
+	runs := para.runs()
+	index := make(map[*node]int, len(runs))
+	for i, r := range runs {
+		index[r] = i
+	}
+	first, last := -1, len(runs)
+	if seg := para.segmentAt(start); seg != nil {
+		if i, ok := index[seg.t.parent]; ok {
+			first = i
+		}
+	}
+	if seg := para.segmentAt(end - 1); seg != nil && !tail {
+		if i, ok := index[seg.t.parent]; ok {
+			last = i
+		}
+	}
+	for i := first + 1; i < last; i++ {
+		runs[i].remove()
+	}
+	para.replace(start, end, "")
+	if seg := para.segmentAt(start); seg != nil && seg.t.parent != nil && seg.t.text() == "" {
+		removeText(seg.t)
+	}
+}
+
+// runs returns all runs of a paragraph in document order
+func (para *paragraph) runs() []*node {
+	var runs []*node
+	para.p.walk(func(n *node) bool {
+		if n.is("w:p") {
+			return false
+		}
+		if n.is("w:r") {
+			runs = append(runs, n)
+			return false
+		}
+		return true
+	})
+	return runs
+}
+
+// removeText removes <w:t> element and its run if nothing else is left there
+func removeText(t *node) {
+	r := t.parent
+	t.remove()
+	if r == nil || !r.is("w:r") {
+		return
+	}
+	for _, c := range r.children {
+		if !c.is("w:rPr") {
+			return
+		}
+	}
+	r.remove()
+}
+
+// runProperties returns formatting (<w:rPr>) of a run at given position
+func (para *paragraph) runProperties(pos int) *node {
+	seg := para.segmentAt(pos)
+	if seg == nil || seg.t.parent == nil {
+		return nil
+	}
+	return seg.t.parent.child("w:rPr")
+}
+
+// segmentAt returns a segment which contains given position
+func (para *paragraph) segmentAt(pos int) *segment {
+	for i := range para.segments {
+		seg := &para.segments[i]
+		if seg.start <= pos && pos < seg.end {
+			return seg
+		}
+	}
+	if n := len(para.segments); n > 0 {
+		return &para.segments[n-1]
+	}
+	return nil
+}
+
+// insertRuns splits a run at given position and puts new runs in between
+func (para *paragraph) insertRuns(pos int, runs []*node) {
+	seg := para.segmentAt(pos)
+	if seg == nil {
+		para.p.append(runs...)
+		return
+	}
+	t := seg.t
+	r := t.parent
+	if r == nil || !r.is("w:r") {
+		return
+	}
+	text := t.text()
+	offset := pos - seg.start
+	if offset > len(text) {
+		offset = len(text)
+	}
+	i := t.index()
+	after := append([]*node(nil), r.children[i+1:]...)
+	r.children = r.children[:i+1]
+	setRunText(t, text[:offset])
+	// and update segments, so following inserts find the right part of the run
+	seg.end = seg.start + offset
+	nodes := append([]*node(nil), runs...)
+	if offset < len(text) || len(after) > 0 {
+		// the second half of a run gets the same formatting and the rest of its content
+		tail := elem("w:r")
+		tail.attr = r.attr
+		if rPr := r.child("w:rPr"); rPr != nil {
+			tail.append(rPr.clone())
+		}
+		if offset < len(text) {
+			rest := elem("w:t")
+			setRunText(rest, text[offset:])
+			tail.append(rest)
+		}
+		tail.append(after...)
+		nodes = append(nodes, tail)
+	}
+	r.parent.insert(r.index()+1, nodes...)
+	if offset == 0 {
+		removeText(t)
+	}
+}
+
+// setRunText changes text of <w:t> element and preserves its whitespace
+func setRunText(t *node, s string) {
+	t.setText(s)
+	if strings.TrimSpace(s) != s {
+		t.setAttr("xml:space", "preserve")
+	}
+}
@@ -0,0 +1,77 @@
+package docx
+
+import (
+	"archive/zip"
+	"io"
+	"time"
+)
+
+// dosEpoch is the earliest modification time a zip archive can store
+var dosEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Reproducible makes the same template and data always produce the same bytes, so documents
+// can be cached and compared by hash: all entries of the archive get the given modification
+// time (1980-01-01 when it's zero), a stable order and headers without varying fields.
+// Dates written by Audit and ReviewComments are the given time too. Deflate of the standard
+// library is deterministic, so the bytes only change with the version of Go
+func (doc *Docx) Reproducible(modified time.Time) *Docx {
+	if modified.IsZero() || modified.Before(dosEpoch) {
+		modified = dosEpoch
+	}
+	modified = modified.UTC()
+	doc.reproducible = &modified
+	return doc
+}
+
+// now returns the current time or the time of reproducible output
+func (doc *Docx) now() time.Time {
+	if doc.reproducible != nil {
+		return *doc.reproducible
+	}
+	return now()
+}
+
+// createEntry adds an entry of a changed part, with a fixed time in reproducible output
+func (p *pkg) createEntry(zipOut *zip.Writer, name string) (io.Writer, error) {
+	if p.modified == nil {
+		return zipOut.Create(name)
+	}
+	return zipOut.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: *p.modified})
+}
+
+// copyEntry copies an untouched part still compressed, in reproducible output its header
+// keeps only the name, the compression and the sizes of the original entry
+func (p *pkg) copyEntry(zipOut *zip.Writer, f *zip.File) error {
+	if p.modified == nil {
+		return zipOut.Copy(f)
+	}
+	r, err := f.OpenRaw()
+	if err != nil {
+		return err
+	}
+	date, clock := msDosTime(*p.modified)
+	w, err := zipOut.CreateRaw(&zip.FileHeader{
+		Name:               f.Name,
+		Method:             f.Method,
+		Flags:              f.Flags &^ 0x8, // sizes are known, no data descriptor is needed
+		Modified:           *p.modified,
+		ModifiedDate:       date,
+		ModifiedTime:       clock,
+		CRC32:              f.CRC32,
+		CompressedSize64:   f.CompressedSize64,
+		UncompressedSize64: f.UncompressedSize64,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// msDosTime returns a date and a time of day as stored in headers of zip archives,
+// CreateRaw writes them without converting the modification time
+func msDosTime(t time.Time) (date, clock uint16) {
+	date = uint16(t.Day() + int(t.Month())<<5 + (t.Year()-1980)<<9)
+	clock = uint16(t.Second()/2 + t.Minute()<<5 + t.Hour()<<11)
+	return date, clock
+}
@@ -0,0 +1,65 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReproducible(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	now = func() time.Time {
+		clock = clock.Add(time.Hour)
+		return clock
+	}
+	modified := time.Date(2020, 5, 4, 12, 0, 0, 0, time.UTC)
+	render := func(reproducible bool) []byte {
+		t.Helper()
+		// relationships of parts without them are added in random order of a map
+		parts := map[string]string{"word/a.xml": "<a/>", "word/b.xml": "<b/>", "word/c.xml": "<c/>"}
+		doc := newTestDocx(t, p("[name]"), parts).ReplaceDict(Dict{"name": "ACME"}).Audit("alice").
+			Header(DefaultPages, "[name]")
+		for _, source := range []string{"word/a.xml", "word/b.xml", "word/c.xml"} {
+			if _, err := doc.AddPart(strings.TrimSuffix(source, ".xml")+".png", "image/png", []byte("png"), source); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if reproducible {
+			doc.Reproducible(modified)
+		}
+		var buf bytes.Buffer
+		if _, err := doc.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	first := render(true)
+	for i := 0; i < 10; i++ {
+		if !bytes.Equal(render(true), first) {
+			t.Fatal("Expected the same output of every rendering")
+		}
+	}
+	if bytes.Equal(render(false), render(false)) {
+		t.Error("Expected different times of audit trails without reproducible output")
+	}
+	r, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range r.File {
+		if !f.Modified.Equal(modified) {
+			t.Errorf("Expected %s modified at %s, got %s", f.Name, modified, f.Modified)
+		}
+	}
+	custom := renderParts(t, newTestDocx(t, p("x"), nil).Audit("alice").Reproducible(modified))["docProps/custom.xml"]
+	if !strings.Contains(custom, "2020-05-04T12:00:00Z") {
+		t.Errorf("Expected the time of reproducible output in the audit trail, got %s", custom)
+	}
+
+	if got := *newTestDocx(t, p("x"), nil).Reproducible(time.Time{}).reproducible; !got.Equal(dosEpoch) {
+		t.Errorf("Expected zero time to be 1980-01-01, got %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	contentTypeComments = "application/vnd.openxmlformats-officedocument.wordprocessingml.comments+xml"
+	commentsXML         = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"></w:comments>`
+	defaultAuthor   = "go-docx"
+	defaultInitials = "GD"
+)
+
+// Author is shown with comments and revisions written by the library
+type Author struct {
+	Name     string
+	Initials string
+	// Date is the time of rendering if it's zero
+	Date time.Time
+}
+
+// RevisionAuthor sets the author of comments and revisions written by the library,
+// it's "go-docx" by default. Empty fields get the default values
+func (doc *Docx) RevisionAuthor(author Author) *Docx {
+	doc.author = author
+	return doc
+}
+
+// revisionAuthor returns the author with default values of empty fields
+func (doc *Docx) revisionAuthor() Author {
+	author := doc.author
+	if author.Name == "" {
+		author.Name = defaultAuthor
+		if author.Initials == "" {
+			author.Initials = defaultInitials
+		}
+	}
+	if author.Date.IsZero() {
+		author.Date = doc.now()
+	}
+	author.Date = author.Date.UTC()
+	return author
+}
+
+// ReviewComments attaches a Word comment to every value replaced in the document body,
+// like "Filled by the system from customer.name on 2024-03-01", so reviewers can see
+// where values come from
+func (doc *Docx) ReviewComments(enabled bool) *Docx {
+	doc.reviewComments = enabled
+	return doc
+}
+
+// reviewComments are comments added to the rendered part
+type reviewComments struct {
+	name string
+	root *node
+	// next is ID of the next comment
+	next int
+}
+
+// reviewComment puts runs of a value replacing a placeholder into a range of a new comment
+func (ctx *renderContext) reviewComment(runs []*node, ph placeholder) ([]*node, error) {
+	if ctx.part != documentXML {
+		return runs, nil
+	}
+	comments, err := ctx.loadReviewComments()
+	if err != nil || comments == nil {
+		return runs, err
+	}
+	author := ctx.doc.revisionAuthor()
+	id := strconv.Itoa(comments.next)
+	comments.next++
+	name, _ := parseFilters(ph.name)
+	pPr := elem("w:pPr")
+	pPr.append(elem("w:pStyle", "w:val", "CommentText"))
+	annotation := elem("w:r")
+	annotation.append(elem("w:annotationRef"))
+	p := elem("w:p")
+	p.append(pPr, annotation, newRun(nil, fmt.Sprintf("Filled by the system from %s on %s", name, author.Date.Format("2006-01-02"))))
+	comment := elem("w:comment", "w:id", id, "w:author", author.Name, "w:date", author.Date.Format(time.RFC3339))
+	if author.Initials != "" {
+		comment.setAttr("w:initials", author.Initials)
+	}
+	comment.append(p)
+	comments.root.documentElement().append(comment)
+	rPr := elem("w:rPr")
+	rPr.append(elem("w:rStyle", "w:val", "CommentReference"))
+	reference := elem("w:r")
+	reference.append(rPr, elem("w:commentReference", "w:id", id))
+	nodes := append([]*node{elem("w:commentRangeStart", "w:id", id)}, runs...)
+	return append(nodes, elem("w:commentRangeEnd", "w:id", id), reference), nil
+}
+
+// loadReviewComments returns comments of the document, the part is created if it doesn't exist.
+// It returns nil if the part is malformed in resilient mode
+func (ctx *renderContext) loadReviewComments() (*reviewComments, error) {
+	if ctx.reviewComments != nil {
+		return ctx.reviewComments, nil
+	}
+	name, ok := ctx.pkg.relatedPart(ctx.part, relTypeComments)
+	var root *node
+	if ok && ctx.pkg.has(name) {
+		var err error
+		if root, ok, err = ctx.parseAuxiliaryPart(name); !ok {
+			return nil, err
+		}
+	} else {
+		name = "word/comments.xml"
+		var err error
+		if root, err = scan([]byte(commentsXML)); err != nil {
+			return nil, err
+		}
+		rels, err := ctx.pkg.relationships(ctx.part)
+		if err != nil {
+			return nil, err
+		}
+		ct, err := ctx.pkg.types()
+		if err != nil {
+			return nil, err
+		}
+		rels.add(relTypeComments, relTarget(ctx.part, name))
+		ct.addOverride(name, contentTypeComments)
+	}
+	if root.documentElement() == nil {
+		return nil, nil
+	}
+	comments := &reviewComments{name: name, root: root}
+	for _, c := range root.find("w:comment") {
+		if id, err := strconv.Atoi(c.attrValue("w:id")); err == nil && id >= comments.next {
+			comments.next = id + 1
+		}
+	}
+	ctx.reviewComments = comments
+	return comments, nil
+}
+
+// saveReviewComments stores added comments
+func (ctx *renderContext) saveReviewComments() error {
+	if ctx.reviewComments == nil {
+		return nil
+	}
+	return ctx.pkg.setPart(ctx.reviewComments.name, ctx.reviewComments.root)
+}
@@ -0,0 +1,52 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewComments(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC) }
+	body := `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Dear [customer.name], [missing]</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": Dict{"name": "acme"}}).ReviewComments(true)
+	got := renderParts(t, doc)
+	want := `<w:r><w:rPr><w:b></w:b></w:rPr><w:t xml:space="preserve">Dear </w:t></w:r>` +
+		`<w:commentRangeStart w:id="0"></w:commentRangeStart><w:r><w:rPr><w:b></w:b></w:rPr><w:t>acme</w:t></w:r>` +
+		`<w:commentRangeEnd w:id="0"></w:commentRangeEnd>` +
+		`<w:r><w:rPr><w:rStyle w:val="CommentReference"></w:rStyle></w:rPr><w:commentReference w:id="0"></w:commentReference></w:r>` +
+		`<w:r><w:rPr><w:b></w:b></w:rPr><w:t>, [missing]</w:t></w:r>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	want = `<w:comment w:id="0" w:author="go-docx" w:date="2024-03-01T10:30:00Z" w:initials="GD"><w:p><w:pPr>` +
+		`<w:pStyle w:val="CommentText"></w:pStyle></w:pPr><w:r><w:annotationRef></w:annotationRef></w:r>` +
+		`<w:r><w:t>Filled by the system from customer.name on 2024-03-01</w:t></w:r></w:p></w:comment>`
+	if !strings.Contains(got["word/comments.xml"], want) {
+		t.Errorf("got: %s\nwant: %s", got["word/comments.xml"], want)
+	}
+	if !strings.Contains(got["word/_rels/document.xml.rels"], `Type="`+relTypeComments+`" Target="comments.xml"`) {
+		t.Errorf("Comments aren't related to the document: %s", got["word/_rels/document.xml.rels"])
+	}
+}
+
+func TestRevisionAuthor(t *testing.T) {
+	date := time.Date(2024, 5, 2, 8, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	tests := []struct {
+		author Author
+		want   string
+	}{
+		{Author{Name: "Jane Doe", Initials: "JD", Date: date}, `w:author="Jane Doe" w:date="2024-05-02T06:00:00Z" w:initials="JD"`},
+		{Author{Name: "Jane Doe", Date: date}, `w:author="Jane Doe" w:date="2024-05-02T06:00:00Z">`},
+		{Author{Date: date}, `w:author="go-docx" w:date="2024-05-02T06:00:00Z" w:initials="GD"`},
+	}
+	for _, tt := range tests {
+		doc := newTestDocx(t, `<w:p><w:r><w:t>[name]</w:t></w:r></w:p>`, nil).
+			ReplaceDict(Dict{"name": "Ann"}).ReviewComments(true).RevisionAuthor(tt.author)
+		got := renderParts(t, doc)["word/comments.xml"]
+		if !strings.Contains(got, tt.want) || !strings.Contains(got, "on 2024-05-02") {
+			t.Errorf("got: %s\nwant: %s", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// RevisionFilter selects tracked changes by their author and date
+type RevisionFilter struct {
+	// Authors are names of authors whose changes are selected, changes of all authors are selected if it's empty
+	Authors []string
+	// From and To limit dates of selected changes including them, zero times don't limit them.
+	// Changes without a date are selected only if both are zero
+	From, To time.Time
+}
+
+// AcceptRevisions accepts tracked changes of all parts, like the document body, headers, footers
+// or footnotes, which are selected by the filter, other changes are left pending. Accepted parts
+// override parts of the template, which can be saved with WriteTemplate
+func (doc *Docx) AcceptRevisions(filter RevisionFilter) *Docx {
+	return doc.resolveRevisions(reviser{filter: filter, accept: true})
+}
+
+// RejectRevisions rejects tracked changes selected by the filter, like AcceptRevisions accepts them
+func (doc *Docx) RejectRevisions(filter RevisionFilter) *Docx {
+	return doc.resolveRevisions(reviser{filter: filter})
+}
+
+func (doc *Docx) resolveRevisions(r reviser) *Docx {
+	if doc.err != nil {
+		return doc
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if doc.err = doc.setParts(p); doc.err != nil {
+		return doc
+	}
+	for _, name := range p.names {
+		if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		var data []byte
+		if data, doc.err = p.read(name); doc.err != nil {
+			return doc
+		}
+		if !bytes.Contains(data, []byte("w:author=")) {
+			continue
+		}
+		var root *node
+		if root, doc.err = scan(data); doc.err != nil {
+			return doc
+		}
+		if !r.revise(root) {
+			continue
+		}
+		if data, doc.err = root.bytes(); doc.err != nil {
+			return doc
+		}
+		doc.SetPart(name, data)
+	}
+	return doc
+}
+
+// reviser accepts or rejects tracked changes
+type reviser struct {
+	filter RevisionFilter
+	accept bool
+}
+
+// insertions and deletions are elements of tracked changes of content
+var (
+	insertions = map[string]bool{"w:ins": true, "w:moveTo": true, "w:cellIns": true}
+	deletions  = map[string]bool{"w:del": true, "w:moveFrom": true, "w:cellDel": true}
+	moveRanges = map[string]string{
+		"w:moveFromRangeStart": "w:moveFromRangeEnd",
+		"w:moveToRangeStart":   "w:moveToRangeEnd",
+	}
+)
+
+// revise resolves selected changes of a part and reports whether it changed
+func (r reviser) revise(root *node) bool {
+	revisions := trackedChanges(root)
+	changed := false
+	// paragraphs whose marks are removed are merged with the following ones
+	var merged []*node
+	ranges := make(map[string]bool)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		n := revisions[i]
+		if !r.selected(n) {
+			continue
+		}
+		changed = true
+		if end, ok := moveRanges[n.tag]; ok {
+			ranges[end+" "+n.attrValue("w:id")] = true
+			n.remove()
+			continue
+		}
+		if !insertions[n.tag] && !deletions[n.tag] {
+			r.resolveProperties(n)
+			continue
+		}
+		// accepted insertions and rejected deletions are kept
+		keep := insertions[n.tag] == r.accept
+		parent := n.parent
+		switch {
+		case parent.is("w:rPr") && parent.parent != nil && parent.parent.is("w:pPr"):
+			n.remove()
+			if !keep {
+				merged = append(merged, parent.parent.parent)
+			}
+		case parent.is("w:trPr") || parent.is("w:tcPr"):
+			n.remove()
+			if !keep {
+				removeCell(parent.parent)
+			}
+		case keep:
+			if deletions[n.tag] {
+				restoreText(n)
+			}
+			children := n.children
+			n.children = nil
+			n.replace(children...)
+		default:
+			n.remove()
+		}
+	}
+	if len(ranges) > 0 {
+		for _, tag := range moveRanges {
+			for _, n := range root.find(tag) {
+				if ranges[tag+" "+n.attrValue("w:id")] {
+					n.remove()
+				}
+			}
+		}
+	}
+	for _, p := range merged {
+		mergeParagraph(p)
+	}
+	return changed
+}
+
+// trackedChanges returns elements of tracked changes of a part in document order
+func trackedChanges(root *node) []*node {
+	var revisions []*node
+	root.walk(func(n *node) bool {
+		if n.hasAttr("w:author") && (insertions[n.tag] || deletions[n.tag] || moveRanges[n.tag] != "" ||
+			strings.HasSuffix(n.tag, "PrChange")) {
+			revisions = append(revisions, n)
+		}
+		return true
+	})
+	return revisions
+}
+
+// selected reports whether a change matches the filter
+func (r reviser) selected(n *node) bool {
+	if len(r.filter.Authors) > 0 {
+		author := n.attrValue("w:author")
+		found := false
+		for _, a := range r.filter.Authors {
+			if a == author {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.filter.From.IsZero() && r.filter.To.IsZero() {
+		return true
+	}
+	date := parseDate(n.attrValue("w:date"))
+	if date.IsZero() {
+		return false
+	}
+	return (r.filter.From.IsZero() || !date.Before(r.filter.From)) && (r.filter.To.IsZero() || !date.After(r.filter.To))
+}
+
+// resolveProperties accepts or rejects a formatting change like <w:rPrChange>,
+// rejected changes restore the previous properties
+func (r reviser) resolveProperties(change *node) {
+	parent := change.parent
+	change.remove()
+	if r.accept || parent == nil {
+		return
+	}
+	var previous *node
+	for _, c := range change.children {
+		if c.tag != "" {
+			previous = c
+			break
+		}
+	}
+	if previous == nil {
+		return
+	}
+	// properties which aren't part of the change, like the paragraph mark, are kept
+	var kept []*node
+	for _, c := range parent.children {
+		if c.is("w:rPr") || c.is("w:sectPr") || insertions[c.tag] || deletions[c.tag] || strings.HasSuffix(c.tag, "Change") {
+			kept = append(kept, c)
+		}
+	}
+	children := previous.children
+	previous.children = nil
+	parent.children = nil
+	parent.append(children...)
+	parent.append(kept...)
+}
+
+// removeCell removes a table row or cell, tables without rows are removed as well
+func removeCell(n *node) {
+	table := n.ancestor("w:tbl")
+	n.remove()
+	if table != nil && len(table.find("w:tc")) == 0 {
+		table.remove()
+	}
+}
+
+// restoreText turns deleted text of a rejected deletion into text
+func restoreText(del *node) {
+	del.walk(func(n *node) bool {
+		if deletions[n.tag] {
+			// deletions of other authors stay
+			return false
+		}
+		switch n.tag {
+		case "w:delText":
+			n.tag = "w:t"
+		case "w:delInstrText":
+			n.tag = "w:instrText"
+		}
+		return true
+	})
+}
+
+// mergeParagraph moves content of a paragraph whose mark was removed to the following paragraph
+func mergeParagraph(p *node) {
+	if p == nil || p.parent == nil {
+		return
+	}
+	var content []*node
+	for _, c := range p.children {
+		if !c.is("w:pPr") {
+			content = append(content, c)
+		}
+	}
+	siblings := p.parent.children
+	var next *node
+	for i := p.index() + 1; i < len(siblings); i++ {
+		if siblings[i].tag != "" {
+			next = siblings[i]
+			break
+		}
+	}
+	switch {
+	case next != nil && next.is("w:p"):
+		i := 0
+		if pPr := next.child("w:pPr"); pPr != nil {
+			i = pPr.index() + 1
+		}
+		for _, c := range content {
+			c.remove()
+		}
+		next.insert(i, content...)
+		p.remove()
+	case len(content) == 0:
+		p.remove()
+	}
+}
+
+// Kinds of revisions
+const (
+	RevisionInsert   = "insert"
+	RevisionDelete   = "delete"
+	RevisionMoveFrom = "moveFrom"
+	RevisionMoveTo   = "moveTo"
+	RevisionFormat   = "format"
+)
+
+// revisionKinds are kinds of revisions by their elements
+var revisionKinds = map[string]string{
+	"w:ins": RevisionInsert, "w:cellIns": RevisionInsert, "w:del": RevisionDelete, "w:cellDel": RevisionDelete,
+	"w:moveFrom": RevisionMoveFrom, "w:moveTo": RevisionMoveTo,
+}
+
+// contextLength is the maximum number of characters of text around a revision
+const contextLength = 40
+
+// Revision is a tracked change of a document
+type Revision struct {
+	ID     string    `json:"id"`
+	Kind   string    `json:"kind"`
+	Author string    `json:"author"`
+	Date   time.Time `json:"date"`
+	// Part is the name of the part, like "word/document.xml" or "word/header1.xml"
+	Part string `json:"part"`
+	// Text is inserted or deleted text, inserted or deleted paragraph marks are "\n"
+	// and text of changed rows or formatting is the text they contain
+	Text string `json:"text"`
+	// Before and After are text of the paragraph around the change
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Revisions returns tracked changes of all parts of the template, like the document body,
+// headers, footers or footnotes
+func (doc *Docx) Revisions() ([]Revision, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	defer p.close()
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	var list []Revision
+	for _, name := range p.names {
+		if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+			continue
+		}
+		data, err := p.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Contains(data, []byte("w:author=")) {
+			continue
+		}
+		root, err := scan(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range trackedChanges(root) {
+			if moveRanges[n.tag] != "" {
+				continue
+			}
+			list = append(list, newRevision(name, n))
+		}
+	}
+	return list, nil
+}
+
+// newRevision describes a tracked change
+func newRevision(part string, n *node) Revision {
+	rev := Revision{
+		ID:     n.attrValue("w:id"),
+		Kind:   revisionKinds[n.tag],
+		Author: n.attrValue("w:author"),
+		Date:   parseDate(n.attrValue("w:date")),
+		Part:   part,
+	}
+	parent := n.parent
+	// target is the changed content, its paragraph gives the context
+	target := n
+	switch {
+	case rev.Kind == "":
+		rev.Kind = RevisionFormat
+		target = parent.parent
+		rev.Text = revisionText(target)
+	case parent.is("w:rPr") && parent.parent != nil && parent.parent.is("w:pPr"):
+		rev.Text = "\n"
+		target = parent.parent.parent
+	case parent.is("w:trPr") || parent.is("w:tcPr"):
+		target = parent.parent
+		rev.Text = revisionText(target)
+	default:
+		rev.Text = revisionText(n)
+	}
+	if target == nil {
+		return rev
+	}
+	p := target
+	if !p.is("w:p") {
+		p = target.ancestor("w:p")
+	}
+	if p != nil {
+		before, after := paragraphContext(p, target)
+		if r := []rune(before); len(r) > contextLength {
+			before = string(r[len(r)-contextLength:])
+		}
+		if r := []rune(after); len(r) > contextLength {
+			after = string(r[:contextLength])
+		}
+		rev.Before, rev.After = before, after
+	}
+	return rev
+}
+
+// revisionText returns inserted and deleted text of an element, paragraphs are separated by new lines
+// and cells by tabs
+func revisionText(n *node) string {
+	var sb strings.Builder
+	n.walk(func(c *node) bool {
+		switch c.tag {
+		case "w:t", "w:delText":
+			sb.WriteString(c.text())
+		case "w:tab":
+			sb.WriteByte('\t')
+		case "w:br", "w:cr":
+			sb.WriteByte('\n')
+		case "w:pPr", "w:rPr", "w:instrText", "w:delInstrText":
+			return false
+		case "w:p":
+			if sb.Len() > 0 {
+				sb.WriteByte('\n')
+			}
+		case "w:tc":
+			if sb.Len() > 0 {
+				sb.WriteByte('\t')
+			}
+		}
+		return true
+	})
+	return sb.String()
+}
+
+// paragraphContext returns text of a paragraph before and after its content, other deletions are left out.
+// The whole text comes before the paragraph itself
+func paragraphContext(p, content *node) (string, string) {
+	var sb strings.Builder
+	var before string
+	seen := false
+	p.walk(func(c *node) bool {
+		if c == content {
+			before = sb.String()
+			sb.Reset()
+			seen = true
+			return false
+		}
+		switch c.tag {
+		case "w:t":
+			sb.WriteString(c.text())
+		case "w:tab":
+			sb.WriteByte('\t')
+		case "w:br", "w:cr":
+			sb.WriteByte('\n')
+		case "w:del", "w:moveFrom", "w:pPr", "w:rPr", "w:instrText":
+			return false
+		}
+		return true
+	})
+	if !seen {
+		return sb.String(), ""
+	}
+	return before, sb.String()
+}
@@ -0,0 +1,112 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// trackedBody has tracked changes of two authors
+const trackedBody = `<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r>` +
+	`<w:del w:id="1" w:author="Ann" w:date="2024-03-01T00:00:00Z"><w:r><w:delText>quick</w:delText></w:r></w:del>` +
+	`<w:ins w:id="2" w:author="Ann" w:date="2024-03-01T00:00:00Z"><w:r><w:t>slow</w:t></w:r></w:ins>` +
+	`<w:r><w:rPr><w:b/><w:rPrChange w:id="3" w:author="Bob" w:date="2024-04-01T00:00:00Z"><w:rPr><w:i/></w:rPr></w:rPrChange></w:rPr><w:t xml:space="preserve"> fox</w:t></w:r></w:p>` +
+	`<w:p><w:pPr><w:rPr><w:ins w:id="4" w:author="Bob" w:date="2024-04-01T00:00:00Z"/></w:rPr></w:pPr>` +
+	`<w:ins w:id="5" w:author="Bob" w:date="2024-04-01T00:00:00Z"><w:r><w:t>jumps</w:t></w:r></w:ins></w:p>` +
+	`<w:p><w:r><w:t>over</w:t></w:r></w:p>`
+
+func TestResolveRevisions(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(*Docx) *Docx
+		want string
+	}{
+		{"accept all", func(doc *Docx) *Docx { return doc.AcceptRevisions(RevisionFilter{}) },
+			`<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r><w:r><w:t>slow</w:t></w:r>` +
+				`<w:r><w:rPr><w:b></w:b></w:rPr><w:t xml:space="preserve"> fox</w:t></w:r></w:p>` +
+				`<w:p><w:pPr><w:rPr></w:rPr></w:pPr><w:r><w:t>jumps</w:t></w:r></w:p><w:p><w:r><w:t>over</w:t></w:r></w:p>`},
+		{"reject all", func(doc *Docx) *Docx { return doc.RejectRevisions(RevisionFilter{}) },
+			`<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r><w:r><w:t>quick</w:t></w:r>` +
+				`<w:r><w:rPr><w:i></w:i></w:rPr><w:t xml:space="preserve"> fox</w:t></w:r></w:p>` +
+				`<w:p><w:r><w:t>over</w:t></w:r></w:p>`},
+		{"accept author", func(doc *Docx) *Docx { return doc.AcceptRevisions(RevisionFilter{Authors: []string{"Ann"}}) },
+			`<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r><w:r><w:t>slow</w:t></w:r>` +
+				`<w:r><w:rPr><w:b></w:b><w:rPrChange w:id="3" w:author="Bob" w:date="2024-04-01T00:00:00Z">`},
+		{"reject dates", func(doc *Docx) *Docx {
+			return doc.RejectRevisions(RevisionFilter{From: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)})
+		},
+			`<w:ins w:id="2" w:author="Ann" w:date="2024-03-01T00:00:00Z"><w:r><w:t>slow</w:t></w:r></w:ins>` +
+				`<w:r><w:rPr><w:i></w:i></w:rPr><w:t xml:space="preserve"> fox</w:t></w:r></w:p><w:p><w:r><w:t>over</w:t></w:r></w:p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(newTestDocx(t, trackedBody, nil)).Part(documentXML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveComparedRevisions(t *testing.T) {
+	original := newTestDocx(t, `<w:p><w:r><w:t>The quick fox</w:t></w:r></w:p><w:p><w:r><w:t>Gone</w:t></w:r></w:p>`, nil)
+	revised := newTestDocx(t, `<w:p><w:r><w:t>The slow fox</w:t></w:r></w:p><w:p><w:r><w:t>Unchanged</w:t></w:r></w:p>`+
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>Added</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`, nil)
+	compared := Compare(original, revised)
+	accepted, err := compared.AcceptRevisions(RevisionFilter{}).Part(documentXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := bodyText(t, accepted), "The slow fox|Unchanged|Added|"; got != want {
+		t.Errorf("Accepted %q, want %q", got, want)
+	}
+	rejected, err := Compare(original, revised).RejectRevisions(RevisionFilter{}).Part(documentXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := bodyText(t, rejected), "The quick fox|Gone|"; got != want {
+		t.Errorf("Rejected %q, want %q", got, want)
+	}
+	if strings.Contains(string(rejected), "<w:tbl>") {
+		t.Errorf("Rejected table is kept: %s", rejected)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	got, err := newTestDocx(t, trackedBody, nil).Revisions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann, bob := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	want := []Revision{
+		{ID: "1", Kind: RevisionDelete, Author: "Ann", Date: ann, Part: documentXML, Text: "quick", Before: "The ", After: "slow fox"},
+		{ID: "2", Kind: RevisionInsert, Author: "Ann", Date: ann, Part: documentXML, Text: "slow", Before: "The ", After: " fox"},
+		{ID: "3", Kind: RevisionFormat, Author: "Bob", Date: bob, Part: documentXML, Text: " fox", Before: "The slow"},
+		{ID: "4", Kind: RevisionInsert, Author: "Bob", Date: bob, Part: documentXML, Text: "\n", Before: "jumps"},
+		{ID: "5", Kind: RevisionInsert, Author: "Bob", Date: bob, Part: documentXML, Text: "jumps"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+// bodyText returns text of paragraphs of a document separated by "|"
+func bodyText(t *testing.T, data []byte) string {
+	t.Helper()
+	root, err := scan(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	for _, p := range root.find("w:p") {
+		for _, text := range p.find("w:t") {
+			sb.WriteString(text.text())
+		}
+		sb.WriteString("|")
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+)
+
+// runContainers are elements of a paragraph whose runs are merged too
+var runContainers = map[string]bool{
+	"w:hyperlink": true, "w:smartTag": true, "w:ins": true, "w:customXml": true,
+}
+
+// spansRuns checks if any placeholder is split across several runs
+func (para *paragraph) spansRuns(found []placeholder) bool {
+	for _, ph := range found {
+		if ph.end > ph.start && para.segmentAt(ph.start) != para.segmentAt(ph.end-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRuns joins neighbouring runs with the same formatting which Word splits while text
+// is edited (by spell checking, revision IDs or formatting which was later removed), so
+// placeholders found in their text are replaced in a single run. Spelling marks are removed,
+// Word checks spelling again
+func mergeRuns(p *node) {
+	var prev *node
+	for _, c := range append([]*node(nil), p.children...) {
+		switch {
+		case c.is("w:proofErr"):
+			c.remove()
+		case c.tag == "":
+			// whitespace between elements
+		case c.is("w:r") && isTextRun(c):
+			if prev != nil && sameFormatting(prev, c) {
+				appendRunText(prev, c)
+				c.remove()
+				continue
+			}
+			prev = c
+		default:
+			prev = nil
+			if runContainers[c.tag] {
+				mergeRuns(c)
+			}
+		}
+	}
+}
+
+// isTextRun checks if a run contains nothing else than text and formatting
+func isTextRun(r *node) bool {
+	hasText := false
+	for _, c := range r.children {
+		switch {
+		case c.is("w:t"):
+			hasText = true
+		case c.is("w:rPr"), c.tag == "":
+		default:
+			return false
+		}
+	}
+	return hasText
+}
+
+// sameFormatting compares formatting of runs, revision IDs (rsids) are ignored
+func sameFormatting(a, b *node) bool {
+	return bytes.Equal(formattingKey(a), formattingKey(b))
+}
+
+func formattingKey(r *node) []byte {
+	rPr := r.child("w:rPr")
+	if rPr == nil {
+		return nil
+	}
+	rPr = rPr.clone()
+	rPr.walk(func(n *node) bool {
+		attrs := n.attr[:0]
+		for _, a := range n.attr {
+			if !strings.HasPrefix(a.Name.Local, "w:rsid") {
+				attrs = append(attrs, a)
+			}
+		}
+		n.attr = attrs
+		return true
+	})
+	rPr.attr = nil
+	data, _ := rPr.bytes()
+	return data
+}
+
+// appendRunText adds text of a run to the last text of another run
+func appendRunText(r, from *node) {
+	ts := r.elements("w:t")
+	last := ts[len(ts)-1]
+	var sb strings.Builder
+	sb.WriteString(last.text())
+	for _, t := range from.elements("w:t") {
+		sb.WriteString(t.text())
+	}
+	setRunText(last, sb.String())
+}
@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestMergeRuns(t *testing.T) {
+	tests := []struct {
+		body, want string
+	}{
+		{
+			// spell checking and revision IDs split a placeholder
+			`<w:p><w:r w:rsidR="00A1"><w:t xml:space="preserve">Dear [cus</w:t></w:r><w:proofErr w:type="spellStart"/>` +
+				`<w:r w:rsidR="00B2"><w:t>tomer_na</w:t></w:r><w:proofErr w:type="spellEnd"/><w:r><w:t>me]!</w:t></w:r></w:p>`,
+			`<w:p><w:r w:rsidR="00A1"><w:t xml:space="preserve">Dear Bob!</w:t></w:r></w:p>`,
+		},
+		{
+			// formatting of the first run is kept
+			`<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>[custo</w:t></w:r><w:r><w:rPr><w:i/></w:rPr><w:t>mer_name] x</w:t></w:r></w:p>`,
+			`<w:p><w:r><w:rPr><w:b></w:b></w:rPr><w:t>Bob</w:t></w:r><w:r><w:rPr><w:i></w:i></w:rPr><w:t xml:space="preserve"> x</w:t></w:r></w:p>`,
+		},
+		{
+			// formatting which differs only in revision IDs is the same
+			`<w:p><w:r><w:rPr><w:rFonts w:ascii="Arial" w:rsidR="1"/><w:b/></w:rPr><w:t>[customer_</w:t></w:r>` +
+				`<w:r><w:rPr><w:rFonts w:ascii="Arial" w:rsidR="2"/><w:b/></w:rPr><w:t>name], hi</w:t></w:r></w:p>`,
+			`<w:p><w:r><w:rPr><w:rFonts w:ascii="Arial" w:rsidR="1"></w:rFonts><w:b></w:b></w:rPr><w:t>Bob, hi</w:t></w:r></w:p>`,
+		},
+		{
+			// runs of links and insertions are merged too
+			`<w:p><w:hyperlink r:id="rId1"><w:r><w:t>[customer</w:t></w:r><w:proofErr w:type="gramEnd"/><w:r><w:t>_name]</w:t></w:r></w:hyperlink>` +
+				`<w:ins w:id="1" w:author="A"><w:r><w:t xml:space="preserve"> and [cust</w:t></w:r><w:r><w:t>omer_name]</w:t></w:r></w:ins></w:p>`,
+			`<w:p><w:hyperlink r:id="rId1"><w:r><w:t>Bob</w:t></w:r></w:hyperlink>` +
+				`<w:ins w:id="1" w:author="A"><w:r><w:t xml:space="preserve"> and Bob</w:t></w:r></w:ins></w:p>`,
+		},
+		{
+			// paragraphs without split placeholders are left as they are
+			`<w:p><w:r><w:t>[customer_name]</w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:t>x</w:t></w:r></w:p>`,
+			`<w:p><w:r><w:t>Bob</w:t></w:r><w:proofErr w:type="spellStart"></w:proofErr><w:r><w:t>x</w:t></w:r></w:p>`,
+		},
+	}
+	for _, test := range tests {
+		got := renderBody(t, newTestDocx(t, test.body, nil).ReplaceDict(Dict{"customer_name": "Bob"}))
+		if got != test.want {
+			t.Errorf("got: %s\nwant: %s", got, test.want)
+		}
+	}
+}
+
+func TestSplitVariables(t *testing.T) {
+	body := `<w:p><w:r><w:t>Dear [</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>na</w:t></w:r>` +
+		`<w:r><w:t>me], [x] and [y]!</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"[name]": "John", "x": 1, "[y]": nil})
+	got := renderBody(t, doc)
+	want := `<w:p><w:r><w:t>Dear John</w:t></w:r>` +
+		`<w:r><w:t>, 1 and !</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
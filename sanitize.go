@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharacterPolicy decides what happens with characters of values which can't be written
+// in XML, like control characters copied from other systems. Special characters like "&"
+// and "<" are always escaped
+type CharacterPolicy int
+
+// Policies of invalid characters
+const (
+	// ReplaceInvalid writes U+FFFD (�) instead of invalid characters, it's the default
+	ReplaceInvalid CharacterPolicy = iota
+	// StripInvalid removes invalid characters
+	StripInvalid
+	// RejectInvalid makes rendering fail
+	RejectInvalid
+)
+
+// InvalidCharacters sets a policy of characters of values which can't be written in XML
+// and make Word refuse to open the document
+func (doc *Docx) InvalidCharacters(policy CharacterPolicy) *Docx {
+	doc.invalidCharacters = policy
+	return doc
+}
+
+// sanitizeText applies the policy of invalid characters on text of a paragraph, they are
+// replaced by default when the part is written
+func (ctx *renderContext) sanitizeText(p *node) error {
+	for _, t := range p.find("w:t") {
+		text := t.text()
+		if isValidText(text) {
+			continue
+		}
+		if ctx.doc.invalidCharacters == RejectInvalid {
+			return fmt.Errorf("Invalid character in text %q", text)
+		}
+		var sb strings.Builder
+		for i := 0; i < len(text); {
+			r, width := utf8.DecodeRuneInString(text[i:])
+			if validRune(r, width) {
+				sb.WriteString(text[i : i+width])
+			}
+			i += width
+		}
+		setRunText(t, sb.String())
+	}
+	return nil
+}
+
+// isValidText checks if all characters of a text can be written in XML
+func isValidText(s string) bool {
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		if !validRune(r, width) {
+			return false
+		}
+		i += width
+	}
+	return true
+}
+
+// validRune checks a decoded character, a single byte error is an invalid UTF-8 sequence
+func validRune(r rune, width int) bool {
+	return isInCharacterRange(r) && !(r == utf8.RuneError && width == 1)
+}
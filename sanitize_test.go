@@ -0,0 +1,45 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInvalidCharacters(t *testing.T) {
+	dict := Dict{"name": "Tom & Jerry <\"cartoon\">\x01\x0b\xff!"}
+	for policy, want := range map[CharacterPolicy]string{
+		ReplaceInvalid: "Tom & Jerry <\"cartoon\">���!",
+		StripInvalid:   "Tom & Jerry <\"cartoon\">!",
+	} {
+		got := renderParts(t, newTestDocx(t, p("[name]"), nil).ReplaceDict(dict).InvalidCharacters(policy))
+		// the document is well-formed
+		decoder := xml.NewDecoder(strings.NewReader(got[documentXML]))
+		var text strings.Builder
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Invalid XML of policy %d: %v", policy, err)
+			}
+			if data, ok := token.(xml.CharData); ok {
+				text.Write(data)
+			}
+		}
+		if text.String() != want {
+			t.Errorf("Policy %d: got %q, want %q", policy, text.String(), want)
+		}
+	}
+	doc := newTestDocx(t, p("[name]"), nil).ReplaceDict(dict).InvalidCharacters(RejectInvalid)
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of invalid characters")
+	}
+	doc = newTestDocx(t, p("[name]"), nil).ReplaceDict(Dict{"name": "Tom & Jerry"}).InvalidCharacters(RejectInvalid)
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err != nil {
+		t.Error(err)
+	}
+}
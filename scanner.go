@@ -0,0 +1,425 @@
+package docx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// scanner is a minimal XML parser for parts of a document. It's much faster than
+// encoding/xml because it only splits elements, attributes and character data and
+// keeps names with their prefixes, like "w:p", so namespaces aren't resolved at all
+type scanner struct {
+	data []byte
+	pos  int
+	line int
+	// names are shared strings of element and attribute names which repeat a lot
+	names map[string]string
+	// nodes are allocated in chunks
+	nodes []node
+}
+
+// scan reads XML data into a tree of nodes
+func scan(data []byte) (*node, error) {
+	s := getScanner(data)
+	defer putScanner(s)
+	root := new(node)
+	current := root
+	for s.pos < len(s.data) {
+		if s.data[s.pos] != '<' {
+			end := bytes.IndexByte(s.data[s.pos:], '<')
+			if end == -1 {
+				end = len(s.data)
+			} else {
+				end += s.pos
+			}
+			text, err := s.text(s.data[s.pos:end])
+			if err != nil {
+				return nil, err
+			}
+			s.advance(end)
+			n := s.node()
+			n.data = xml.CharData(text)
+			current.append(n)
+			continue
+		}
+		rest := s.data[s.pos:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<?")):
+			end := bytes.Index(rest, []byte("?>"))
+			if end == -1 {
+				return nil, s.errorf("unexpected EOF")
+			}
+			target, inst := string(rest[2:end]), ""
+			if i := strings.IndexAny(target, " \t\r\n"); i != -1 {
+				target, inst = target[:i], strings.TrimLeft(target[i:], " \t\r\n")
+			}
+			if target == "" {
+				return nil, s.errorf("expected target name after <?")
+			}
+			s.advance(s.pos + end + 2)
+			current.append(&node{data: xml.ProcInst{Target: target, Inst: []byte(inst)}})
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest[4:], []byte("-->"))
+			if end == -1 {
+				return nil, s.errorf("unexpected EOF")
+			}
+			comment := rest[4 : 4+end]
+			s.advance(s.pos + 4 + end + 3)
+			current.append(&node{data: xml.Comment(string(comment))})
+		case bytes.HasPrefix(rest, []byte("<![CDATA[")):
+			end := bytes.Index(rest[9:], []byte("]]>"))
+			if end == -1 {
+				return nil, s.errorf("unexpected EOF in CDATA section")
+			}
+			text := string(rest[9 : 9+end])
+			s.advance(s.pos + 9 + end + 3)
+			current.append(&node{data: xml.CharData(text)})
+		case bytes.HasPrefix(rest, []byte("<!")):
+			end, err := s.directiveEnd(rest)
+			if err != nil {
+				return nil, err
+			}
+			directive := string(rest[2:end])
+			s.advance(s.pos + end + 1)
+			current.append(&node{data: xml.Directive(directive)})
+		case bytes.HasPrefix(rest, []byte("</")):
+			s.advance(s.pos + 2)
+			name := s.name()
+			if name == "" {
+				return nil, s.errorf("expected element name after </")
+			}
+			s.space()
+			if s.pos >= len(s.data) || s.data[s.pos] != '>' {
+				return nil, s.errorf("invalid characters between </ and >")
+			}
+			s.pos++
+			// broken parts are reported, not repaired
+			if current == root {
+				return nil, s.errorf("unexpected end element </%s>", name)
+			}
+			if name != current.tag {
+				return nil, s.errorf("element <%s> closed by </%s>", current.tag, name)
+			}
+			current = current.parent
+		default:
+			n, closed, err := s.element()
+			if err != nil {
+				return nil, err
+			}
+			current.append(n)
+			if !closed {
+				current = n
+			}
+		}
+	}
+	if current != root {
+		return nil, s.errorf("unexpected EOF, element <%s> isn't closed", current.tag)
+	}
+	return root, nil
+}
+
+// element reads a start tag, closed is true for an empty element like <w:b/>
+func (s *scanner) element() (n *node, closed bool, err error) {
+	s.pos++
+	n = s.node()
+	n.tag = s.name()
+	if n.tag == "" {
+		return nil, false, s.errorf("expected element name after <")
+	}
+	for {
+		s.space()
+		if s.pos >= len(s.data) {
+			return nil, false, s.errorf("unexpected EOF")
+		}
+		switch s.data[s.pos] {
+		case '>':
+			s.pos++
+			return n, false, nil
+		case '/':
+			if s.pos+1 >= len(s.data) || s.data[s.pos+1] != '>' {
+				return nil, false, s.errorf("expected /> in element")
+			}
+			s.pos += 2
+			return n, true, nil
+		}
+		name := s.name()
+		if name == "" {
+			return nil, false, s.errorf("expected attribute name in element")
+		}
+		s.space()
+		if s.pos >= len(s.data) || s.data[s.pos] != '=' {
+			return nil, false, s.errorf("attribute name without = in element")
+		}
+		s.pos++
+		s.space()
+		if s.pos >= len(s.data) || (s.data[s.pos] != '"' && s.data[s.pos] != '\'') {
+			return nil, false, s.errorf("unquoted or missing attribute value in element")
+		}
+		quote := s.data[s.pos]
+		end := bytes.IndexByte(s.data[s.pos+1:], quote)
+		if end == -1 {
+			return nil, false, s.errorf("unexpected EOF")
+		}
+		end += s.pos + 1
+		value, err := s.text(s.data[s.pos+1 : end])
+		if err != nil {
+			return nil, false, err
+		}
+		s.advance(end + 1)
+		n.attr = append(n.attr, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+	}
+}
+
+// node returns a new node
+func (s *scanner) node() *node {
+	if len(s.nodes) == 0 {
+		s.nodes = make([]node, 256)
+	}
+	n := &s.nodes[0]
+	s.nodes = s.nodes[1:]
+	return n
+}
+
+// name reads a qualified name like "w:p"
+func (s *scanner) name() string {
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n', '>', '/', '=', '<', '"', '\'':
+			return s.intern(s.data[start:s.pos])
+		}
+		s.pos++
+	}
+	return s.intern(s.data[start:s.pos])
+}
+
+// intern returns a shared string of a name
+func (s *scanner) intern(b []byte) string {
+	if name, ok := s.names[string(b)]; ok {
+		return name
+	}
+	name := string(b)
+	s.names[name] = name
+	return name
+}
+
+// space skips whitespace
+func (s *scanner) space() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\n':
+			s.line++
+		case ' ', '\t', '\r':
+		default:
+			return
+		}
+		s.pos++
+	}
+}
+
+// advance moves to a position and counts lines for error messages
+func (s *scanner) advance(pos int) {
+	s.line += bytes.Count(s.data[s.pos:pos], []byte("\n"))
+	s.pos = pos
+}
+
+// directiveEnd returns position of > which ends a directive like <!DOCTYPE ...>,
+// quoted text and nested declarations are skipped
+func (s *scanner) directiveEnd(b []byte) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 2; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '<':
+			depth++
+		case c == '>':
+			if depth == 0 {
+				return i, nil
+			}
+			depth--
+		}
+	}
+	return 0, s.errorf("unexpected EOF")
+}
+
+// text decodes entities of character data or an attribute value, line ends become \n
+func (s *scanner) text(b []byte) (string, error) {
+	if bytes.IndexByte(b, '&') == -1 && bytes.IndexByte(b, '\r') == -1 {
+		return string(b), nil
+	}
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if c == '\r' {
+			sb.WriteByte('\n')
+			if i+1 < len(b) && b[i+1] == '\n' {
+				i++
+			}
+			continue
+		}
+		if c != '&' {
+			sb.WriteByte(c)
+			continue
+		}
+		end := bytes.IndexByte(b[i:], ';')
+		if end == -1 {
+			return "", s.errorf("invalid character entity %s", b[i:])
+		}
+		entity := string(b[i+1 : i+end])
+		switch entity {
+		case "amp":
+			sb.WriteByte('&')
+		case "lt":
+			sb.WriteByte('<')
+		case "gt":
+			sb.WriteByte('>')
+		case "apos":
+			sb.WriteByte('\'')
+		case "quot":
+			sb.WriteByte('"')
+		default:
+			r, ok := charRef(entity)
+			if !ok {
+				return "", s.errorf("invalid character entity &%s;", entity)
+			}
+			sb.WriteRune(r)
+		}
+		i += end
+	}
+	return sb.String(), nil
+}
+
+// charRef decodes a numeric character reference like #34 or #x22
+func charRef(entity string) (rune, bool) {
+	if !strings.HasPrefix(entity, "#") {
+		return 0, false
+	}
+	var n uint64
+	var err error
+	if strings.HasPrefix(entity, "#x") {
+		n, err = strconv.ParseUint(entity[2:], 16, 32)
+	} else {
+		n, err = strconv.ParseUint(entity[1:], 10, 32)
+	}
+	if err != nil || !isInCharacterRange(rune(n)) {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) error {
+	return &xml.SyntaxError{Msg: fmt.Sprintf(format, args...), Line: s.line}
+}
+
+// write writes a tree of nodes as XML, text is escaped the same way as by encoding/xml
+func (n *node) write(w *bufio.Writer) {
+	switch t := n.data.(type) {
+	case xml.CharData:
+		escapeText(w, string(t), false)
+		return
+	case xml.Comment:
+		w.WriteString("<!--")
+		w.Write(t)
+		w.WriteString("-->")
+		return
+	case xml.ProcInst:
+		w.WriteString("<?")
+		w.WriteString(t.Target)
+		if len(t.Inst) > 0 {
+			w.WriteByte(' ')
+			w.Write(t.Inst)
+		}
+		w.WriteString("?>")
+		return
+	case xml.Directive:
+		w.WriteString("<!")
+		w.Write(t)
+		w.WriteByte('>')
+		return
+	}
+	if n.tag != "" {
+		w.WriteByte('<')
+		w.WriteString(n.tag)
+		for _, a := range n.attr {
+			if a.Name.Local == "" {
+				continue
+			}
+			w.WriteByte(' ')
+			w.WriteString(a.Name.Local)
+			w.WriteString(`="`)
+			escapeText(w, a.Value, true)
+			w.WriteByte('"')
+		}
+		w.WriteByte('>')
+	}
+	for _, c := range n.children {
+		c.write(w)
+	}
+	if n.tag != "" {
+		w.WriteString("</")
+		w.WriteString(n.tag)
+		w.WriteByte('>')
+	}
+}
+
+// escapeText writes text with escaped special characters,
+// new lines are escaped only in attribute values
+func escapeText(w *bufio.Writer, s string, newLines bool) {
+	last := 0
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		var esc string
+		switch r {
+		case '"':
+			esc = "&#34;"
+		case '\'':
+			esc = "&#39;"
+		case '&':
+			esc = "&amp;"
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '\t':
+			esc = "&#x9;"
+		case '\n':
+			if newLines {
+				esc = "&#xA;"
+			}
+		case '\r':
+			esc = "&#xD;"
+		default:
+			if !isInCharacterRange(r) || (r == utf8.RuneError && width == 1) {
+				esc = "\uFFFD"
+			}
+		}
+		if esc != "" {
+			w.WriteString(s[last:i])
+			w.WriteString(esc)
+			last = i + width
+		}
+		i += width
+	}
+	w.WriteString(s[last:])
+}
+
+// isInCharacterRange checks if a character can be written in XML
+func isInCharacterRange(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
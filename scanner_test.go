@@ -0,0 +1,59 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	for _, test := range []struct{ in, out string }{
+		{`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\r\n" + `<w:document xmlns:w="ns"><w:body/></w:document>`,
+			`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + `<w:document xmlns:w="ns"><w:body></w:body></w:document>`},
+		{`<w:t xml:space='preserve'> a &amp; b &lt;c&gt; &quot;d&quot; &apos;e&apos; &#8364;&#x20AC;</w:t>`,
+			`<w:t xml:space="preserve"> a &amp; b &lt;c&gt; &#34;d&#34; &#39;e&#39; €€</w:t>`},
+		{`<a b="x&#10;y	z"><![CDATA[<b>]]><!-- comment --></a >`, `<a b="x&#xA;y&#x9;z">&lt;b&gt;<!-- comment --></a>`},
+		{`<a>line` + "\n" + `tab	</a>`, `<a>line` + "\n" + `tab&#x9;</a>`},
+	} {
+		root, err := parse(strings.NewReader(test.in))
+		if err != nil {
+			t.Errorf("%s: %v", test.in, err)
+			continue
+		}
+		out, err := root.bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != test.out {
+			t.Errorf("got: %s\nwant: %s", out, test.out)
+		}
+	}
+	for _, in := range []string{`<a`, `<a b=c></a>`, `<a>&nbsp;</a>`, `<a><!-- </a>`, `<a></ a>`,
+		`<a><b></a>`, `<a><b>`, `</x><a/>`, `<a></b>`} {
+		if _, err := parse(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	var body strings.Builder
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&body, `<w:p w14:paraId="%08X"><w:pPr><w:pStyle w:val="Normal"/></w:pPr>`+
+			`<w:r><w:rPr><w:b/><w:color w:val="FF0000"/></w:rPr><w:t xml:space="preserve">Paragraph [n] of </w:t></w:r>`+
+			`<w:r><w:t>the document &amp; more</w:t></w:r></w:p>`, i)
+	}
+	data := []byte(fmt.Sprintf(testDocumentXML, body.String()))
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root, err := parse(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := root.bytes(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package docx
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// schemaField describes a variable used by a template
+type schemaField struct {
+	// typ is a JSON type like "string" or "number", it's empty when any value can be used
+	typ      string
+	required bool
+	// fields are properties of an object or of items of an array
+	fields map[string]*schemaField
+}
+
+// typeRanks decide which type wins when a variable is used in several ways,
+// e.g. [total] and [total|money:EUR] require a number
+var typeRanks = map[string]int{"": 0, "string": 1, "number": 2, "boolean": 2, "object": 3, "array": 3}
+
+// numberFilters are filters which need a number
+var numberFilters = map[string]bool{"money": true, "plural": true}
+
+// field returns a property of an object, it's added if it doesn't exist
+func (f *schemaField) field(name string) *schemaField {
+	if f.fields == nil {
+		f.fields = make(map[string]*schemaField)
+	}
+	c, ok := f.fields[name]
+	if !ok {
+		c = new(schemaField)
+		f.fields[name] = c
+	}
+	return c
+}
+
+// add registers a variable, values of dotted names like customer.name are objects
+func (f *schemaField) add(name, typ string, required bool) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		f = f.field(part)
+		f.required = f.required || required
+		if i < len(parts)-1 {
+			f.setType("object")
+		} else {
+			f.setType(typ)
+		}
+	}
+}
+
+func (f *schemaField) setType(typ string) {
+	if typeRanks[typ] > typeRanks[f.typ] {
+		f.typ = typ
+	}
+}
+
+// addExpr registers variables of an expression
+func (f *schemaField) addExpr(e expr, typ string, required bool) {
+	exprVariables(e, typ, func(name, typ string) {
+		f.add(name, typ, required)
+	})
+}
+
+// addAggregate registers collections of an aggregate like [SUM items.amount], where items are
+// an array and amounts are numbers. Missing collections leave aggregates unchanged, so they aren't required
+func (f *schemaField) addAggregate(fn, path string) {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		f = f.field(key)
+		if i < len(keys)-1 || fn == "COUNT" {
+			f.setType("array")
+		} else {
+			f.setType("number")
+		}
+	}
+}
+
+// exprVariables calls fn for all variables of an expression with their expected types,
+// variables used in arithmetic or compared with numbers are numbers
+func exprVariables(e expr, typ string, fn func(name, typ string)) {
+	switch e := e.(type) {
+	case identExpr:
+		fn(string(e), typ)
+	case unaryExpr:
+		if e.op == "-" {
+			typ = "number"
+		} else {
+			typ = ""
+		}
+		exprVariables(e.x, typ, fn)
+	case binaryExpr:
+		x, y := "", ""
+		switch e.op {
+		case "+", "-", "*", "/", "%", "<", "<=", ">", ">=":
+			x, y = "number", "number"
+		case "==", "!=":
+			x, y = literalType(e.y), literalType(e.x)
+		}
+		exprVariables(e.x, x, fn)
+		exprVariables(e.y, y, fn)
+	}
+}
+
+// literalType returns a type of a constant expression
+func literalType(e expr) string {
+	switch e.(type) {
+	case numberExpr:
+		return "number"
+	case stringExpr:
+		return "string"
+	case boolExpr:
+		return "boolean"
+	}
+	return ""
+}
+
+// templateSchema collects variables used in the document. Variables outside of conditional blocks
+// are required, fields of repeating sections and loops are fields of items of arrays
+func (doc *Docx) templateSchema() (*schemaField, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	p := newPkg(doc.zipReader)
+	if err := doc.setParts(p); err != nil {
+		return nil, err
+	}
+	root, err := p.parsePart(documentXML)
+	if err != nil {
+		return nil, err
+	}
+	doc.mustacheTags(root)
+	schema := &schemaField{typ: "object"}
+	// depth is a number of open conditional blocks, loops are items of open [#each] blocks
+	depth := 0
+	var loops []*schemaField
+	for _, par := range root.find("w:p") {
+		para := newParagraph(par)
+		for _, ph := range doc.placeholders(para.text) {
+			if para.crossesField(ph.start, ph.end) {
+				continue
+			}
+			target := schemaOf(par, schema)
+			if len(loops) > 0 {
+				target = loops[len(loops)-1]
+			}
+			if m, ok := parseMarker(ph); ok {
+				switch {
+				case m.alternative:
+				case m.end && (m.kind == "each" || m.kind == "section"):
+					if len(loops) > 0 {
+						loops = loops[:len(loops)-1]
+					}
+				case m.end:
+					if depth > 0 {
+						depth--
+					}
+				case m.kind == "each" || m.kind == "section":
+					// sections of Mustache templates can be lists, objects or flags
+					if name, _, err := parseLoopOptions(m.arg); err == nil {
+						items := target.field(name)
+						items.required = items.required || depth == 0
+						if m.kind == "each" {
+							items.setType("array")
+						}
+						loops = append(loops, items)
+					}
+				default:
+					depth++
+					if e, err := parseExpr(m.arg); err == nil {
+						target.addExpr(e, "", false)
+					}
+				}
+				continue
+			}
+			name, filters := parseFilters(ph.name)
+			typ := "string"
+			for _, f := range filters {
+				if numberFilters[f.name] {
+					typ = "number"
+				}
+			}
+			if fn, path, ok := parseAggregate(name); ok {
+				target.addAggregate(fn, path)
+			} else if e, err := parseExpr(name); err == nil {
+				target.addExpr(e, typ, depth == 0)
+			} else if name != "" {
+				target.add(name, typ, depth == 0)
+			}
+		}
+	}
+	// text controls of repeating sections are filled from fields of items
+	for _, sdt := range root.find("w:sdt") {
+		if isTextControl(sdt) {
+			if target := schemaOf(sdt, schema); target != schema {
+				target.field(sdtName(sdt)).setType("string")
+			}
+		}
+	}
+	return schema, nil
+}
+
+// schemaOf returns a schema of items of repeating sections which contain a node,
+// or the schema itself outside of them
+func schemaOf(n *node, schema *schemaField) *schemaField {
+	var sections []*node
+	for p := n.parent; p != nil; p = p.parent {
+		if p.is("w:sdt") && sdtProperty(p, "w15:repeatingSection") != nil && sdtName(p) != "" {
+			sections = append(sections, p)
+		}
+	}
+	for i := len(sections) - 1; i >= 0; i-- {
+		// options of sections like items|sort:price aren't a part of the name
+		name, _ := parseFilters(sdtName(sections[i]))
+		schema = schema.field(name)
+		schema.setType("array")
+	}
+	return schema
+}
+
+// jsonSchema returns JSON Schema of a field
+func (f *schemaField) jsonSchema() map[string]interface{} {
+	s := make(map[string]interface{})
+	if f.typ != "" {
+		s["type"] = f.typ
+	}
+	if f.typ != "object" && f.typ != "array" {
+		return s
+	}
+	object := s
+	if f.typ == "array" {
+		object = map[string]interface{}{"type": "object"}
+		s["items"] = object
+	}
+	properties := make(map[string]interface{}, len(f.fields))
+	var required []string
+	for name, c := range f.fields {
+		properties[name] = c.jsonSchema()
+		if c.required {
+			required = append(required, name)
+		}
+	}
+	object["properties"] = properties
+	if len(required) > 0 {
+		sort.Strings(required)
+		object["required"] = required
+	}
+	return s
+}
+
+// Schema returns JSON Schema of data used by the template, so forms can be built and payloads
+// validated for it. Every variable is a property, types are inferred from filters and expressions
+// (like [total|money:EUR] or [price*quantity]), repeating sections are arrays of objects
+// and variables outside of conditional blocks are required
+func (doc *Docx) Schema() ([]byte, error) {
+	schema, err := doc.templateSchema()
+	if err != nil {
+		return nil, err
+	}
+	s := schema.jsonSchema()
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return json.MarshalIndent(s, "", "  ")
+}
@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	body := p("Dear [customer.name], you owe [total|money:EUR] for [count|plural:item,items].") +
+		p("[#if discount > 0]") + p("Discount: [discount] [note]") + p("[/if]") +
+		`<w:sdt><w:sdtPr><w:tag w:val="items"/><w15:repeatingSection/></w:sdtPr><w:sdtContent>` +
+		`<w:sdt><w:sdtPr><w15:repeatingSectionItem/></w:sdtPr><w:sdtContent>` +
+		p("[name]: [price*qty]") +
+		`<w:sdt><w:sdtPr><w:tag w:val="comment"/></w:sdtPr><w:sdtContent>` + p("Comment") + `</w:sdtContent></w:sdt>` +
+		`</w:sdtContent></w:sdt></w:sdtContent></w:sdt>` +
+		p("Total of [COUNT items] items: [SUM items.price]") +
+		p("[#each lines]") + p("[text]: [amount|money:EUR]") + p("[/each]")
+	data, err := newTestDocx(t, body, nil).Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	var want interface{}
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"customer": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]},
+			"total": {"type": "number"},
+			"count": {"type": "number"},
+			"discount": {"type": "number"},
+			"note": {"type": "string"},
+			"items": {"type": "array", "items": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "price": {"type": "number"}, "qty": {"type": "number"}, "comment": {"type": "string"}},
+				"required": ["name", "price", "qty"]
+			}},
+			"lines": {"type": "array", "items": {
+				"type": "object",
+				"properties": {"text": {"type": "string"}, "amount": {"type": "number"}},
+				"required": ["amount", "text"]
+			}}
+		},
+		"required": ["count", "customer", "lines", "total"]
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %s", data)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	body := p("Dear [customer.name], you owe [total|money:EUR].") + p("[#if vip]") + p("[gift]") + p("[/if]") +
+		`<w:sdt><w:sdtPr><w:tag w:val="items"/><w15:repeatingSection/></w:sdtPr><w:sdtContent>` +
+		`<w:sdt><w:sdtPr><w15:repeatingSectionItem/></w:sdtPr><w:sdtContent>` + p("[name]: [price*qty]") +
+		`</w:sdtContent></w:sdt></w:sdtContent></w:sdt>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{
+		"customer": Dict{"name": "ACME"},
+		"total":    "12.50",
+		"items":    []Dict{{"name": "Book", "price": 10, "qty": 1.5}},
+	})
+	if err := doc.Validate(); err != nil {
+		t.Error(err)
+	}
+	doc.ReplaceDict(Dict{
+		"customer": "ACME",
+		"total":    "unknown",
+		"items":    []map[string]interface{}{{"name": "Book", "price": 10}, {"price": "free", "qty": 1}},
+	})
+	err := doc.Validate()
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	want := []string{
+		"customer must be an object, not string",
+		"missing items[0].qty",
+		"missing items[1].name",
+		"items[1].price must be a number, not string",
+		"total must be a number, not string",
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("got: %q\nwant: %q", verr.Problems, want)
+	}
+}
@@ -0,0 +1,213 @@
+package docx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// scope is a set of variables visible in a part of a document,
+// content repeated for elements of a collection gets its own scope
+type scope struct {
+	doc    *Docx
+	dict   Dict
+	parent *scope
+	// computed are results of lazy values of the dictionary
+	computed map[string]interface{}
+	// keys are normalized keys of the dictionary, see IgnoreCase and NormalizeSpace
+	keys map[string]string
+}
+
+// child creates a scope with variables of a collection element
+func (s *scope) child(dict Dict) *scope {
+	return &scope{doc: s.doc, dict: dict, parent: s}
+}
+
+// lookup finds a value of a variable by its name, starting from the innermost scope.
+// Dictionary keys may contain brackets (like "[name]") or not.
+// Values of nested dictionaries are accessed with a dot, like "customer.name"
+func (s *scope) lookup(name string) (interface{}, bool) {
+	if v, ok := s.find(name); ok {
+		return v, true
+	}
+	path := strings.Split(name, ".")
+	if len(path) == 1 {
+		return nil, false
+	}
+	v, ok := s.find(path[0])
+	for _, key := range path[1:] {
+		if !ok {
+			break
+		}
+		v, ok = s.field(v, key)
+	}
+	return v, ok
+}
+
+// find returns a value of a dictionary key
+func (s *scope) find(name string) (interface{}, bool) {
+	key := s.doc.openingBracket + name + s.doc.closingBracket
+	for ; s != nil; s = s.parent {
+		if v, ok := s.dict[key]; ok {
+			return s.evaluate(name, v), true
+		}
+		if v, ok := s.dict[name]; ok {
+			return s.evaluate(name, v), true
+		}
+		if s.doc.ignoreCase || s.doc.normalizeSpace {
+			if key, v, ok := s.findNormalized(name); ok {
+				// lazy values are computed once for all names matching the key
+				return s.evaluate(strings.TrimSuffix(strings.TrimPrefix(key, s.doc.openingBracket), s.doc.closingBracket), v), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// field returns a value of a map with string keys, like Dict
+func field(v interface{}, key string) (interface{}, bool) {
+	switch m := v.(type) {
+	case Dict:
+		v, ok := m[key]
+		return v, ok
+	case map[string]interface{}:
+		v, ok := m[key]
+		return v, ok
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	value := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+	if !value.IsValid() {
+		return nil, false
+	}
+	return value.Interface(), true
+}
+
+// resolve returns a value of a placeholder, it's looked up in the dictionary
+// or computed as arithmetic expression, and then passed through filters
+func (s *scope) resolve(name string) (interface{}, bool, error) {
+	if v, ok := s.lookup(name); ok {
+		return v, true, nil
+	}
+	name, filters := parseFilters(name)
+	v, ok := s.lookup(name)
+	if fn, path, isAggregate := parseAggregate(name); !ok && isAggregate {
+		var err error
+		if v, ok, err = s.aggregate(fn, path); err != nil {
+			return nil, false, err
+		}
+	}
+	if !ok {
+		if e, err := parseExpr(name); err == nil && s.computes(e) {
+			// a phrase like "[a/b]" with b = 0 is left as it is, like other unknown texts
+			if v, ok, err = e.eval(s.lookup); err != nil {
+				s.doc.warn(fmt.Errorf("%s%s%s is left unchanged: %v", s.doc.openingBracket, name, s.doc.closingBracket, err))
+				v, ok = nil, false
+			}
+		}
+		if !ok {
+			// it's an unknown text in brackets unless it's computed by ReplaceFunc
+			return s.compute(name, filters)
+		}
+	}
+	if len(filters) == 0 {
+		return v, true, nil
+	}
+	v, err := s.doc.applyFilters(v, filters)
+	return v, err == nil, err
+}
+
+// computes checks if an expression computes a value of variables of the dictionary,
+// like "price*quantity". Numbers and texts in brackets (like "[1]" or "[2024]") aren't expressions
+func (s *scope) computes(e expr) bool {
+	switch e.(type) {
+	case unaryExpr, binaryExpr:
+	default:
+		return false
+	}
+	found := false
+	exprVariables(e, "", func(name, typ string) {
+		if _, ok := s.lookup(name); ok {
+			found = true
+		}
+	})
+	return found
+}
+
+// compute returns a value of a placeholder given by ReplaceFunc
+func (s *scope) compute(name string, filters []filterCall) (interface{}, bool, error) {
+	if s.doc.replaceFunc == nil {
+		return nil, false, nil
+	}
+	text, ok := s.doc.replaceFunc(name)
+	if !ok || len(filters) == 0 {
+		return text, ok, nil
+	}
+	v, err := s.doc.applyFilters(text, filters)
+	return v, err == nil, err
+}
+
+// condition evaluates an expression of a conditional block, missing variables are empty
+func (s *scope) condition(expression string) (bool, error) {
+	e, err := parseExpr(expression)
+	if err != nil {
+		return false, fmt.Errorf("Invalid condition %s: %v", expression, err)
+	}
+	v, _, err := e.eval(func(name string) (interface{}, bool) {
+		v, _ := s.lookup(name)
+		return v, true
+	})
+	if err != nil {
+		return false, fmt.Errorf("Invalid condition %s: %v", expression, err)
+	}
+	return truthy(v), nil
+}
+
+// toRecords converts a collection of dictionaries (like []Dict or []map[string]string)
+// to a slice of Dict
+func toRecords(v interface{}) ([]Dict, bool) {
+	switch records := v.(type) {
+	case []Dict:
+		return records, true
+	case []map[string]interface{}:
+		dicts := make([]Dict, len(records))
+		for i, r := range records {
+			dicts[i] = r
+		}
+		return dicts, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	dicts := make([]Dict, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		dict, ok := toDict(rv.Index(i).Interface())
+		if !ok {
+			return nil, false
+		}
+		dicts = append(dicts, dict)
+	}
+	return dicts, true
+}
+
+// toDict converts a map with string keys to Dict
+func toDict(v interface{}) (Dict, bool) {
+	switch m := v.(type) {
+	case Dict:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	dict := make(Dict, rv.Len())
+	for _, key := range rv.MapKeys() {
+		dict[key.String()] = rv.MapIndex(key).Interface()
+	}
+	return dict, true
+}
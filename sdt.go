@@ -0,0 +1,248 @@
+package docx
+
+import (
+	"fmt"
+)
+
+// sdtTypes are kinds of content controls which aren't filled with text
+var sdtTypes = []string{
+	"w:picture", "w:date", "w:docPartObj", "w:docPartList", "w:group", "w:comboBox",
+	"w:dropDownList", "w14:checkbox", "w15:repeatingSection", "w15:repeatingSectionItem",
+}
+
+// repeatingSections expands repeating section content controls (w15:repeatingSection).
+// A section is bound to a collection of Dicts by its tag (or title when it has no tag),
+// the first item of the section is cloned for every record and gets variables of the record.
+// Sections which aren't found in the dictionary are left untouched
+func (ctx *renderContext) repeatingSections() error {
+	done := make(map[*node]bool)
+	for {
+		var section *node
+		ctx.root.walk(func(n *node) bool {
+			if section == nil && n.is("w:sdt") && !done[n] && sdtProperty(n, "w15:repeatingSection") != nil {
+				section = n
+			}
+			return section == nil
+		})
+		if section == nil {
+			return nil
+		}
+		done[section] = true
+		if err := ctx.repeatSection(section); err != nil {
+			return err
+		}
+	}
+}
+
+// repeatSection replaces items of a section with clones of its first item, one per record.
+// Records can be sorted and grouped by options of the tag, like items|sort:-price|group:category,
+// items tagged "header" and "footer" are put before and after records of each group
+func (ctx *renderContext) repeatSection(section *node) error {
+	name, options, err := parseLoopOptions(sdtName(section))
+	if err != nil {
+		return err
+	}
+	s := ctx.scopeOf(section)
+	v, ok := s.lookup(name)
+	if !ok {
+		return nil
+	}
+	records, ok := toRecords(v)
+	if !ok {
+		return fmt.Errorf("Invalid value of repeating section %s: %T is not a list of Dicts", name, v)
+	}
+	content := section.child("w:sdtContent")
+	if content == nil {
+		return nil
+	}
+	var detail, header, footer *node
+	var items []*node
+	for _, c := range content.children {
+		if !c.is("w:sdt") || sdtProperty(c, "w15:repeatingSectionItem") == nil {
+			continue
+		}
+		items = append(items, c)
+		switch tag := sdtName(c); {
+		case tag == "header" && header == nil:
+			header = c
+		case tag == "footer" && footer == nil:
+			footer = c
+		case detail == nil && tag != "header" && tag != "footer":
+			detail = c
+		}
+	}
+	if detail == nil {
+		return nil
+	}
+	// a section of table rows keeps the rows above it on every page
+	ctx.markHeaderRows(section)
+	if len(records) == 0 {
+		parent := section.parent
+		section.remove()
+		ensureParagraph(parent)
+		return nil
+	}
+	i := items[0].index()
+	for _, item := range items {
+		item.remove()
+	}
+	var clones []*node
+	repeat := func(item *node, scope *scope) error {
+		if item == nil {
+			return nil
+		}
+		clone := item.clone()
+		removeIDs(clone)
+		ctx.scopes[clone] = scope
+		clones = append(clones, clone)
+		return ctx.fillControls(clone)
+	}
+	for _, group := range options.groups(records) {
+		// headers and footers see the group key and records of the group
+		groupScope := s.child(Dict{name: group.records})
+		if options.group != "" {
+			groupScope.dict[options.group] = group.key
+		}
+		if err := repeat(header, groupScope); err != nil {
+			return err
+		}
+		for _, record := range group.records {
+			if err := repeat(detail, groupScope.child(record)); err != nil {
+				return err
+			}
+		}
+		if err := repeat(footer, groupScope); err != nil {
+			return err
+		}
+	}
+	content.insert(i, clones...)
+	return nil
+}
+
+// fillControls puts values of variables into text content controls of a section item
+// which are named after them, checkbox controls are ticked by bool variables.
+// Controls of nested sections are filled when they are expanded
+func (ctx *renderContext) fillControls(item *node) error {
+	content := item.child("w:sdtContent")
+	if content == nil {
+		return nil
+	}
+	var controls []*node
+	content.walk(func(n *node) bool {
+		if !n.is("w:sdt") {
+			return true
+		}
+		if sdtProperty(n, "w15:repeatingSection") != nil {
+			return false
+		}
+		if isTextControl(n) || sdtProperty(n, "w14:checkbox") != nil {
+			controls = append(controls, n)
+			return false
+		}
+		return true
+	})
+	s := ctx.scopeOf(item)
+	for _, sdt := range controls {
+		v, ok := s.lookup(sdtName(sdt))
+		if !ok {
+			continue
+		}
+		var err error
+		if sdtProperty(sdt, "w14:checkbox") == nil {
+			err = ctx.fillControl(sdt, ctx.doc.checkboxValue(v))
+		} else if checked, ok := v.(bool); ok {
+			err = ctx.tickCheckbox(sdt, checked)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillControl replaces content of a text content control with a value,
+// formatting of the first run is kept unless it's the style of placeholder text
+func (ctx *renderContext) fillControl(sdt *node, v interface{}) error {
+	content := sdt.child("w:sdtContent")
+	if content == nil {
+		return nil
+	}
+	value, err := toValue(v)
+	if err != nil {
+		return err
+	}
+	var rPr *node
+	if r := content.find("w:r"); len(r) > 0 && r[0].child("w:rPr") != nil {
+		rPr = r[0].child("w:rPr").clone()
+		if style := rPr.child("w:rStyle"); style != nil && style.attrValue("w:val") == "PlaceholderText" {
+			style.remove()
+		}
+	}
+	runs, err := value.runs(ctx, rPr)
+	if err != nil {
+		return err
+	}
+	if p := content.child("w:p"); p != nil {
+		// a block-level control keeps its first paragraph
+		for i := len(p.children) - 1; i >= 0; i-- {
+			if !p.children[i].is("w:pPr") {
+				p.children[i].remove()
+			}
+		}
+		p.append(runs...)
+		content.children = nil
+		content.append(p)
+	} else {
+		content.children = nil
+		content.append(runs...)
+	}
+	if sdtPr := sdt.child("w:sdtPr"); sdtPr != nil {
+		sdtPr.removeChild("w:showingPlcHdr")
+	}
+	return nil
+}
+
+// sdtProperty returns a child element of content control properties
+func sdtProperty(sdt *node, tag string) *node {
+	if sdtPr := sdt.child("w:sdtPr"); sdtPr != nil {
+		return sdtPr.child(tag)
+	}
+	return nil
+}
+
+// sdtName returns a tag of a content control, or its title (alias) if the tag is missing
+func sdtName(sdt *node) string {
+	if tag := sdtProperty(sdt, "w:tag"); tag != nil && tag.attrValue("w:val") != "" {
+		return tag.attrValue("w:val")
+	}
+	if alias := sdtProperty(sdt, "w:alias"); alias != nil {
+		return alias.attrValue("w:val")
+	}
+	return ""
+}
+
+// isTextControl checks if a content control is a plain or rich text one
+func isTextControl(sdt *node) bool {
+	if sdtName(sdt) == "" {
+		return false
+	}
+	for _, tag := range sdtTypes {
+		if sdtProperty(sdt, tag) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// removeIDs removes identifiers of content controls and paragraphs from cloned content,
+// they are optional and Word complains about duplicates
+func removeIDs(n *node) {
+	if n.is("w:sdtPr") {
+		n.removeChild("w:id")
+	}
+	n.removeAttr("w14:paraId")
+	n.removeAttr("w14:textId")
+	for _, c := range n.children {
+		removeIDs(c)
+	}
+}
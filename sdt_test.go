@@ -0,0 +1,140 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+// sdt returns a content control with given properties and content
+func sdt(properties, content string) string {
+	return `<w:sdt><w:sdtPr>` + properties + `</w:sdtPr><w:sdtContent>` + content + `</w:sdtContent></w:sdt>`
+}
+
+func TestRepeatingSection(t *testing.T) {
+	field := sdt(`<w:alias w:val="Name"/><w:tag w:val="name"/><w:id w:val="3"/><w:showingPlcHdr/>`,
+		`<w:r><w:rPr><w:rStyle w:val="PlaceholderText"/><w:b/></w:rPr><w:t>Click here</w:t></w:r>`)
+	item := sdt(`<w:id w:val="2"/><w15:repeatingSectionItem/>`,
+		`<w:p w14:paraId="0A0B0C0D"><w:r><w:t>[#if vip]VIP [/if]</w:t></w:r>`+field+
+			`<w:r><w:t> ([qty] × [price|money:EUR])</w:t></w:r></w:p>`)
+	body := p("Order of [customer]") +
+		sdt(`<w:tag w:val="items"/><w:id w:val="1"/><w15:repeatingSection/>`, item) +
+		p("End")
+	dict := Dict{
+		"customer": "ACME",
+		"items": []Dict{
+			{"name": "Apple", "qty": 2, "price": 1.5, "vip": true},
+			{"name": "Pear", "qty": 1, "price": 2},
+		},
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	for _, want := range []string{
+		p("Order of ACME"),
+		`<w:r><w:t xml:space="preserve">VIP </w:t></w:r><w:sdt><w:sdtPr><w:alias w:val="Name"></w:alias>` +
+			`<w:tag w:val="name"></w:tag></w:sdtPr><w:sdtContent><w:r><w:rPr><w:b></w:b></w:rPr><w:t>Apple</w:t></w:r>` +
+			`</w:sdtContent></w:sdt><w:r><w:t xml:space="preserve"> (2 × €1.50)</w:t></w:r>`,
+		`<w:t>Pear</w:t></w:r></w:sdtContent></w:sdt><w:r><w:t xml:space="preserve"> (1 × €2.00)</w:t></w:r>`,
+		p("End"),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+	if strings.Count(got, "<w15:repeatingSectionItem>") != 2 || strings.Count(got, "VIP") != 1 {
+		t.Errorf("Items weren't repeated: %s", got)
+	}
+	if strings.Contains(got, "paraId") || strings.Contains(got, `<w:id w:val="2">`) {
+		t.Errorf("IDs weren't removed from cloned items: %s", got)
+	}
+
+	dict["items"] = []map[string]string{}
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	if got != p("Order of ACME")+p("End") {
+		t.Errorf("Empty section wasn't removed: %s", got)
+	}
+
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": "ACME"}))
+	if !strings.Contains(got, "Click here") || !strings.Contains(got, "[qty]") {
+		t.Errorf("Unknown section was changed: %s", got)
+	}
+
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"items": "apples"})
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for a section which isn't a list")
+	}
+}
+
+func TestNestedRepeatingSections(t *testing.T) {
+	line := sdt(`<w:tag w:val="lines"/><w15:repeatingSection/>`,
+		sdt(`<w15:repeatingSectionItem/>`, p("[customer]: [product]")))
+	body := sdt(`<w:tag w:val="orders"/><w15:repeatingSection/>`,
+		sdt(`<w15:repeatingSectionItem/>`, p("Order [id]")+line))
+	dict := Dict{
+		"customer": "ACME",
+		"orders": []interface{}{
+			map[string]interface{}{"id": 1, "lines": []Dict{{"product": "Apple"}, {"product": "Pear"}}},
+			Dict{"id": 2, "lines": []Dict{{"product": "Plum"}}},
+		},
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	var texts []string
+	for _, part := range strings.Split(got, "<w:t>")[1:] {
+		texts = append(texts, part[:strings.Index(part, "</w:t>")])
+	}
+	want := "Order 1|ACME: Apple|ACME: Pear|Order 2|ACME: Plum"
+	if strings.Join(texts, "|") != want {
+		t.Errorf("got: %s\nwant: %s", strings.Join(texts, "|"), want)
+	}
+}
+
+func TestGroupedRepeatingSection(t *testing.T) {
+	body := sdt(`<w:tag w:val="items|sort:category,-price|group:category"/><w15:repeatingSection/>`,
+		sdt(`<w:tag w:val="header"/><w15:repeatingSectionItem/>`, p("[category]:"))+
+			sdt(`<w15:repeatingSectionItem/>`, p("[name] [price]"))+
+			sdt(`<w:tag w:val="footer"/><w15:repeatingSectionItem/>`, p("Subtotal [SUM items.price] of [COUNT items]")))
+	dict := Dict{"items": []Dict{
+		{"name": "Pear", "price": 2, "category": "Fruit"},
+		{"name": "Leek", "price": 1, "category": "Vegetables"},
+		{"name": "Apple", "price": 1.5, "category": "Fruit"},
+		{"name": "Melon", "price": 4, "category": "Fruit"},
+	}}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	var texts []string
+	for _, part := range strings.Split(got, "<w:t>")[1:] {
+		texts = append(texts, part[:strings.Index(part, "</w:t>")])
+	}
+	want := []string{"Fruit:", "Melon 4", "Pear 2", "Apple 1.5", "Subtotal 7.5 of 3", "Vegetables:", "Leek 1", "Subtotal 1 of 1"}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Errorf("got: %q\nwant: %q", texts, want)
+	}
+
+	body = sdt(`<w:tag w:val="items|sort:name"/><w15:repeatingSection/>`, sdt(`<w15:repeatingSectionItem/>`, p("[name]")))
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	if strings.Index(got, "Apple") > strings.Index(got, "Leek") || strings.Index(got, "Melon") > strings.Index(got, "Pear") {
+		t.Errorf("Items weren't sorted: %s", got)
+	}
+
+	body = sdt(`<w:tag w:val="items|shuffle"/><w15:repeatingSection/>`, sdt(`<w15:repeatingSectionItem/>`, p("[name]")))
+	if _, err := newTestDocx(t, body, nil).ReplaceDict(dict).WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for an unknown option")
+	}
+}
+
+func TestRepeatingRowsHeader(t *testing.T) {
+	row := func(text string) string { return `<w:tr><w:tc>` + p(text) + `</w:tc></w:tr>` }
+	body := `<w:tbl>` + `<w:tr><w:trPr><w:tblHeader w:val="0"/></w:trPr><w:tc>` + p("Title") + `</w:tc></w:tr>` + row("Name") +
+		sdt(`<w:tag w:val="items"/><w15:repeatingSection/>`, sdt(`<w15:repeatingSectionItem/>`, row("[name]"))) +
+		row("End") + `</w:tbl>`
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"items": []Dict{{"name": "Apple"}, {"name": "Pear"}}}))
+	want := `<w:tbl><w:tr><w:trPr><w:tblHeader w:val="0"></w:tblHeader></w:trPr><w:tc>` + p("Title") + `</w:tc></w:tr>` + row("Name")
+	if !strings.HasPrefix(got, want) || strings.Contains(got, "<w:tblHeader>") {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	body = strings.Replace(body, `<w:tblHeader w:val="0"/>`, `<w:cantSplit/>`, 1)
+	body = strings.Replace(body, row("Name"), `<w:tr><w:trPr><w:tblHeader w:val="false"/></w:trPr><w:tc>`+p("Name")+`</w:tc></w:tr>`, 1)
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"items": []Dict{{"name": "Apple"}}}))
+	want = `<w:tbl><w:tr><w:trPr><w:cantSplit></w:cantSplit><w:tblHeader></w:tblHeader></w:trPr>`
+	if !strings.HasPrefix(got, want) || strings.Count(got, "<w:tblHeader>") != 1 {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package docx
+
+import (
+	"strconv"
+)
+
+// sectPrOrder is an order of <w:sectPr> child elements required by the schema
+var sectPrOrder = []string{
+	"w:headerReference", "w:footerReference", "w:footnotePr", "w:endnotePr", "w:type", "w:pgSz",
+	"w:pgMar", "w:paperSrc", "w:pgBorders", "w:lnNumType", "w:pgNumType", "w:cols", "w:formProt",
+	"w:vAlign", "w:noEndnote", "w:titlePg", "w:textDirection", "w:bidi", "w:rtlGutter", "w:docGrid",
+	"w:printerSettings", "w:sectPrChange",
+}
+
+// sections returns section properties of all sections of the document,
+// former properties of tracked changes are skipped
+func sections(root *node) []*node {
+	var found []*node
+	for _, sectPr := range root.find("w:sectPr") {
+		if sectPr.parent == nil || !sectPr.parent.is("w:sectPrChange") {
+			found = append(found, sectPr)
+		}
+	}
+	return found
+}
+
+// LineRestart is when line numbers start again from the first number
+type LineRestart string
+
+// Restarts of line numbers
+const (
+	RestartEachPage    LineRestart = "newPage"
+	RestartEachSection LineRestart = "newSection"
+	ContinuousLines    LineRestart = "continuous"
+)
+
+// LineNumbering are settings of line numbers shown in the margin, like in legal documents
+type LineNumbering struct {
+	// CountBy shows only every n-th number, all numbers are shown by default
+	CountBy int
+	// Start is the first number, it's 1 by default
+	Start int
+	// Distance between text and numbers, it's automatic when it's not set
+	Distance Length
+	// Restart is each page by default
+	Restart LineRestart
+}
+
+// LineNumbers enables line numbering in all sections
+func (doc *Docx) LineNumbers(ln LineNumbering) *Docx {
+	doc.lineNumbers = &ln
+	return doc
+}
+
+// setLineNumbers changes line numbering of all sections
+func (ctx *renderContext) setLineNumbers() {
+	ln := ctx.doc.lineNumbers
+	if ln == nil {
+		return
+	}
+	countBy := ln.CountBy
+	if countBy < 1 {
+		countBy = 1
+	}
+	for _, sectPr := range sections(ctx.root) {
+		el := elem("w:lnNumType", "w:countBy", strconv.Itoa(countBy))
+		if ln.Start > 1 {
+			// the attribute is zero-based
+			el.setAttr("w:start", strconv.Itoa(ln.Start-1))
+		}
+		if ln.Distance > 0 {
+			el.setAttr("w:distance", strconv.Itoa(int(ln.Distance/Twip)))
+		}
+		if ln.Restart != "" {
+			el.setAttr("w:restart", string(ln.Restart))
+		}
+		sectPr.setChild(el, sectPrOrder)
+	}
+}
@@ -0,0 +1,23 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineNumbers(t *testing.T) {
+	body := `<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:lnNumType w:countBy="10"/><w:cols w:space="708"/>` +
+		`</w:sectPr></w:pPr></w:p><w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:cols w:space="708"/></w:sectPr>`
+	ln := LineNumbering{CountBy: 5, Start: 1, Distance: 10 * Point, Restart: RestartEachSection}
+	got := renderBody(t, newTestDocx(t, body, nil).LineNumbers(ln))
+	want := `<w:pgSz w:w="11906" w:h="16838"></w:pgSz>` +
+		`<w:lnNumType w:countBy="5" w:distance="200" w:restart="newSection"></w:lnNumType><w:cols w:space="708"></w:cols>`
+	if strings.Count(got, want) != 2 {
+		t.Errorf("got: %s\nwant: %s", got, want)
+	}
+
+	got = renderBody(t, newTestDocx(t, body, nil).LineNumbers(LineNumbering{Start: 5}))
+	if !strings.Contains(got, `<w:lnNumType w:countBy="1" w:start="4"></w:lnNumType>`) {
+		t.Errorf("Wrong start of line numbers: %s", got)
+	}
+}
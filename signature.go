@@ -0,0 +1,36 @@
+package docx
+
+// Signature is a picture of a signature placed inline, so it stands on the baseline
+// of the text like a handwritten one. An optional caption (like "Signed on 2024-05-01")
+// is put on the next line
+type Signature struct {
+	Image   Image
+	Caption string
+}
+
+func (s Signature) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	runs, err := s.Image.runs(ctx, rPr)
+	if err != nil || s.Caption == "" {
+		return runs, err
+	}
+	br := elem("w:r")
+	if rPr != nil {
+		br.append(rPr.clone())
+	}
+	br.append(elem("w:br"))
+	return append(runs, br, newRun(rPr, s.Caption)), nil
+}
+
+// PlaceSignature puts a signature picture at a bookmark or a placeholder with given name,
+// the first caption is shown below the signature
+func (doc *Docx) PlaceSignature(anchor string, img Image, caption ...string) *Docx {
+	s := Signature{Image: img}
+	if len(caption) > 0 {
+		s.Caption = caption[0]
+	}
+	if doc.values == nil {
+		doc.values = make(Dict)
+	}
+	doc.values[anchor] = s
+	return doc.ReplaceBookmark(anchor, s)
+}
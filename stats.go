@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const relTypeExtendedProperties = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+
+// updateStatistics recomputes statistics of the document in docProps/app.xml,
+// which would be those of the template otherwise. Pages are counted from explicit
+// page breaks and lines can't be computed without a layout, so they are removed
+func (ctx *renderContext) updateStatistics() error {
+	name, ok := ctx.pkg.relatedPart("", relTypeExtendedProperties)
+	if !ok || !ctx.pkg.has(name) {
+		return nil
+	}
+	root, ok, err := ctx.parseAuxiliaryPart(name)
+	if !ok {
+		return err
+	}
+	props := root.documentElement()
+	if props == nil {
+		return nil
+	}
+	var words, characters, spaces, paragraphs int
+	pages := 1
+	for _, p := range ctx.root.find("w:p") {
+		text := newParagraph(p).text
+		if strings.TrimSpace(text) != "" {
+			paragraphs++
+		}
+		words += len(strings.Fields(text))
+		for _, r := range text {
+			if unicode.IsSpace(r) {
+				spaces++
+			}
+		}
+		characters += utf8.RuneCountInString(text)
+		if pPr := p.child("w:pPr"); pPr != nil && pPr.child("w:pageBreakBefore") != nil {
+			pages++
+		}
+		for _, br := range p.find("w:br") {
+			if br.attrValue("w:type") == "page" {
+				pages++
+			}
+		}
+	}
+	for i, sectPr := range sections(ctx.root) {
+		// a section starts on a new page unless it's continuous
+		if t := sectPr.child("w:type"); i > 0 && (t == nil || t.attrValue("w:val") != "continuous") {
+			pages++
+		}
+	}
+	for tag, n := range map[string]int{
+		"Pages":                pages,
+		"Words":                words,
+		"Characters":           characters - spaces,
+		"CharactersWithSpaces": characters,
+		"Paragraphs":           paragraphs,
+	} {
+		if el := props.child(tag); el != nil {
+			el.setText(strconv.Itoa(n))
+		}
+	}
+	props.removeChild("Lines")
+	return ctx.pkg.setPart(name, root)
+}
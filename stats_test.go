@@ -0,0 +1,28 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	parts := map[string]string{
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeExtendedProperties + `" Target="docProps/app.xml"/>` +
+			`</Relationships>`,
+		"docProps/app.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">` +
+			`<Pages>7</Pages><Words>15</Words><Characters>119</Characters><Lines>9</Lines>` +
+			`<Paragraphs>4</Paragraphs><CharactersWithSpaces>130</CharactersWithSpaces></Properties>`,
+	}
+	body := `<w:p><w:r><w:t>Dear [name],</w:t></w:r></w:p><w:p></w:p>` +
+		`<w:p><w:r><w:br w:type="page"/><w:t xml:space="preserve">Best regards</w:t></w:r></w:p>`
+	got := renderParts(t, newTestDocx(t, body, parts).ReplaceDict(Dict{"name": "Jane Doe"}))
+	want := `<Pages>2</Pages><Words>5</Words><Characters>23</Characters>` +
+		`<Paragraphs>2</Paragraphs><CharactersWithSpaces>26</CharactersWithSpaces></Properties>`
+	if !strings.Contains(got["docProps/app.xml"], want) {
+		t.Errorf("got: %s\nwant: %s", got["docProps/app.xml"], want)
+	}
+}
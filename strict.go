@@ -0,0 +1,36 @@
+package docx
+
+import (
+	"strings"
+)
+
+// UnresolvedError lists placeholders which had no value in strict mode
+type UnresolvedError struct {
+	// Placeholders include brackets, like "[name]", each of them is listed once
+	Placeholders []string
+}
+
+func (e *UnresolvedError) Error() string {
+	return "Unresolved placeholders: " + strings.Join(e.Placeholders, ", ")
+}
+
+// Strict makes WriteTo fail with *UnresolvedError listing all placeholders without a value
+// instead of leaving them in the document, so documents aren't sent with missing data
+func (doc *Docx) Strict(enabled bool) *Docx {
+	doc.strict = enabled
+	return doc
+}
+
+// recordUnresolved remembers a placeholder without a value
+func (ctx *renderContext) recordUnresolved(ph placeholder) {
+	if !ctx.doc.strict {
+		return
+	}
+	name := ctx.doc.openingBracket + ph.name + ctx.doc.closingBracket
+	for _, u := range ctx.pkg.unresolved {
+		if u == name {
+			return
+		}
+	}
+	ctx.pkg.unresolved = append(ctx.pkg.unresolved, name)
+}
@@ -0,0 +1,34 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	body := p("[name] owes [amount] until [due].") + p("Call [phone] or [name].")
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"name": "Bob", "amount": 10}).Strict(true)
+	_, err := doc.WriteTo(new(bytes.Buffer))
+	var unresolved *UnresolvedError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("Expected UnresolvedError, got %v", err)
+	}
+	if want := []string{"[due]", "[phone]"}; !reflect.DeepEqual(unresolved.Placeholders, want) {
+		t.Errorf("got %v, want %v", unresolved.Placeholders, want)
+	}
+	if want := "Unresolved placeholders: [due], [phone]"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+
+	doc = newTestDocx(t, body, nil).ReplaceDict(Dict{"name": "Bob", "amount": 10, "due": "May", "phone": "1"}).Strict(true)
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err != nil {
+		t.Error(err)
+	}
+	// without strict mode placeholders are kept
+	doc = newTestDocx(t, body, nil).ReplaceDict(Dict{"name": "Bob"})
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err != nil {
+		t.Error(err)
+	}
+}
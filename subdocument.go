@@ -0,0 +1,578 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	relTypeNumbering      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	relTypeOfficeDocument = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	contentTypeNumbering  = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+	contentTypeStyles     = "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"
+)
+
+// SubDocument is a content of a DOCX file which is inserted into the document, like a chapter
+// kept in a separate file. It replaces the whole paragraph of a placeholder and its placeholders
+// are replaced too. Pictures, embedded objects and links are copied, lists get their own numbering,
+// drawings get unique IDs and bookmarks which collide with those of the document are renamed.
+// Footnotes and endnotes are copied when the document has notes, comments are left out.
+// Styles which the template doesn't have are copied, styles with the same IDs look like those of the template
+type SubDocument []byte
+
+func (d SubDocument) blocks(ctx *renderContext) ([]*node, error) {
+	r, err := zip.NewReader(bytes.NewReader(d), int64(len(d)))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid sub-document: %v", err)
+	}
+	src := newPkg(r)
+	defer src.close()
+	name, ok := src.relatedPart("", relTypeOfficeDocument)
+	if !ok {
+		name = documentXML
+	}
+	if !src.has(name) {
+		return nil, fmt.Errorf("Invalid sub-document: %s not found", name)
+	}
+	root, err := src.parsePart(name)
+	if err != nil {
+		return nil, err
+	}
+	body := root.find("w:body")
+	if len(body) == 0 {
+		return nil, fmt.Errorf("Invalid sub-document: document has no body")
+	}
+	var blocks []*node
+	for _, c := range body[0].children {
+		if c.tag == "" || c.is("w:sectPr") {
+			continue
+		}
+		blocks = append(blocks, c.clone())
+	}
+	imp := &subDocumentImport{
+		ctx:         ctx,
+		src:         src,
+		name:        name,
+		parts:       make(map[string]string),
+		numbering:   make(map[string]string),
+		bookmarks:   make(map[string]string),
+		bookmarkIDs: make(map[string]string),
+	}
+	for _, n := range blocks {
+		removeIDs(n)
+		if err := imp.importContent(name, ctx.part, n, true); err != nil {
+			return nil, err
+		}
+	}
+	imp.renameBookmarks(append(blocks, imp.notes...))
+	if err := imp.importStyles(append(blocks, imp.notes...)); err != nil {
+		return nil, err
+	}
+	if imp.numberingRoot != nil {
+		if err := ctx.pkg.setPart(imp.numberingName, imp.numberingRoot); err != nil {
+			return nil, err
+		}
+	}
+	if el := root.documentElement(); el != nil {
+		for _, a := range el.attr {
+			if strings.HasPrefix(a.Name.Local, "xmlns:") {
+				ctx.declareNS(strings.TrimPrefix(a.Name.Local, "xmlns:"), a.Value)
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func (d SubDocument) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	blocks, err := d.blocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) != 1 || !blocks[0].is("w:p") {
+		return nil, fmt.Errorf("Sub-document has several paragraphs, it can't be used inside text")
+	}
+	var runs []*node
+	for _, c := range blocks[0].children {
+		if !c.is("w:pPr") {
+			runs = append(runs, c)
+		}
+	}
+	return runs, nil
+}
+
+// subDocumentImport copies content of a sub-document into the rendered part
+// and gives it IDs which don't collide with those of the document
+type subDocumentImport struct {
+	ctx *renderContext
+	src *pkg
+	// name is the main part of the sub-document
+	name string
+	// parts of the sub-document mapped to their copies
+	parts map[string]string
+	// list IDs (w:numId) of the sub-document mapped to IDs of copied lists
+	numbering     map[string]string
+	numberingName string
+	numberingRoot *node
+	srcNumbering  *node
+	// abstracts are copied abstract numberings, they can refer to styles
+	abstracts []*node
+	// renamed bookmarks and new IDs of bookmarks of source parts
+	bookmarks   map[string]string
+	bookmarkIDs map[string]string
+	// imported footnotes and endnotes
+	notes []*node
+}
+
+// importContent changes IDs of content of a source part which is moved to a target part.
+// Notes are imported with the main content only, comments are removed
+func (imp *subDocumentImport) importContent(source, target string, content *node, main bool) error {
+	srcRels, err := imp.src.relationships(source)
+	if err != nil {
+		return err
+	}
+	rels, err := imp.ctx.pkg.relationships(target)
+	if err != nil {
+		return err
+	}
+	copied := make(map[string]string)
+	var removed []*node
+	var walkErr error
+	content.walk(func(n *node) bool {
+		if walkErr != nil {
+			return false
+		}
+		for i, a := range n.attr {
+			if !strings.HasPrefix(a.Name.Local, "r:") {
+				continue
+			}
+			if id, ok := copied[a.Value]; ok {
+				n.attr[i].Value = id
+				continue
+			}
+			rel, ok := srcRels.target(a.Value)
+			if !ok {
+				continue
+			}
+			if rel.TargetMode != "External" {
+				name, err := imp.copyPart(resolveTarget(source, rel.Target))
+				if err != nil {
+					walkErr = err
+					return false
+				}
+				rel.Target = relTarget(target, name)
+			}
+			copied[a.Value] = rels.addRelationship(rel)
+			n.attr[i].Value = copied[a.Value]
+		}
+		switch n.tag {
+		case "wp:docPr":
+			n.setAttr("id", strconv.Itoa(imp.ctx.nextDocPrID()))
+		case "w:bookmarkStart", "w:bookmarkEnd":
+			n.setAttr("w:id", imp.bookmarkID(source, n.attrValue("w:id")))
+			if n.is("w:bookmarkStart") {
+				n.setAttr("w:name", imp.bookmarkName(n.attrValue("w:name")))
+			}
+		case "w:numId":
+			id, err := imp.numID(n.attrValue("w:val"))
+			if err != nil {
+				walkErr = err
+				return false
+			}
+			n.setAttr("w:val", id)
+		case "w:commentRangeStart", "w:commentRangeEnd", "w:commentReference":
+			removed = append(removed, n)
+		case "w:footnoteReference", "w:endnoteReference":
+			if !main {
+				removed = append(removed, n)
+				break
+			}
+			ok, err := imp.importNote(source, n)
+			if err != nil {
+				walkErr = err
+				return false
+			}
+			if !ok {
+				removed = append(removed, n)
+			}
+		}
+		return true
+	})
+	for _, n := range removed {
+		n.remove()
+	}
+	return walkErr
+}
+
+// copyPart copies a part of the sub-document with the parts it refers to and returns name of the copy
+func (imp *subDocumentImport) copyPart(name string) (string, error) {
+	if copied, ok := imp.parts[name]; ok {
+		return copied, nil
+	}
+	data, err := imp.src.read(name)
+	if err != nil {
+		return "", err
+	}
+	ext := path.Ext(name)
+	copied := imp.ctx.pkg.uniqueName(strings.TrimRight(strings.TrimSuffix(name, ext), "0123456789"), ext)
+	imp.parts[name] = copied
+	if err := imp.ctx.pkg.set(copied, data); err != nil {
+		return "", err
+	}
+	srcTypes, err := imp.src.types()
+	if err != nil {
+		return "", err
+	}
+	types, err := imp.ctx.pkg.types()
+	if err != nil {
+		return "", err
+	}
+	if ct := srcTypes.contentType(name); ct != "" && types.contentType(copied) != ct {
+		types.addOverride(copied, ct)
+	}
+	// relationships keep their IDs, so the copied part doesn't change
+	srcRels, err := imp.src.relationships(name)
+	if err != nil || len(srcRels.Relationships) == 0 {
+		return copied, err
+	}
+	rels, err := imp.ctx.pkg.relationships(copied)
+	if err != nil {
+		return "", err
+	}
+	for _, rel := range srcRels.Relationships {
+		if rel.TargetMode != "External" {
+			target, err := imp.copyPart(resolveTarget(name, rel.Target))
+			if err != nil {
+				return "", err
+			}
+			rel.Target = relTarget(copied, target)
+		}
+		rels.Relationships = append(rels.Relationships, rel)
+	}
+	rels.modified = true
+	return copied, nil
+}
+
+// bookmarkID returns a new ID of a bookmark, the start and the end of a bookmark get the same ID
+func (imp *subDocumentImport) bookmarkID(source, id string) string {
+	key := source + ":" + id
+	if newID, ok := imp.bookmarkIDs[key]; ok {
+		return newID
+	}
+	imp.ctx.loadBookmarks()
+	imp.ctx.bookmarkID++
+	imp.bookmarkIDs[key] = strconv.Itoa(imp.ctx.bookmarkID)
+	return imp.bookmarkIDs[key]
+}
+
+// bookmarkName returns a name of a bookmark which isn't used by the document
+func (imp *subDocumentImport) bookmarkName(name string) string {
+	if renamed, ok := imp.bookmarks[name]; ok {
+		return renamed
+	}
+	imp.ctx.loadBookmarks()
+	renamed := name
+	for i := 2; imp.ctx.bookmarks[strings.ToLower(renamed)]; i++ {
+		renamed = name + "_" + strconv.Itoa(i)
+	}
+	imp.ctx.bookmarks[strings.ToLower(renamed)] = true
+	imp.bookmarks[name] = renamed
+	return renamed
+}
+
+// loadBookmarks collects names and the largest ID of bookmarks of the rendered part,
+// names are lowercase because Word ignores their case
+func (ctx *renderContext) loadBookmarks() {
+	if ctx.bookmarks != nil {
+		return
+	}
+	ctx.bookmarks = make(map[string]bool)
+	for _, n := range ctx.root.find("w:bookmarkStart") {
+		ctx.bookmarks[strings.ToLower(n.attrValue("w:name"))] = true
+		if id, err := strconv.Atoi(n.attrValue("w:id")); err == nil && id > ctx.bookmarkID {
+			ctx.bookmarkID = id
+		}
+	}
+}
+
+// bookmarkReference matches names in field instructions like REF, PAGEREF or HYPERLINK \l
+var bookmarkReference = regexp.MustCompile(`[\pL\pN_]+`)
+
+// renameBookmarks changes links and fields which refer to renamed bookmarks
+func (imp *subDocumentImport) renameBookmarks(blocks []*node) {
+	changed := make(map[string]string)
+	for name, renamed := range imp.bookmarks {
+		if name != renamed {
+			changed[strings.ToLower(name)] = renamed
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	rename := func(s string) string {
+		return bookmarkReference.ReplaceAllStringFunc(s, func(name string) string {
+			if renamed, ok := changed[strings.ToLower(name)]; ok {
+				return renamed
+			}
+			return name
+		})
+	}
+	for _, b := range blocks {
+		b.walk(func(n *node) bool {
+			switch n.tag {
+			case "w:hyperlink":
+				if anchor, ok := changed[strings.ToLower(n.attrValue("w:anchor"))]; ok {
+					n.setAttr("w:anchor", anchor)
+				}
+			case "w:fldSimple":
+				n.setAttr("w:instr", rename(n.attrValue("w:instr")))
+			case "w:instrText":
+				n.setText(rename(n.text()))
+			}
+			return true
+		})
+	}
+}
+
+// numID copies a list of the sub-document to the numbering of the document and returns its new ID
+func (imp *subDocumentImport) numID(id string) (string, error) {
+	if id == "0" || id == "" {
+		return id, nil
+	}
+	if newID, ok := imp.numbering[id]; ok {
+		return newID, nil
+	}
+	if imp.srcNumbering == nil {
+		name, ok := imp.src.relatedPart(imp.name, relTypeNumbering)
+		if !ok || !imp.src.has(name) {
+			return "0", nil
+		}
+		root, err := imp.src.parsePart(name)
+		if err != nil {
+			return "", err
+		}
+		imp.srcNumbering = root
+	}
+	num, abstract := findNum(imp.srcNumbering, id)
+	if num == nil || abstract == nil {
+		return "0", nil
+	}
+	el, err := imp.targetNumbering()
+	if err != nil {
+		return "", err
+	}
+	abstract = abstract.clone()
+	abstract.setAttr("w:abstractNumId", strconv.Itoa(maxNumberingID(el, "w:abstractNum", "w:abstractNumId")+1))
+	// lists with the same nsid are merged by Word
+	abstract.removeChild("w:nsid")
+	imp.abstracts = append(imp.abstracts, abstract)
+	if nums := el.elements("w:num"); len(nums) > 0 {
+		el.insert(nums[0].index(), abstract)
+	} else {
+		el.append(abstract)
+	}
+	num = num.clone()
+	newID := strconv.Itoa(maxNumberingID(el, "w:num", "w:numId") + 1)
+	num.setAttr("w:numId", newID)
+	num.child("w:abstractNumId").setAttr("w:val", abstract.attrValue("w:abstractNumId"))
+	if cleanup := el.child("w:numIdMacAtCleanup"); cleanup != nil {
+		el.insert(cleanup.index(), num)
+	} else {
+		el.append(num)
+	}
+	imp.numbering[id] = newID
+	return newID, nil
+}
+
+// findNum returns a list with given ID and its abstract numbering
+func findNum(root *node, id string) (*node, *node) {
+	for _, num := range root.find("w:num") {
+		if num.attrValue("w:numId") != id {
+			continue
+		}
+		ref := num.child("w:abstractNumId")
+		if ref == nil {
+			return nil, nil
+		}
+		for _, abstract := range root.find("w:abstractNum") {
+			if abstract.attrValue("w:abstractNumId") == ref.attrValue("w:val") {
+				return num, abstract
+			}
+		}
+	}
+	return nil, nil
+}
+
+// maxNumberingID returns the largest ID of numbering elements
+func maxNumberingID(el *node, tag, attr string) int {
+	max := 0
+	for _, n := range el.elements(tag) {
+		if id, err := strconv.Atoi(n.attrValue(attr)); err == nil && id > max {
+			max = id
+		}
+	}
+	return max
+}
+
+// targetNumbering returns the numbering element of the document, the part is created if it's missing
+func (imp *subDocumentImport) targetNumbering() (*node, error) {
+	if imp.numberingRoot == nil {
+		name, root, err := documentPart(imp.ctx.pkg, relTypeNumbering, contentTypeNumbering, "w:numbering")
+		if err != nil {
+			return nil, err
+		}
+		imp.numberingName, imp.numberingRoot = name, root
+	}
+	return imp.numberingRoot.documentElement(), nil
+}
+
+// documentPart parses a part related to the main document, like numbering or styles,
+// a part with an empty root element is created if it's missing
+func documentPart(p *pkg, relType, contentType, tag string) (string, *node, error) {
+	name, ok := p.relatedPart(documentXML, relType)
+	if !ok || !p.has(name) {
+		if !ok {
+			base := strings.TrimPrefix(tag, "w:")
+			name = "word/" + base + ".xml"
+			if p.has(name) {
+				name = p.uniqueName("word/"+base, ".xml")
+			}
+			rels, err := p.relationships(documentXML)
+			if err != nil {
+				return "", nil, err
+			}
+			rels.add(relType, relTarget(documentXML, name))
+		}
+		types, err := p.types()
+		if err != nil {
+			return "", nil, err
+		}
+		types.addOverride(name, contentType)
+		data := fmt.Sprintf(`%s<%s xmlns:w="%s"></%s>`, xml.Header, tag, nsW, tag)
+		if err := p.set(name, []byte(data)); err != nil {
+			return "", nil, err
+		}
+	}
+	root, err := p.parsePart(name)
+	if err != nil {
+		return "", nil, err
+	}
+	if root.documentElement() == nil {
+		return "", nil, fmt.Errorf("Invalid DOCX document: %s is empty", name)
+	}
+	return name, root, nil
+}
+
+// importStyles copies styles of the content (and styles they're based on) which the document
+// doesn't have, styles with the same IDs keep formatting of the document
+func (imp *subDocumentImport) importStyles(content []*node) error {
+	srcName, ok := imp.src.relatedPart(imp.name, relTypeStyles)
+	if !ok || !imp.src.has(srcName) {
+		return nil
+	}
+	var queue []string
+	for _, n := range append(content, imp.abstracts...) {
+		queue = append(queue, usedStyles(n)...)
+	}
+	if len(queue) == 0 {
+		return nil
+	}
+	srcRoot, err := imp.src.parsePart(srcName)
+	if err != nil {
+		return err
+	}
+	srcStyles := make(map[string]*node)
+	for _, s := range srcRoot.find("w:style") {
+		srcStyles[s.attrValue("w:styleId")] = s
+	}
+	name, root, err := documentPart(imp.ctx.pkg, relTypeStyles, contentTypeStyles, "w:styles")
+	if err != nil {
+		return err
+	}
+	el := root.documentElement()
+	known := make(map[string]bool)
+	for _, s := range el.elements("w:style") {
+		known[s.attrValue("w:styleId")] = true
+	}
+	copied := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		style, ok := srcStyles[id]
+		if known[id] || !ok {
+			continue
+		}
+		known[id] = true
+		style = style.clone()
+		for _, n := range style.find("w:numId") {
+			newID, err := imp.numID(n.attrValue("w:val"))
+			if err != nil {
+				return err
+			}
+			n.setAttr("w:val", newID)
+		}
+		el.append(style)
+		copied++
+		queue = append(queue, usedStyles(style)...)
+	}
+	if copied == 0 {
+		return nil
+	}
+	return imp.ctx.pkg.setPart(name, root)
+}
+
+// usedStyles returns IDs of styles referred to by an element and its descendants
+func usedStyles(n *node) []string {
+	var ids []string
+	n.walk(func(c *node) bool {
+		if styleReferences[c.tag] {
+			ids = append(ids, c.attrValue("w:val"))
+		}
+		return true
+	})
+	return ids
+}
+
+// importNote copies a note of the sub-document when the document has notes of the same kind,
+// the note is numbered with other notes of the document
+func (imp *subDocumentImport) importNote(source string, ref *node) (bool, error) {
+	for _, kind := range noteKinds {
+		if !ref.is(kind.reference) {
+			continue
+		}
+		target, ok := imp.ctx.pkg.relatedPart(imp.ctx.part, kind.relType)
+		if !ok || !imp.ctx.pkg.has(target) {
+			return false, nil
+		}
+		name, ok := imp.src.relatedPart(source, kind.relType)
+		if !ok || !imp.src.has(name) {
+			return false, nil
+		}
+		key := fmt.Sprintf("%p:%s", imp, name)
+		notes, ok := imp.ctx.noteParts[key]
+		if !ok {
+			root, err := imp.src.parsePart(name)
+			if err != nil {
+				return false, err
+			}
+			if err := imp.importContent(name, target, root, false); err != nil {
+				return false, err
+			}
+			imp.ctx.noteParts[key] = root
+			imp.notes = append(imp.notes, root)
+			notes = root
+		}
+		for _, n := range notes.find(kind.note) {
+			if n.attrValue("w:id") == ref.attrValue("w:id") {
+				imp.ctx.noteSources[ref] = key
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// numberingPart returns a numbering part with a single list
+func numberingPart(numID, abstractID string) string {
+	return `<w:numbering xmlns:w="w"><w:abstractNum w:abstractNumId="` + abstractID + `"><w:nsid w:val="1234ABCD"/>` +
+		`<w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="` + numID + `"><w:abstractNumId w:val="` + abstractID + `"/></w:num></w:numbering>`
+}
+
+func TestSubDocument(t *testing.T) {
+	const hyperlinkType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+	rels := func(rels string) string {
+		return `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels + `</Relationships>`
+	}
+	body := `<w:p><w:bookmarkStart w:id="0" w:name="Intro"/><w:r><w:t>Chapter</w:t></w:r><w:bookmarkEnd w:id="0"/></w:p>` +
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="3"/></w:numPr></w:pPr>` +
+		`<w:commentRangeStart w:id="0"/><w:r><w:t>See </w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+		`<w:fldSimple w:instr=" REF intro \h "><w:r><w:t>Chapter</w:t></w:r></w:fldSimple>` +
+		`<w:r><w:footnoteReference w:id="1"/></w:r></w:p>` +
+		`<w:p><w:hyperlink r:id="rId2" w:history="1"><w:r><w:t>[site]</w:t></w:r></w:hyperlink>` +
+		`<w:r><w:drawing><wp:docPr id="1"/><a:blip r:embed="rId1"/></w:drawing></w:r></w:p>` +
+		`<w:sectPr/>`
+	sub := newTestDocx(t, body, map[string]string{
+		"word/_rels/document.xml.rels": rels(
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/>` +
+				`<Relationship Id="rId2" Type="` + hyperlinkType + `" Target="https://example.com" TargetMode="External"/>` +
+				`<Relationship Id="rId3" Type="` + relTypeNumbering + `" Target="numbering.xml"/>`),
+		"word/media/image1.png": "chapter picture",
+		"word/numbering.xml":    numberingPart("3", "0"),
+	})
+	var data bytes.Buffer
+	if _, err := sub.WriteTemplate(&data); err != nil {
+		t.Fatal(err)
+	}
+
+	template := `<w:p><w:bookmarkStart w:id="4" w:name="intro"/><w:r><w:t>Title</w:t></w:r><w:bookmarkEnd w:id="4"/></w:p>` +
+		p("[chapter]") +
+		`<w:p><w:r><w:drawing><wp:docPr id="2"/><a:blip r:embed="rId1"/></w:drawing></w:r></w:p>`
+	doc := newTestDocx(t, template, map[string]string{
+		"word/_rels/document.xml.rels": rels(
+			`<Relationship Id="rId1" Type="` + relTypeImage + `" Target="media/image1.png"/>` +
+				`<Relationship Id="rId2" Type="` + relTypeNumbering + `" Target="numbering.xml"/>`),
+		"word/media/image1.png": "template picture",
+		"word/numbering.xml":    numberingPart("1", "0"),
+	}).ReplaceDict(Dict{"chapter": SubDocument(data.Bytes()), "site": "ACME"})
+	got := renderParts(t, doc)
+
+	want := `<w:p><w:bookmarkStart w:id="5" w:name="Intro_2"></w:bookmarkStart><w:r><w:t>Chapter</w:t></w:r><w:bookmarkEnd w:id="5"></w:bookmarkEnd></w:p>` +
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"></w:ilvl><w:numId w:val="2"></w:numId></w:numPr></w:pPr>` +
+		`<w:r><w:t>See </w:t></w:r>` +
+		`<w:fldSimple w:instr=" REF Intro_2 \h "><w:r><w:t>Chapter</w:t></w:r></w:fldSimple><w:r></w:r></w:p>` +
+		`<w:p><w:hyperlink r:id="rId3" w:history="1"><w:r><w:t>ACME</w:t></w:r></w:hyperlink>` +
+		`<w:r><w:drawing><wp:docPr id="3"></wp:docPr><a:blip r:embed="rId4"></a:blip></w:drawing></w:r></w:p>` +
+		`<w:p><w:r><w:drawing><wp:docPr id="2"></wp:docPr><a:blip r:embed="rId1"></a:blip></w:drawing></w:r></w:p>`
+	if !strings.Contains(got[documentXML], want) {
+		t.Errorf("got: %s\nwant: %s", got[documentXML], want)
+	}
+	for _, rel := range []string{
+		`Id="rId3" Type="` + hyperlinkType + `" Target="https://example.com" TargetMode="External"`,
+		`Id="rId4" Type="` + relTypeImage + `" Target="media/image2.png"`,
+	} {
+		if !strings.Contains(got["word/_rels/document.xml.rels"], rel) {
+			t.Errorf("Relationship %s wasn't added: %s", rel, got["word/_rels/document.xml.rels"])
+		}
+	}
+	if got["word/media/image1.png"] != "template picture" || got["word/media/image2.png"] != "chapter picture" {
+		t.Errorf("Unexpected pictures: %q, %q", got["word/media/image1.png"], got["word/media/image2.png"])
+	}
+	numbering := `<w:abstractNum w:abstractNumId="0"><w:nsid w:val="1234ABCD"></w:nsid><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"></w:numFmt></w:lvl></w:abstractNum>` +
+		`<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"></w:numFmt></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"></w:abstractNumId></w:num>` +
+		`<w:num w:numId="2"><w:abstractNumId w:val="1"></w:abstractNumId></w:num>`
+	if !strings.Contains(got["word/numbering.xml"], numbering) {
+		t.Errorf("got: %s\nwant: %s", got["word/numbering.xml"], numbering)
+	}
+
+	// numbering is created when the template has no lists
+	doc = newTestDocx(t, p("[chapter]"), nil).ReplaceDict(Dict{"chapter": SubDocument(data.Bytes())})
+	got = renderParts(t, doc)
+	if !strings.Contains(got["word/numbering.xml"], `<w:num w:numId="1"><w:abstractNumId w:val="1"></w:abstractNumId></w:num>`) {
+		t.Errorf("Numbering wasn't created: %s", got["word/numbering.xml"])
+	}
+	if !strings.Contains(got["word/_rels/document.xml.rels"], `Type="`+relTypeNumbering+`" Target="numbering.xml"`) ||
+		!strings.Contains(got[contentTypesXML], `PartName="/word/numbering.xml" ContentType="`+contentTypeNumbering+`"`) {
+		t.Errorf("Numbering part wasn't registered: %s\n%s", got["word/_rels/document.xml.rels"], got[contentTypesXML])
+	}
+
+	// notes are copied when the template has notes
+	footnotes := func(notes string) map[string]string {
+		return map[string]string{
+			"word/_rels/document.xml.rels": rels(`<Relationship Id="rId1" Type="` + relTypeFootnotes + `" Target="footnotes.xml"/>`),
+			"word/footnotes.xml": `<w:footnotes xmlns:w="w"><w:footnote w:type="separator" w:id="0"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` +
+				notes + `</w:footnotes>`,
+		}
+	}
+	sub = newTestDocx(t, `<w:p><w:r><w:t>Chapter</w:t></w:r><w:r><w:footnoteReference w:id="1"/></w:r></w:p>`,
+		footnotes(`<w:footnote w:id="1"><w:p><w:r><w:t>Chapter note</w:t></w:r></w:p></w:footnote>`))
+	data.Reset()
+	if _, err := sub.WriteTemplate(&data); err != nil {
+		t.Fatal(err)
+	}
+	body = `<w:p><w:r><w:footnoteReference w:id="1"/></w:r></w:p>` + p("[chapter]")
+	doc = newTestDocx(t, body, footnotes(`<w:footnote w:id="1"><w:p><w:r><w:t>Title note</w:t></w:r></w:p></w:footnote>`)).
+		ReplaceDict(Dict{"chapter": SubDocument(data.Bytes())})
+	got = renderParts(t, doc)
+	want = `<w:footnote w:id="1"><w:p><w:r><w:t>Title note</w:t></w:r></w:p></w:footnote>` +
+		`<w:footnote w:id="2"><w:p><w:r><w:t>Chapter note</w:t></w:r></w:p></w:footnote>`
+	if !strings.Contains(got["word/footnotes.xml"], want) || !strings.Contains(got[documentXML], `<w:footnoteReference w:id="2">`) {
+		t.Errorf("Note wasn't copied: %s\n%s", got[documentXML], got["word/footnotes.xml"])
+	}
+
+	doc = newTestDocx(t, p("[chapter]"), nil).ReplaceDict(Dict{"chapter": SubDocument("not a document")})
+	if _, err := doc.WriteTo(new(bytes.Buffer)); err == nil {
+		t.Error("Expected error of an invalid sub-document")
+	}
+}
@@ -0,0 +1,164 @@
+package docx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+const (
+	svgContentType = "image/svg+xml"
+	// svgExtension is the URI of the blip extension with an SVG picture
+	svgExtension = "{96DAC541-7B7A-43D3-8B79-37D633B846F1}"
+	nsSVG        = "http://schemas.microsoft.com/office/drawing/2016/SVG/main"
+	// maxFallbackSize limits pixels of generated fallback pictures
+	maxFallbackSize = 1024
+)
+
+// isSVG checks if data is an SVG document
+func isSVG(data []byte) bool {
+	root, ok := svgElement(data)
+	return ok && root.Name.Local == "svg"
+}
+
+// svgElement returns the root element of an XML document
+func svgElement(data []byte) (xml.StartElement, bool) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Strict = false
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, false
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return t, true
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return xml.StartElement{}, false
+			}
+		}
+	}
+}
+
+// svgSize returns dimensions of an SVG picture from its width and height,
+// or from its viewBox when they are missing or relative
+func svgSize(data []byte) (Length, Length, error) {
+	root, ok := svgElement(data)
+	if !ok {
+		return 0, 0, fmt.Errorf("Invalid SVG picture")
+	}
+	var width, height, viewBox string
+	for _, a := range root.Attr {
+		switch a.Name.Local {
+		case "width":
+			width = a.Value
+		case "height":
+			height = a.Value
+		case "viewBox":
+			viewBox = a.Value
+		}
+	}
+	w, okW := svgLength(width)
+	h, okH := svgLength(height)
+	if okW && okH {
+		return w, h, nil
+	}
+	box := strings.FieldsFunc(viewBox, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(box) == 4 {
+		bw, err1 := strconv.ParseFloat(box[2], 64)
+		bh, err2 := strconv.ParseFloat(box[3], 64)
+		if err1 == nil && err2 == nil && bw > 0 && bh > 0 {
+			// a single dimension is scaled by the aspect ratio of the view box
+			switch {
+			case okW:
+				return w, Length(float64(w) * bh / bw), nil
+			case okH:
+				return Length(float64(h) * bw / bh), h, nil
+			}
+			return Length(bw * float64(Pixel)), Length(bh * float64(Pixel)), nil
+		}
+	}
+	// browsers use 300x150 pixels for pictures without a size
+	return 300 * Pixel, 150 * Pixel, nil
+}
+
+// svgUnits are lengths of units of SVG dimensions
+var svgUnits = map[string]Length{
+	"": Pixel, "px": Pixel, "pt": Point, "pc": 12 * Point, "in": Inch, "cm": Centimeter, "mm": Millimeter,
+}
+
+// svgLength parses an absolute SVG dimension like "120", "12px" or "3cm"
+func svgLength(s string) (Length, bool) {
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for i > 0 && (s[i-1] < '0' || s[i-1] > '9') && s[i-1] != '.' {
+		i--
+	}
+	unit, ok := svgUnits[s[i:]]
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return Length(v * float64(unit)), true
+}
+
+// addSVGFallback moves an SVG picture of a blip to its extension and points the blip to a PNG picture
+func (img Image) addSVGFallback(ctx *renderContext, blip *node) error {
+	fallback := img.Fallback
+	if fallback == nil {
+		var err error
+		if fallback, err = img.blankFallback(); err != nil {
+			return err
+		}
+	}
+	if contentType := imageContentType(fallback); contentType != "image/png" {
+		return fmt.Errorf("Unsupported fallback image type: %s", contentType)
+	}
+	relID, err := ctx.addMedia(fallback, "png", "image/png")
+	if err != nil {
+		return err
+	}
+	svg := elem("asvg:svgBlip", "xmlns:asvg", nsSVG, "r:embed", blip.attrValue("r:embed"))
+	blip.setAttr("r:embed", relID)
+	ext := elem("a:ext", "uri", svgExtension)
+	ext.append(svg)
+	extLst := elem("a:extLst")
+	extLst.append(ext)
+	blip.append(extLst)
+	return nil
+}
+
+// blankFallback returns a transparent PNG picture of the size of an SVG picture
+func (img Image) blankFallback() ([]byte, error) {
+	width, height, err := img.nativeSize()
+	if err != nil {
+		return nil, err
+	}
+	w, h := int(width/Pixel), int(height/Pixel)
+	if w > maxFallbackSize || h > maxFallbackSize {
+		if w > h {
+			w, h = maxFallbackSize, h*maxFallbackSize/w
+		} else {
+			w, h = w*maxFallbackSize/h, maxFallbackSize
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
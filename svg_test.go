@@ -0,0 +1,61 @@
+package docx
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSVGImage(t *testing.T) {
+	svg := `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="2cm" height="1cm"><rect width="10" height="5"/></svg>`
+	body := `<w:p><w:r><w:t>Logo: [logo]</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"logo": Image{Data: []byte(svg)}})
+	parts := renderParts(t, doc)
+	if parts["word/media/image1.svg"] != svg {
+		t.Error("SVG picture wasn't stored in the archive")
+	}
+	fallback, err := png.DecodeConfig(strings.NewReader(parts["word/media/image1.png"]))
+	if err != nil || fallback.Width != 75 || fallback.Height != 37 {
+		t.Errorf("Unexpected fallback picture %+v: %v", fallback, err)
+	}
+	want := `<a:blip r:embed="rId2"><a:extLst><a:ext uri="{96DAC541-7B7A-43D3-8B79-37D633B846F1}">` +
+		`<asvg:svgBlip xmlns:asvg="http://schemas.microsoft.com/office/drawing/2016/SVG/main" r:embed="rId1"></asvg:svgBlip>` +
+		`</a:ext></a:extLst></a:blip>`
+	for _, s := range []string{`<wp:extent cx="720000" cy="360000">`, want} {
+		if !strings.Contains(parts[documentXML], s) {
+			t.Errorf("Can't find %s in %s", s, parts[documentXML])
+		}
+	}
+	if !strings.Contains(parts[contentTypesXML], `<Default Extension="svg" ContentType="image/svg+xml">`) {
+		t.Errorf("Content type wasn't added: %s", parts[contentTypesXML])
+	}
+
+	custom := testPNG(t, 4, 2)
+	doc = newTestDocx(t, body, nil).ReplaceDict(Dict{"logo": Image{Data: []byte(svg), Fallback: custom.Bytes()}})
+	if parts := renderParts(t, doc); parts["word/media/image1.png"] != custom.String() {
+		t.Error("Fallback picture wasn't used")
+	}
+}
+
+func TestSVGSize(t *testing.T) {
+	for svg, want := range map[string][2]Length{
+		`<svg width="100" height="50px"/>`:                      {100 * Pixel, 50 * Pixel},
+		`<svg width="1in" height="72pt"/>`:                      {Inch, Inch},
+		`<svg viewBox="0 0 200 100"/>`:                          {200 * Pixel, 100 * Pixel},
+		`<svg width="100%" height="100%" viewBox="0,0,20,10"/>`: {20 * Pixel, 10 * Pixel},
+		`<svg width="4cm" viewBox="0 0 20 10"/>`:                {4 * Centimeter, 2 * Centimeter},
+		`<svg/>`:                                                {300 * Pixel, 150 * Pixel},
+	} {
+		if !isSVG([]byte(svg)) {
+			t.Errorf("%s wasn't detected", svg)
+		}
+		w, h, err := svgSize([]byte(svg))
+		if err != nil || w != want[0] || h != want[1] {
+			t.Errorf("%s: got %d x %d (%v), want %d x %d", svg, w, h, err, want[0], want[1])
+		}
+	}
+	if isSVG([]byte(`<html><svg/></html>`)) || isSVG(testPNG(t, 1, 1).Bytes()) || isSVG(bytes.Repeat([]byte("svg"), 10)) {
+		t.Error("Other data was detected as SVG")
+	}
+}
@@ -0,0 +1,117 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// trPrOrder is an order of <w:trPr> child elements required by the schema
+var trPrOrder = []string{
+	"w:cnfStyle", "w:divId", "w:gridBefore", "w:gridAfter", "w:wBefore", "w:wAfter", "w:cantSplit",
+	"w:trHeight", "w:tblHeader", "w:tblCellSpacing", "w:jc", "w:hidden", "w:ins", "w:del", "w:trPrChange",
+}
+
+// rowProperties returns <w:trPr> of a table row, it's added if it's missing
+func rowProperties(tr *node) *node {
+	if trPr := tr.child("w:trPr"); trPr != nil {
+		return trPr
+	}
+	trPr := elem("w:trPr")
+	i := 0
+	if tblPrEx := tr.child("w:tblPrEx"); tblPrEx != nil {
+		i = tblPrEx.index() + 1
+	}
+	tr.insert(i, trPr)
+	return trPr
+}
+
+// markHeaderRows makes rows above the first repeated row (or a repeated section of rows) of a table
+// header rows, which Word repeats at the top of every page of the table. Rows below it, like
+// other repeated rows, aren't headers. Header rows have to start at the top of the table, so rows
+// below a row which is explicitly not a header aren't marked
+func (ctx *renderContext) markHeaderRows(first *node) {
+	tbl := first.parent
+	if tbl == nil || !tbl.is("w:tbl") || ctx.repeatedTables[tbl] {
+		return
+	}
+	ctx.repeatedTables[tbl] = true
+	for _, c := range tbl.children[:first.index()] {
+		if !c.is("w:tr") {
+			continue
+		}
+		if trPr := c.child("w:trPr"); trPr != nil && trPr.child("w:tblHeader") != nil {
+			switch trPr.child("w:tblHeader").attrValue("w:val") {
+			case "0", "false", "off":
+				return
+			}
+			continue
+		}
+		rowProperties(c).setChild(elem("w:tblHeader"), trPrOrder)
+	}
+}
+
+// repeatRows repeats table rows with placeholders of fields of a list of records, like [item.price]
+// when item is a []Dict, once per record. Rows of an empty list are removed, like tables
+// without other rows
+func (ctx *renderContext) repeatRows() error {
+	for _, tr := range ctx.root.find("w:tr") {
+		// rows of removed rows, like nested tables of repeated rows, are skipped
+		if tr.root() != ctx.root {
+			continue
+		}
+		record, records, ok, err := ctx.rowRecords(tr)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		tbl := tr.parent
+		ctx.repeatRow(tr, record, records)
+		// a table without rows is invalid
+		if tbl.is("w:tbl") && len(tbl.elements("w:tr")) == 0 {
+			parent := tbl.parent
+			tbl.remove()
+			ensureParagraph(parent)
+		}
+	}
+	return nil
+}
+
+// rowRecords returns records shown by a row, the name of the record is the first part
+// of a placeholder which refers to a list, like item of [item.price]. Records of rows
+// are shown in their order, [#each] and repeating sections sort and group them
+func (ctx *renderContext) rowRecords(tr *node) (string, []Dict, bool, error) {
+	s := ctx.scopeOf(tr)
+	for _, p := range tr.find("w:p") {
+		if p.ancestor("w:tr") != tr {
+			continue
+		}
+		for _, ph := range ctx.doc.placeholders(newParagraph(p).text) {
+			if _, ok := parseMarker(ph); ok {
+				continue
+			}
+			name, filters := parseFilters(ph.name)
+			i := strings.IndexByte(name, '.')
+			if i <= 0 {
+				continue
+			}
+			v, ok := s.lookup(name[:i])
+			if !ok {
+				continue
+			}
+			records, ok := toRecords(v)
+			if !ok {
+				continue
+			}
+			for _, f := range filters {
+				if f.name == "sort" || f.name == "group" {
+					return "", nil, false, fmt.Errorf("Rows of %s can't be sorted or grouped by %s%s%s, records are sorted by loops and grouped by repeating sections",
+						name[:i], ctx.doc.openingBracket, ph.name, ctx.doc.closingBracket)
+				}
+			}
+			return name[:i], records, true, nil
+		}
+	}
+	return "", nil, false, nil
+}
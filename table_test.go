@@ -0,0 +1,45 @@
+package docx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRepeatRows(t *testing.T) {
+	cell := func(text string) string {
+		return `<w:tc>` + p(text) + `</w:tc>`
+	}
+	body := `<w:tbl>` +
+		`<w:tr>` + cell("Item") + cell("Price") + `</w:tr>` +
+		`<w:tr>` + cell("[item.name]") + cell("[item.price|money:EUR]") + `</w:tr>` +
+		`<w:tr>` + cell("Total") + cell("[total]") + `</w:tr>` +
+		`</w:tbl>` +
+		`<w:tbl><w:tr>` + cell("[note.text]") + `</w:tr></w:tbl>`
+	dict := Dict{
+		"item":  []Dict{{"name": "Book", "price": 10.5}, {"name": "Pen", "price": 2}},
+		"note":  []map[string]interface{}{},
+		"total": "€12.50",
+	}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict))
+	want := `<w:tbl>` +
+		`<w:tr><w:trPr><w:tblHeader></w:tblHeader></w:trPr>` + cell("Item") + cell("Price") + `</w:tr>` +
+		`<w:tr>` + cell("Book") + cell("€10.50") + `</w:tr>` +
+		`<w:tr>` + cell("Pen") + cell("€2.00") + `</w:tr>` +
+		`<w:tr>` + cell("Total") + cell("€12.50") + `</w:tr>` +
+		`</w:tbl>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSortedRows(t *testing.T) {
+	dict := Dict{"item": []Dict{{"name": "Book", "price": 10.5}, {"name": "Pen", "price": 2}}}
+	for _, option := range []string{"sort:price", "group:name"} {
+		body := `<w:tbl><w:tr><w:tc>` + p("[item.name|"+option+"]") + `</w:tc></w:tr></w:tbl>`
+		_, err := newTestDocx(t, body, nil).ReplaceDict(dict).WriteTo(new(bytes.Buffer))
+		if err == nil || !strings.Contains(err.Error(), "can't be sorted or grouped") {
+			t.Errorf("%s: expected error of sorted rows, got %v", option, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package docx
+
+import (
+	"strings"
+)
+
+// Tabs turns tab characters of replaced values into tabs of Word, so text is aligned at tab
+// stops of the paragraph like when it's typed. Rich text and formatted values always use them
+func (doc *Docx) Tabs(enabled bool) *Docx {
+	doc.tabs = enabled
+	return doc
+}
+
+// expandTabs replaces tab characters in text of a paragraph with tab elements
+func expandTabs(p *node) {
+	for _, t := range p.find("w:t") {
+		if t.ancestor("w:p") != p || !strings.Contains(t.text(), "\t") {
+			continue
+		}
+		var nodes []*node
+		for i, s := range strings.Split(t.text(), "\t") {
+			if i > 0 {
+				nodes = append(nodes, elem("w:tab"))
+			}
+			if s != "" {
+				text := elem("w:t")
+				setRunText(text, s)
+				nodes = append(nodes, text)
+			}
+		}
+		t.replace(nodes...)
+	}
+}
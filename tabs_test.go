@@ -0,0 +1,16 @@
+package docx
+
+import (
+	"testing"
+)
+
+func TestTabs(t *testing.T) {
+	body := `<w:p><w:r><w:rPr><w:b/></w:rPr><w:t>Total:[total]</w:t></w:r></w:p>` + p("\t[name]")
+	dict := Dict{"total": "\t10\t€", "name": "Bob\n\tSmith"}
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(dict).Tabs(true).LineBreaks(true))
+	want := `<w:p><w:r><w:rPr><w:b></w:b></w:rPr><w:t>Total:</w:t><w:tab></w:tab><w:t>10</w:t><w:tab></w:tab><w:t>€</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:tab></w:tab><w:t>Bob</w:t><w:br></w:br><w:tab></w:tab><w:t>Smith</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+}
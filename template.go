@@ -0,0 +1,142 @@
+package docx
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Template is a snapshot of a configured document which renders documents with different
+// dictionaries, it's safe for concurrent use, e.g. by handlers of HTTP requests:
+//
+//	tmpl, err := docx.NewTemplate(doc.Delimiters("{{", "}}").Locale("de-DE"))
+//	...
+//	_, err = tmpl.Render(docx.Dict{"name": name}, w)
+type Template struct {
+	doc *Docx
+}
+
+// NewTemplate takes a snapshot of options of a document, changes of the document made later
+// don't affect the template. An error of the document (like an invalid archive) is returned at once
+func NewTemplate(doc *Docx) (*Template, error) {
+	if doc.err != nil {
+		return nil, doc.err
+	}
+	return &Template{doc: doc.snapshot()}, nil
+}
+
+// RenderResult is a result of rendering a template
+type RenderResult struct {
+	Written int64
+	// Warnings are problems which didn't stop rendering in resilient mode, like Docx.Warnings
+	Warnings []error
+}
+
+// Render writes a document with a dictionary, which may be nil to use the dictionary of the template
+func (t *Template) Render(dict Dict, w io.Writer) (RenderResult, error) {
+	return t.RenderContext(context.Background(), dict, w)
+}
+
+// RenderContext writes a document like Render and stops when the context is done, like WriteToContext
+func (t *Template) RenderContext(ctx context.Context, dict Dict, w io.Writer) (RenderResult, error) {
+	doc := t.doc.withDict(dict)
+	n, err := doc.WriteToContext(ctx, w)
+	return RenderResult{Written: n, Warnings: doc.warnings}, err
+}
+
+// withDict returns a copy of a document rendered with another dictionary, so documents
+// sharing the template don't affect each other. Options of the copy must not be changed
+func (doc *Docx) withDict(dict Dict) *Docx {
+	c := *doc
+	c.warnings = nil
+	if dict != nil {
+		c.dict = dict
+	}
+	return &c
+}
+
+// snapshot returns a copy of a document with its own copies of options kept in maps,
+// which are changed in place by methods of the document
+func (doc *Docx) snapshot() *Docx {
+	c := *doc
+	c.warnings = nil
+	c.dict = copyMap(doc.dict)
+	c.values = copyMap(doc.values)
+	c.bookmarks = copyMap(doc.bookmarks)
+	if doc.filters != nil {
+		c.filters = make(map[string]Filter, len(doc.filters))
+		for k, v := range doc.filters {
+			c.filters[k] = v
+		}
+	}
+	if doc.formats != nil {
+		c.formats = make(map[string][]FormatRule, len(doc.formats))
+		for k, v := range doc.formats {
+			c.formats[k] = v[:len(v):len(v)]
+		}
+	}
+	if doc.pictures != nil {
+		c.pictures = make(map[string]Image, len(doc.pictures))
+		for k, v := range doc.pictures {
+			c.pictures[k] = v
+		}
+	}
+	if doc.images != nil {
+		c.images = make(map[string]Image, len(doc.images))
+		for k, v := range doc.images {
+			c.images[k] = v
+		}
+	}
+	if doc.partials != nil {
+		c.partials = make(map[string]SubDocument, len(doc.partials))
+		for k, v := range doc.partials {
+			c.partials[k] = v
+		}
+	}
+	if doc.parts != nil {
+		c.parts = make(map[string][]byte, len(doc.parts))
+		for k, v := range doc.parts {
+			c.parts[k] = v
+		}
+	}
+	if doc.removed != nil {
+		c.removed = make(map[string]bool, len(doc.removed))
+		for k, v := range doc.removed {
+			c.removed[k] = v
+		}
+	}
+	if doc.limits != nil {
+		c.limits = make(map[string]int, len(doc.limits))
+		for k, v := range doc.limits {
+			c.limits[k] = v
+		}
+	}
+	if doc.dates != nil {
+		c.dates = make(map[string]time.Time, len(doc.dates))
+		for k, v := range doc.dates {
+			c.dates[k] = v
+		}
+	}
+	if doc.goTemplate != nil {
+		t := *doc.goTemplate
+		if t.funcs != nil {
+			t.funcs = make(map[string]interface{}, len(doc.goTemplate.funcs))
+			for k, v := range doc.goTemplate.funcs {
+				t.funcs[k] = v
+			}
+		}
+		c.goTemplate = &t
+	}
+	return &c
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
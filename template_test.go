@@ -0,0 +1,85 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	doc := newTestDocx(t, p("Hello [name|shout]"), nil).
+		Filter("shout", func(v interface{}, args []string, locale string) (interface{}, error) {
+			return fmt.Sprint(v) + "!", nil
+		}).
+		ReplaceDict(Dict{"name": "nobody"})
+	tmpl, err := NewTemplate(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// changes of the document don't affect the template
+	doc.Filter("shout", func(v interface{}, args []string, locale string) (interface{}, error) {
+		return "changed", nil
+	})
+	var wg sync.WaitGroup
+	outputs := make([]bytes.Buffer, 8)
+	errs := make([]error, len(outputs))
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = tmpl.Render(Dict{"name": fmt.Sprintf("user%d", i)}, &outputs[i])
+		}(i)
+	}
+	wg.Wait()
+	for i := range outputs {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		doc, err := NewFromBytes(outputs[i].Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := renderBody(t, doc), fmt.Sprintf("Hello user%d!", i); !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := tmpl.Render(nil, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if doc, err := NewFromBytes(buf.Bytes()); err != nil || !strings.Contains(renderBody(t, doc), "Hello nobody!") {
+		t.Errorf("Expected the dictionary of the template, got %v", err)
+	}
+
+	if _, err := NewTemplate(New(strings.NewReader("not a zip"), 9)); err == nil {
+		t.Error("Expected error of an invalid template")
+	}
+}
+
+func TestTemplateWarnings(t *testing.T) {
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relTypeComments + `" Target="comments.xml"/>` +
+			`</Relationships>`,
+		"word/comments.xml": `<w:comments xmlns:w="w"><w:comment w:id="1"><w:p></w:comment`,
+	}
+	tmpl, err := NewTemplate(newTestDocx(t, p("[name]"), parts).Resilient(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	result, err := tmpl.Render(Dict{"name": "ACME"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Written != int64(buf.Len()) {
+		t.Errorf("Expected %d bytes written, got %d", buf.Len(), result.Written)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0].Error(), "word/comments.xml") {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+}
@@ -0,0 +1,78 @@
+package docx
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"runtime/debug"
+	"sort"
+)
+
+const modulePath = "github.com/elblox/go-docx"
+
+// templateInfo identifies the template of generated documents
+type templateInfo struct {
+	name, version string
+}
+
+// TemplateInfo stamps generated documents with custom properties identifying their template:
+// TemplateName and TemplateVersion given here (empty ones are left out), TemplateHash which is
+// SHA-256 of names and checksums of all parts of the template, and Generator with the version
+// of this library. So it can be found out later which template produced a document
+func (doc *Docx) TemplateInfo(name, version string) *Docx {
+	doc.templateInfo = &templateInfo{name: name, version: version}
+	return doc
+}
+
+// templateProperties returns custom properties identifying the template
+func (doc *Docx) templateProperties() []customProperty {
+	if doc.templateInfo == nil {
+		return nil
+	}
+	var properties []customProperty
+	if doc.templateInfo.name != "" {
+		properties = append(properties, customProperty{name: "TemplateName", value: doc.templateInfo.name})
+	}
+	if doc.templateInfo.version != "" {
+		properties = append(properties, customProperty{name: "TemplateVersion", value: doc.templateInfo.version})
+	}
+	return append(properties,
+		customProperty{name: "TemplateHash", value: doc.templateHash()},
+		customProperty{name: "Generator", value: "go-docx " + libraryVersion()},
+	)
+}
+
+// templateHash returns a hash of the template, checksums of parts are used so nothing is decompressed
+func (doc *Docx) templateHash() string {
+	files := append(doc.zipReader.File[:0:0], doc.zipReader.File...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	h := sha256.New()
+	var b [16]byte
+	for _, f := range files {
+		h.Write([]byte(f.Name))
+		binary.BigEndian.PutUint32(b[:4], f.CRC32)
+		binary.BigEndian.PutUint64(b[4:12], f.UncompressedSize64)
+		h.Write(b[:12])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// libraryVersion returns the version of this module used by the program
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, m := range info.Deps {
+		if m.Path == modulePath {
+			if m.Replace != nil {
+				return m.Replace.Version
+			}
+			return m.Version
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,41 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplateInfo(t *testing.T) {
+	doc := newTestDocx(t, p("Hello"), nil).TemplateInfo("contract.docx", "3")
+	got := renderParts(t, doc)
+	custom := got["docProps/custom.xml"]
+	hash := doc.templateHash()
+	for _, want := range []string{
+		`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="2" name="TemplateName"><vt:lpwstr>contract.docx</vt:lpwstr></property>`,
+		`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="3" name="TemplateVersion"><vt:lpwstr>3</vt:lpwstr></property>`,
+		`<property fmtid="{D5CDD505-2E9C-101B-9397-08002B2CF9AE}" pid="4" name="TemplateHash"><vt:lpwstr>` + hash + `</vt:lpwstr></property>`,
+		`name="Generator"><vt:lpwstr>go-docx `,
+	} {
+		if !strings.Contains(custom, want) {
+			t.Errorf("%s not found in %s", want, custom)
+		}
+	}
+	if len(hash) != 64 {
+		t.Errorf("Unexpected hash %s", hash)
+	}
+	if want := `Type="` + relTypeCustomProperties + `" Target="docProps/custom.xml"`; !strings.Contains(got["_rels/.rels"], want) {
+		t.Errorf("%s not found in %s", want, got["_rels/.rels"])
+	}
+	if want := `<Override PartName="/docProps/custom.xml" ContentType="` + contentTypeCustomProperties + `">`; !strings.Contains(got[contentTypesXML], want) {
+		t.Errorf("%s not found in %s", want, got[contentTypesXML])
+	}
+	// existing properties are updated
+	parts := map[string]string{"docProps/custom.xml": custom, "_rels/.rels": got["_rels/.rels"]}
+	custom = renderParts(t, newTestDocx(t, p("Hi"), parts).TemplateInfo("contract.docx", "4"))["docProps/custom.xml"]
+	if n := strings.Count(custom, "name=\"TemplateVersion\""); n != 1 {
+		t.Errorf("TemplateVersion is set %d times in %s", n, custom)
+	}
+	if !strings.Contains(custom, "<vt:lpwstr>4</vt:lpwstr>") || strings.Contains(custom, hash) {
+		t.Errorf("Properties aren't updated in %s", custom)
+	}
+}
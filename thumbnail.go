@@ -0,0 +1,78 @@
+package docx
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+)
+
+const relTypeThumbnail = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/thumbnail"
+
+// Thumbnail sets a preview picture (JPEG or PNG) of the document, which is shown
+// by file managers and document management systems instead of the template's one
+func (doc *Docx) Thumbnail(image []byte) *Docx {
+	doc.thumbnail = image
+	return doc
+}
+
+// ThumbnailFunc sets a function which makes a preview picture of the generated document,
+// e.g. by converting its first page with an external converter. The function gets
+// the whole document without the new thumbnail
+func (doc *Docx) ThumbnailFunc(fn func(document []byte) ([]byte, error)) *Docx {
+	doc.thumbnailFunc = fn
+	return doc
+}
+
+// makeThumbnail sets a thumbnail of the rendered package
+func (doc *Docx) makeThumbnail(p *pkg) error {
+	image := doc.thumbnail
+	if doc.thumbnailFunc != nil {
+		buf := new(bytes.Buffer)
+		if _, err := p.writeTo(buf); err != nil {
+			return err
+		}
+		var err error
+		if image, err = doc.thumbnailFunc(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if image == nil {
+		return nil
+	}
+	return setThumbnail(p, image)
+}
+
+// setThumbnail stores a picture as the thumbnail of a package, the old one is removed
+func setThumbnail(p *pkg, image []byte) error {
+	contentType := http.DetectContentType(image)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return fmt.Errorf("Unsupported thumbnail type: %s", contentType)
+	}
+	name := "docProps/thumbnail." + imageTypes[contentType]
+	rels, err := p.relationships("")
+	if err != nil {
+		return err
+	}
+	ct, err := p.types()
+	if err != nil {
+		return err
+	}
+	for i := len(rels.Relationships) - 1; i >= 0; i-- {
+		rel := rels.Relationships[i]
+		if rel.Type != relTypeThumbnail {
+			continue
+		}
+		if old := resolveTarget("", rel.Target); old != name {
+			p.remove(old)
+			ct.removeOverride(old)
+		}
+		rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+		rels.modified = true
+	}
+	if err := p.set(name, image); err != nil {
+		return err
+	}
+	ct.addOverride(name, contentType)
+	rels.add(relTypeThumbnail, name)
+	return nil
+}
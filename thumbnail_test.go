@@ -0,0 +1,44 @@
+package docx
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	parts := map[string]string{
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relTypeThumbnail + `" Target="docProps/thumbnail.jpeg"/>` +
+			`</Relationships>`,
+		"docProps/thumbnail.jpeg": "\xff\xd8\xff\xe0 old thumbnail",
+	}
+	png := testPNG(t, 4, 4).Bytes()
+	doc := newTestDocx(t, `<w:p><w:r><w:t>[a]</w:t></w:r></w:p>`, parts).ReplaceDict(Dict{"a": "b"}).
+		ThumbnailFunc(func(document []byte) ([]byte, error) {
+			if http.DetectContentType(document) != "application/zip" {
+				t.Error("Thumbnail function didn't get the document")
+			}
+			return png, nil
+		})
+	got := renderParts(t, doc)
+	if _, ok := got["docProps/thumbnail.jpeg"]; ok {
+		t.Error("Old thumbnail wasn't removed")
+	}
+	if got["docProps/thumbnail.png"] != string(png) {
+		t.Error("Thumbnail wasn't added")
+	}
+	want := `Type="` + relTypeThumbnail + `" Target="docProps/thumbnail.png"`
+	if !strings.Contains(got["_rels/.rels"], want) || strings.Contains(got["_rels/.rels"], "thumbnail.jpeg") {
+		t.Errorf("got: %s\nwant: %s", got["_rels/.rels"], want)
+	}
+	if want := `<Override PartName="/docProps/thumbnail.png" ContentType="image/png">`; !strings.Contains(got[contentTypesXML], want) {
+		t.Errorf("%s not found in %s", want, got[contentTypesXML])
+	}
+	doc = newTestDocx(t, `<w:p></w:p>`, nil).Thumbnail([]byte("not an image"))
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected an error of unsupported thumbnail")
+	}
+}
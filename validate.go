@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists problems of data which doesn't match the template
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "Invalid data of the template: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the dictionary against the schema of the template (see Schema) before
+// the document is rendered, so missing or mistyped values don't produce half-filled documents.
+// It returns *ValidationError with all problems found
+func (doc *Docx) Validate() error {
+	schema, err := doc.templateSchema()
+	if err != nil {
+		return err
+	}
+	if doc.invoice != nil {
+		// rows of items and rates are filled by the invoice
+		delete(schema.fields, "item")
+		delete(schema.fields, "rate")
+	}
+	global := doc.globalScope(nil)
+	var problems []string
+	records, prefix := doc.labels, "labels"
+	if doc.envelopes != nil {
+		records, prefix = doc.envelopes, "envelopes"
+	}
+	if records == nil {
+		problems = validateFields(global, schema, "")
+	}
+	for i, record := range records {
+		problems = append(problems, validateFields(global.child(record), schema, prefix+"["+strconv.Itoa(i)+"].")...)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateFields checks values of fields of an object, path is a prefix of their names in messages
+func validateFields(s *scope, schema *schemaField, path string) []string {
+	names := make([]string, 0, len(schema.fields))
+	for name := range schema.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, name := range names {
+		f := schema.fields[name]
+		v, ok := s.lookup(name)
+		if !ok {
+			if f.required {
+				problems = append(problems, "missing "+path+name)
+			}
+			continue
+		}
+		problems = append(problems, validateValue(s, v, f, path+name)...)
+	}
+	return problems
+}
+
+// validateValue checks if a value has the type of a field
+func validateValue(s *scope, v interface{}, f *schemaField, path string) []string {
+	switch f.typ {
+	case "number":
+		if _, ok := toNumber(v); !ok {
+			return []string{fmt.Sprintf("%s must be a number, not %T", path, v)}
+		}
+	case "object":
+		dict, ok := toDict(v)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an object, not %T", path, v)}
+		}
+		return validateFields(&scope{doc: s.doc, dict: dict}, f, path+".")
+	case "array":
+		records, ok := toRecords(v)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a list of objects, not %T", path, v)}
+		}
+		var problems []string
+		for i, record := range records {
+			// fields of items can be also taken from the enclosing scope
+			problems = append(problems, validateFields(s.child(record), f, path+"["+strconv.Itoa(i)+"].")...)
+		}
+		return problems
+	}
+	return nil
+}
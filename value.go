@@ -0,0 +1,169 @@
+package docx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value is a content which can be put in place of a variable:
+// Text, RichText or Image
+type Value interface {
+	// runs returns <w:r> elements representing a value,
+	// rPr is formatting of a run where the variable was found (it may be nil)
+	runs(ctx *renderContext, rPr *node) ([]*node, error)
+}
+
+// blockValue is a Value which consists of paragraphs or tables (like BuildingBlock),
+// it replaces the whole paragraph when nothing else is there
+type blockValue interface {
+	Value
+	blocks(ctx *renderContext) ([]*node, error)
+}
+
+// Valuer is implemented by types which control how they are rendered
+// when they are used as values in Dict
+type Valuer interface {
+	DocxValue() (Value, error)
+}
+
+// Text is a plain text value which keeps formatting of the replaced variable
+type Text string
+
+func (t Text) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	return []*node{newRun(rPr, string(t))}, nil
+}
+
+// RichText is a sequence of differently formatted pieces of text
+type RichText []Run
+
+// Run is a piece of text with the same formatting.
+// Formatting not set here is inherited from the replaced variable
+type Run struct {
+	Text      string
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+	// Color is a hex RGB value like "FF0000"
+	Color string
+	// Highlight is one of Word highlight colors like "yellow"
+	Highlight string
+	Font      string
+	// Size of a font in points
+	Size float64
+}
+
+func (rt RichText) runs(ctx *renderContext, rPr *node) ([]*node, error) {
+	runs := make([]*node, 0, len(rt))
+	for _, run := range rt {
+		runs = append(runs, newRun(run.properties(rPr), run.Text))
+	}
+	return runs, nil
+}
+
+// rPrOrder is an order of <w:rPr> child elements required by the schema
+var rPrOrder = []string{
+	"w:rStyle", "w:rFonts", "w:b", "w:bCs", "w:i", "w:iCs", "w:caps", "w:smallCaps",
+	"w:strike", "w:dstrike", "w:outline", "w:shadow", "w:emboss", "w:imprint",
+	"w:noProof", "w:snapToGrid", "w:vanish", "w:webHidden", "w:color", "w:spacing",
+	"w:w", "w:kern", "w:position", "w:sz", "w:szCs", "w:highlight", "w:u", "w:effect",
+	"w:bdr", "w:shd", "w:fitText", "w:vertAlign", "w:rtl", "w:cs", "w:em", "w:lang",
+	"w:eastAsianLayout", "w:specVanish", "w:oMath",
+}
+
+// pPrOrder is an order of <w:pPr> child elements required by the schema
+var pPrOrder = []string{
+	"w:pStyle", "w:keepNext", "w:keepLines", "w:pageBreakBefore", "w:framePr", "w:widowControl",
+	"w:numPr", "w:suppressLineNumbers", "w:pBdr", "w:shd", "w:tabs", "w:suppressAutoHyphens",
+	"w:kinsoku", "w:wordWrap", "w:overflowPunct", "w:topLinePunct", "w:autoSpaceDE", "w:autoSpaceDN",
+	"w:bidi", "w:adjustRightInd", "w:snapToGrid", "w:spacing", "w:ind", "w:contextualSpacing",
+	"w:mirrorIndents", "w:suppressOverlap", "w:jc", "w:textDirection", "w:textAlignment",
+	"w:textboxTightWrap", "w:outlineLvl", "w:divId", "w:cnfStyle", "w:rPr", "w:sectPr", "w:pPrChange",
+}
+
+// properties returns <w:rPr> element based on inherited formatting
+func (run Run) properties(inherited *node) *node {
+	rPr := elem("w:rPr")
+	if inherited != nil {
+		rPr = inherited.clone()
+	}
+	if run.Font != "" {
+		rPr.setChild(elem("w:rFonts", "w:ascii", run.Font, "w:hAnsi", run.Font, "w:cs", run.Font), rPrOrder)
+	}
+	if run.Bold {
+		rPr.setChild(elem("w:b"), rPrOrder)
+		rPr.setChild(elem("w:bCs"), rPrOrder)
+	}
+	if run.Italic {
+		rPr.setChild(elem("w:i"), rPrOrder)
+		rPr.setChild(elem("w:iCs"), rPrOrder)
+	}
+	if run.Strike {
+		rPr.setChild(elem("w:strike"), rPrOrder)
+	}
+	if run.Color != "" {
+		rPr.setChild(elem("w:color", "w:val", run.Color), rPrOrder)
+	}
+	if run.Size > 0 {
+		// font size is stored in half-points
+		size := strconv.Itoa(int(run.Size*2 + 0.5))
+		rPr.setChild(elem("w:sz", "w:val", size), rPrOrder)
+		rPr.setChild(elem("w:szCs", "w:val", size), rPrOrder)
+	}
+	if run.Highlight != "" {
+		rPr.setChild(elem("w:highlight", "w:val", run.Highlight), rPrOrder)
+	}
+	if run.Underline {
+		rPr.setChild(elem("w:u", "w:val", "single"), rPrOrder)
+	}
+	return rPr
+}
+
+// newRun creates <w:r> element with given formatting and text, tabs become <w:tab> elements
+func newRun(rPr *node, text string) *node {
+	r := elem("w:r")
+	if rPr != nil {
+		r.append(rPr.clone())
+	}
+	for i, s := range strings.Split(text, "\t") {
+		if i > 0 {
+			r.append(elem("w:tab"))
+		}
+		if s != "" || text == "" {
+			t := elem("w:t")
+			setRunText(t, s)
+			r.append(t)
+		}
+	}
+	return r
+}
+
+// toValue converts a dictionary value to something which can be rendered
+func toValue(v interface{}) (Value, error) {
+	switch v := v.(type) {
+	case Value:
+		return v, nil
+	case Valuer:
+		value, err := v.DocxValue()
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return Text(""), nil
+		}
+		return value, nil
+	case string:
+		return Text(v), nil
+	case nil:
+		return Text(""), nil
+	case float64:
+		return Text(formatNumber(v)), nil
+	case float32:
+		return Text(formatNumber(float64(v))), nil
+	case fmt.Stringer:
+		return Text(v.String()), nil
+	default:
+		return Text(fmt.Sprint(v)), nil
+	}
+}
@@ -0,0 +1,147 @@
+package docx
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+type customer struct {
+	name string
+	vip  bool
+}
+
+func (c customer) DocxValue() (Value, error) {
+	if c.name == "" {
+		return nil, errors.New("Customer without a name")
+	}
+	if c.vip {
+		return RichText{{Text: c.name, Bold: true}, {Text: " (VIP)", Color: "FF0000", Size: 8}}, nil
+	}
+	return Text(c.name), nil
+}
+
+func TestValuer(t *testing.T) {
+	body := `<w:p><w:r><w:rPr><w:i/></w:rPr><w:t>Hello [customer]!</w:t></w:r></w:p>`
+	got := renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": customer{name: "Jane", vip: true}}))
+	want := `<w:p><w:r><w:rPr><w:i></w:i></w:rPr><w:t xml:space="preserve">Hello </w:t></w:r>` +
+		`<w:r><w:rPr><w:b></w:b><w:bCs></w:bCs><w:i></w:i></w:rPr><w:t>Jane</w:t></w:r>` +
+		`<w:r><w:rPr><w:i></w:i><w:color w:val="FF0000"></w:color><w:sz w:val="16"></w:sz><w:szCs w:val="16"></w:szCs></w:rPr><w:t xml:space="preserve"> (VIP)</w:t></w:r>` +
+		`<w:r><w:rPr><w:i></w:i></w:rPr><w:t>!</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+
+	got = renderBody(t, newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": customer{name: "Joe"}}))
+	want = `<w:p><w:r><w:rPr><w:i></w:i></w:rPr><w:t>Hello Joe!</w:t></w:r></w:p>`
+	if got != want {
+		t.Errorf("Unexpected body:\n got: %s\nwant: %s", got, want)
+	}
+
+	_, err := newTestDocx(t, body, nil).ReplaceDict(Dict{"customer": customer{}}).WriteTo(new(bytes.Buffer))
+	if err == nil {
+		t.Error("Expected error from DocxValue")
+	}
+}
+
+// testPNG returns a blank PNG picture of given size
+func testPNG(t *testing.T, width, height int) *bytes.Buffer {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func TestImage(t *testing.T) {
+	buf := testPNG(t, 20, 10)
+	body := `<w:p><w:r><w:t>Logo: [logo]</w:t></w:r></w:p>`
+	doc := newTestDocx(t, body, nil).ReplaceDict(Dict{"logo": Image{Data: buf.Bytes(), Width: 2 * Centimeter}})
+	parts := renderParts(t, doc)
+	if parts["word/media/image1.png"] != buf.String() {
+		t.Error("Image wasn't stored in the archive")
+	}
+	for _, s := range []string{`<wp:extent cx="720000" cy="360000">`, `r:embed="rId1"`, `xmlns:wp=`, `xmlns:r=`} {
+		if !strings.Contains(parts[documentXML], s) {
+			t.Errorf("Can't find %s in %s", s, parts[documentXML])
+		}
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], `Target="media/image1.png"`) {
+		t.Errorf("Relationship wasn't added: %s", parts["word/_rels/document.xml.rels"])
+	}
+	if !strings.Contains(parts[contentTypesXML], `Extension="png"`) {
+		t.Errorf("Content type wasn't added: %s", parts[contentTypesXML])
+	}
+}
+
+func TestImageSizing(t *testing.T) {
+	data := testPNG(t, 200, 100).Bytes()
+	for _, test := range []struct {
+		img  Image
+		want []string
+	}{
+		{Image{Data: data, DPI: 300}, []string{`<wp:extent cx="609600" cy="304800">`}},
+		{Image{Data: data, Height: Inch}, []string{`<wp:extent cx="1828800" cy="914400">`}},
+		{Image{Data: data, Width: 4 * Centimeter, Crop: Crop{Left: 0.25, Right: 0.25}}, []string{
+			`<wp:extent cx="1440000" cy="1440000">`,
+			`<pic:blipFill><a:blip r:embed="rId1"></a:blip><a:srcRect l="25000" r="25000"></a:srcRect><a:stretch>`,
+			`<a:ext cx="1440000" cy="1440000">`,
+		}},
+	} {
+		got := renderBody(t, newTestDocx(t, p("[logo]"), nil).ReplaceDict(Dict{"logo": test.img}))
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("Can't find %s in %s", want, got)
+			}
+		}
+	}
+	doc := newTestDocx(t, p("[logo]"), nil).ReplaceDict(Dict{"logo": Image{Data: data, Crop: Crop{Top: 0.5, Bottom: 0.5}}})
+	if _, err := doc.WriteTo(new(strings.Builder)); err == nil {
+		t.Error("Expected error for an empty crop")
+	}
+}
+
+func TestPlaceSignature(t *testing.T) {
+	body := p("Customer: [customer_sign]") +
+		`<w:p><w:r><w:t xml:space="preserve">Seller: </w:t></w:r><w:bookmarkStart w:id="0" w:name="seller_sign"/>` +
+		`<w:bookmarkEnd w:id="0"/></w:p>`
+	img := Image{Data: testPNG(t, 20, 10).Bytes()}
+	doc := newTestDocx(t, body, nil).
+		ReplaceDict(Dict{"customer_sign": "missing"}).
+		PlaceSignature("customer_sign", img).
+		PlaceSignature("seller_sign", img, "Signed on 2024-05-01")
+	got := renderBody(t, doc)
+	if !strings.Contains(got, "missing") || strings.Count(got, "<w:drawing>") != 1 {
+		t.Errorf("Values of the dictionary should take precedence: %s", got)
+	}
+	want := `<w:bookmarkStart w:id="0" w:name="seller_sign"></w:bookmarkStart><w:r><w:drawing>`
+	if !strings.Contains(got, want) || !strings.Contains(got, `<w:r><w:br></w:br></w:r><w:r><w:t>Signed on 2024-05-01</w:t></w:r>`) {
+		t.Errorf("Signature wasn't placed at the bookmark: %s", got)
+	}
+
+	got = renderBody(t, newTestDocx(t, body, nil).PlaceSignature("customer_sign", img))
+	if !strings.Contains(got, `Customer: </w:t></w:r><w:r><w:drawing>`) {
+		t.Errorf("Signature wasn't placed at the placeholder: %s", got)
+	}
+}
+
+func TestFloatingImage(t *testing.T) {
+	stamp := FloatingImage{Image: Image{Data: testPNG(t, 20, 10).Bytes()}, X: 2 * Centimeter, Y: Inch, Wrap: WrapBehind}
+	got := renderBody(t, newTestDocx(t, p("Approved [stamp]"), nil).ReplaceDict(Dict{"stamp": stamp}))
+	for _, want := range []string{
+		`<w:r><w:drawing><wp:anchor distT="0" distB="0" distL="114300" distR="114300" simplePos="0" relativeHeight="1" behindDoc="1"`,
+		`<wp:positionH relativeFrom="page"><wp:posOffset>720000</wp:posOffset></wp:positionH>`,
+		`<wp:positionV relativeFrom="page"><wp:posOffset>914400</wp:posOffset></wp:positionV>` +
+			`<wp:extent cx="190500" cy="95250"></wp:extent><wp:effectExtent l="0" t="0" r="0" b="0"></wp:effectExtent>` +
+			`<wp:wrapNone></wp:wrapNone><wp:docPr id="1"`,
+		`</a:graphic></wp:anchor></w:drawing></w:r>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got: %s\nwant: %s", got, want)
+		}
+	}
+}
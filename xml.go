@@ -0,0 +1,317 @@
+package docx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// node is an element or a piece of character data of a parsed XML part
+type node struct {
+	// tag is a qualified element name like "w:p", it's empty for non-element nodes
+	tag      string
+	attr     []xml.Attr
+	children []*node
+	parent   *node
+	// data keeps CharData, Comment, ProcInst or Directive of non-element nodes
+	data xml.Token
+}
+
+// parse reads XML part into a tree of nodes
+// Names are stored with their prefixes exactly as they were read
+func parse(r io.Reader) (*node, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return scan(data)
+}
+
+// parseFragment reads a piece of XML (which doesn't need to declare namespaces)
+// and returns its top level nodes
+func parseFragment(s string) ([]*node, error) {
+	root, err := parse(strings.NewReader(s))
+	if err != nil {
+		return nil, err
+	}
+	nodes := root.children
+	for _, n := range nodes {
+		n.parent = nil
+	}
+	return nodes, nil
+}
+
+// encode writes a tree of nodes as XML
+func encode(w io.Writer, root *node) error {
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	defer func() {
+		bw.Reset(nil)
+		writerPool.Put(bw)
+	}()
+	root.write(bw)
+	return bw.Flush()
+}
+
+// bytes returns XML representation of a tree
+func (n *node) bytes() ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := encode(buf, n); err != nil {
+		return nil, err
+	}
+	// the buffer goes back to the pool, so its content is copied
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// elem creates an element with given attributes passed as name, value pairs
+func elem(tag string, attrs ...string) *node {
+	n := &node{tag: tag}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		n.setAttr(attrs[i], attrs[i+1])
+	}
+	return n
+}
+
+// textNode creates character data node
+func textNode(s string) *node {
+	return &node{data: xml.CharData(s)}
+}
+
+// is checks if node is an element with given qualified name
+func (n *node) is(tag string) bool {
+	return n.data == nil && n.tag == tag
+}
+
+func (n *node) attrValue(name string) string {
+	for _, a := range n.attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) hasAttr(name string) bool {
+	for _, a := range n.attr {
+		if a.Name.Local == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) setAttr(name, value string) {
+	for i, a := range n.attr {
+		if a.Name.Local == name {
+			n.attr[i].Value = value
+			return
+		}
+	}
+	n.attr = append(n.attr, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+func (n *node) removeAttr(name string) {
+	for i, a := range n.attr {
+		if a.Name.Local == name {
+			n.attr = append(n.attr[:i], n.attr[i+1:]...)
+			return
+		}
+	}
+}
+
+// child returns first child element with given name
+func (n *node) child(tag string) *node {
+	for _, c := range n.children {
+		if c.is(tag) {
+			return c
+		}
+	}
+	return nil
+}
+
+// elements returns child elements with given name
+func (n *node) elements(tag string) []*node {
+	var found []*node
+	for _, c := range n.children {
+		if c.is(tag) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// find returns all descendant elements with given name in document order
+func (n *node) find(tag string) []*node {
+	var found []*node
+	n.walk(func(c *node) bool {
+		if c.is(tag) {
+			found = append(found, c)
+		}
+		return true
+	})
+	return found
+}
+
+// walk visits all descendants of a node in document order,
+// children of a node are skipped if fn returns false
+func (n *node) walk(fn func(*node) bool) {
+	for i := 0; i < len(n.children); i++ {
+		c := n.children[i]
+		if fn(c) {
+			c.walk(fn)
+		}
+	}
+}
+
+// ancestor returns the closest ancestor with given name
+func (n *node) ancestor(tag string) *node {
+	for p := n.parent; p != nil; p = p.parent {
+		if p.is(tag) {
+			return p
+		}
+	}
+	return nil
+}
+
+// text returns all character data of a node and its descendants
+func (n *node) text() string {
+	if cd, ok := n.data.(xml.CharData); ok {
+		return string(cd)
+	}
+	var sb strings.Builder
+	for _, c := range n.children {
+		sb.WriteString(c.text())
+	}
+	return sb.String()
+}
+
+// setText replaces all children of a node with given character data
+func (n *node) setText(s string) {
+	n.children = nil
+	if s != "" {
+		n.append(textNode(s))
+	}
+}
+
+func (n *node) clone() *node {
+	c := &node{tag: n.tag, data: n.data}
+	if n.attr != nil {
+		c.attr = make([]xml.Attr, len(n.attr))
+		copy(c.attr, n.attr)
+	}
+	for _, child := range n.children {
+		c.append(child.clone())
+	}
+	return c
+}
+
+func (n *node) append(children ...*node) {
+	for _, c := range children {
+		c.parent = n
+	}
+	n.children = append(n.children, children...)
+}
+
+// insert puts children at given position
+func (n *node) insert(i int, children ...*node) {
+	for _, c := range children {
+		c.parent = n
+	}
+	tail := make([]*node, 0, len(children)+len(n.children)-i)
+	tail = append(tail, children...)
+	tail = append(tail, n.children[i:]...)
+	n.children = append(n.children[:i], tail...)
+}
+
+// index returns position of a node among its siblings
+func (n *node) index() int {
+	if n.parent == nil {
+		return -1
+	}
+	for i, c := range n.parent.children {
+		if c == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// remove detaches a node from its parent
+func (n *node) remove() {
+	if i := n.index(); i != -1 {
+		n.parent.children = append(n.parent.children[:i], n.parent.children[i+1:]...)
+	}
+	n.parent = nil
+}
+
+// replace puts given nodes in place of n
+func (n *node) replace(nodes ...*node) {
+	parent, i := n.parent, n.index()
+	if i == -1 {
+		return
+	}
+	n.remove()
+	parent.insert(i, nodes...)
+}
+
+// root returns the top-most node of a tree
+func (n *node) root() *node {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+// documentElement returns the first element of a parsed part
+func (n *node) documentElement() *node {
+	for _, c := range n.children {
+		if c.data == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// setChild replaces child element with the same name as c
+// or inserts it at the position required by the schema in order
+func (n *node) setChild(c *node, order []string) {
+	if old := n.child(c.tag); old != nil {
+		old.replace(c)
+		return
+	}
+	pos := indexOf(order, c.tag)
+	for i, child := range n.children {
+		if pos != -1 && indexOf(order, child.tag) > pos {
+			n.insert(i, c)
+			return
+		}
+	}
+	n.append(c)
+}
+
+// removeChild removes all child elements with given name
+func (n *node) removeChild(tag string) {
+	for c := n.child(tag); c != nil; c = n.child(tag) {
+		c.remove()
+	}
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// escape returns s with XML special characters escaped
+func escape(s string) string {
+	buf := new(bytes.Buffer)
+	xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}